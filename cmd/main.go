@@ -3,15 +3,21 @@ package main
 import (
 	"backend/internal/app/services"
 	"backend/internal/config"
-	"backend/internal/domain/models"
+	"backend/internal/domain/money"
 	"backend/internal/infra/database/mysql"
 	"backend/internal/infra/gorm/repositories"
 	"backend/internal/infra/http/handlers"
 	"backend/internal/infra/http/routes"
+	"backend/internal/infra/http/routes/middleware"
+	"backend/internal/infra/mail"
+	"backend/internal/infra/payments"
+	"context"
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -20,48 +26,157 @@ func main() {
 
 	// 2. Initialize Database
 	db := mysql.InitDB(cfg)
+	if err := repositories.RegisterTenantScope(db); err != nil {
+		log.Fatalf("gagal memasang tenant scope: %v", err)
+	}
+
+	// 3. Auto Migrate All Models (kolom uang lama dikonversi ke minor unit terlebih dahulu)
+	money.SetBaseCurrency(cfg.BaseCurrency)
+	mysql.MigrateMoneyColumns(db)
+	mysql.MigrateTenants(db, cfg.DefaultTenant)
+	mysql.MigrateProperties(db)
+	mysql.MigrateRoomTypes(db)
+	mysql.MigrateEmailVerification(db)
+	mysql.AutoMigrate(db, mysql.Models()...)
+	mysql.BackfillBookingCurrency(db)
+	mysql.BackfillPaymentLedger(db)
+	mysql.BackfillPaymentDueAt(db, time.Duration(cfg.PaymentDeadlineMinutes)*time.Minute)
+	mysql.BackfillBookingParty(db)
+
+	// 4. Shared Components (dipakai semua tenant)
+	clock := services.NewSystemClock()
+	eventPublisher := services.NewLogEventPublisher()
+
+	// Payment provider dipilih lewat PAYMENT_PROVIDER
+	var paymentProvider services.PaymentProvider
+	switch cfg.PaymentProvider {
+	case "mock":
+		paymentProvider = payments.NewMockProvider(cfg.PaymentWebhookSecret, clock)
+	default:
+		log.Fatalf("payment provider %q tidak dikenal", cfg.PaymentProvider)
+	}
+
+	// Pengirim email dipilih lewat MAIL_DRIVER
+	var mailSender services.MailSender
+	switch cfg.MailDriver {
+	case "smtp":
+		mailSender = mail.NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	case "file":
+		mailSender = mail.NewFileSender(cfg.MailOutboxDir, cfg.MailFrom)
+	case "memory":
+		mailSender = mail.NewMemorySender()
+	default:
+		log.Fatalf("mail driver %q tidak dikenal", cfg.MailDriver)
+	}
+
+	// 5. Start Background Jobs (koneksi tanpa tenant: memproses booking semua tenant)
+	jobBookingRepo := repositories.NewGormBookingRepository(db)
+	jobTransactor := repositories.NewGormTransactor(db)
+
+	holdSweeper := services.NewHoldSweeper(jobBookingRepo, jobTransactor, clock, time.Duration(cfg.HoldSweepIntervalSeconds)*time.Second)
+	go holdSweeper.Run(context.Background())
 
-	// 3. Auto Migrate All Models
-	mysql.AutoMigrate(db,
-		&models.User{},
-		&models.Room{},
-		&models.RoomImage{},
-		&models.Booking{},
-		&models.Review{},
-	)
+	paymentDeadlineJob := services.NewPaymentDeadlineJob(jobBookingRepo, jobTransactor, eventPublisher, clock, time.Duration(cfg.PaymentDeadlineIntervalSeconds)*time.Second)
+	go paymentDeadlineJob.Run(context.Background())
 
-	// 4. Initialize Repositories
+	tokenCleanupJob := services.NewTokenCleanupJob(repositories.NewGormAuthTokenRepository(db), clock, time.Hour)
+	go tokenCleanupJob.Run(context.Background())
+
+	// 6. Create Fiber App
+	app := fiber.New()
+
+	// 7. Add Middleware
+	app.Use(logger.New())
+
+	// 8. Setup Routes (setiap tenant punya repository, service, dan handler sendiri)
+	tenantRouter := routes.NewTenantRouter(func(tenantID uint) *fiber.App {
+		return newTenantApp(repositories.WithTenant(db, tenantID), cfg, clock, eventPublisher, paymentProvider, mailSender)
+	})
+
+	// Webhook pembayaran diteruskan ke tenant pemilik pembayarannya (dicari lewat koneksi tanpa tenant)
+	webhookPaymentService := services.NewPaymentService(jobBookingRepo, repositories.NewGormPaymentRepository(db), jobTransactor, paymentProvider, eventPublisher, clock)
+	app.Post("/api/payments/webhook", middleware.PaymentWebhookTenant(webhookPaymentService, handlers.PaymentSignatureHeader), tenantRouter.Handle)
+
+	tenantService := services.NewTenantService(repositories.NewGormTenantRepository(db), cfg)
+	app.Use(middleware.TenantMiddleware(tenantService, cfg))
+	app.Use(tenantRouter.Handle)
+
+	// 9. Start Server
+	port := ":" + cfg.ServerPort
+	log.Printf("🚀 Server berjalan di http://localhost%s", port)
+	if err := app.Listen(port); err != nil {
+		log.Fatalf("❌ Gagal menjalankan server: %v", err)
+	}
+}
+
+// newTenantApp membangun aplikasi satu tenant. db harus sudah terikat tenant (repositories.WithTenant)
+// sehingga semua repository di bawah ini otomatis ter-scope ke tenant tersebut.
+func newTenantApp(db *gorm.DB, cfg *config.Config, clock services.Clock, eventPublisher services.EventPublisher, paymentProvider services.PaymentProvider, mailSender services.MailSender) *fiber.App {
+	// Initialize Repositories
 	userRepo := repositories.NewGormRepository(db)
+	authTokenRepo := repositories.NewGormAuthTokenRepository(db)
+	mfaRepo := repositories.NewGormMFARepository(db)
+	roleRepo := repositories.NewGormRoleRepository(db)
 	roomRepo := repositories.NewGormRoomRepository(db)
+	roomTypeRepo := repositories.NewGormRoomTypeRepository(db)
+	propertyRepo := repositories.NewGormPropertyRepository(db)
 	bookingRepo := repositories.NewGormBookingRepository(db)
 	roomImageRepo := repositories.NewGormRoomImageRepository(db)
 	reviewRepo := repositories.NewGormReviewRepository(db)
+	ratePlanRepo := repositories.NewGormRatePlanRepository(db)
+	promoCodeRepo := repositories.NewGormPromoCodeRepository(db)
+	taxFeeRepo := repositories.NewGormTaxFeeRepository(db)
+	exchangeRateRepo := repositories.NewGormExchangeRateRepository(db)
+	paymentRepo := repositories.NewGormPaymentRepository(db)
+	cancellationPolicyRepo := repositories.NewGormCancellationPolicyRepository(db)
+	amenityRepo := repositories.NewGormAmenityRepository(db)
+	stayRestrictionRepo := repositories.NewGormStayRestrictionRepository(db)
+	transactor := repositories.NewGormTransactor(db)
 
-	// 5. Initialize Services
-	authService := services.NewAuthService(userRepo, cfg)
-	roomService := services.NewRoomService(roomRepo, roomImageRepo)
-	bookingService := services.NewBookingService(bookingRepo, roomRepo, reviewRepo)
+	// Initialize Services
+	mfaService := services.NewMFAService(mfaRepo, userRepo, roleRepo, authTokenRepo, cfg, clock)
+	authService := services.NewAuthService(userRepo, authTokenRepo, mfaService, mailSender, cfg, clock)
+	roleService := services.NewRoleService(roleRepo, userRepo, authService)
+	roomService := services.NewRoomService(roomRepo, roomImageRepo, roomTypeRepo, propertyRepo, amenityRepo, bookingRepo, ratePlanRepo, stayRestrictionRepo, clock)
+	roomTypeService := services.NewRoomTypeService(roomTypeRepo, roomRepo, cancellationPolicyRepo, propertyRepo, clock)
+	propertyService := services.NewPropertyService(propertyRepo, roomTypeRepo, roomRepo, userRepo)
+	pricingService := services.NewPricingService(roomTypeRepo, ratePlanRepo, promoCodeRepo, taxFeeRepo, exchangeRateRepo, cfg, clock)
+	paymentService := services.NewPaymentService(bookingRepo, paymentRepo, transactor, paymentProvider, eventPublisher, clock)
+	bookingService := services.NewBookingService(bookingRepo, roomRepo, reviewRepo, userRepo, transactor, pricingService, paymentService, cfg, clock)
 	reviewService := services.NewReviewService(reviewRepo, bookingRepo)
+	ratePlanService := services.NewRatePlanService(ratePlanRepo, roomTypeRepo, cancellationPolicyRepo)
+	promoCodeService := services.NewPromoCodeService(promoCodeRepo)
+	taxFeeService := services.NewTaxFeeService(taxFeeRepo, propertyRepo)
+	exchangeRateService := services.NewExchangeRateService(exchangeRateRepo)
+	cancellationPolicyService := services.NewCancellationPolicyService(cancellationPolicyRepo)
+	amenityService := services.NewAmenityService(amenityRepo)
+	stayRestrictionService := services.NewStayRestrictionService(stayRestrictionRepo, roomTypeRepo, roomRepo)
 
-	// 6. Initialize Handlers
+	// Initialize Handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	roomHandler := handlers.NewRoomHandler(roomService)
+	roomTypeHandler := handlers.NewRoomTypeHandler(roomTypeService, pricingService, exchangeRateService)
 	bookingHandler := handlers.NewBookingHandler(bookingService)
 	reviewHandler := handlers.NewReviewHandler(reviewService)
+	ratePlanHandler := handlers.NewRatePlanHandler(ratePlanService, roomTypeService)
+	promoCodeHandler := handlers.NewPromoCodeHandler(promoCodeService)
+	taxFeeHandler := handlers.NewTaxFeeHandler(taxFeeService)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateService)
+	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	cancellationPolicyHandler := handlers.NewCancellationPolicyHandler(cancellationPolicyService)
+	propertyHandler := handlers.NewPropertyHandler(propertyService)
+	amenityHandler := handlers.NewAmenityHandler(amenityService)
+	stayRestrictionHandler := handlers.NewStayRestrictionHandler(stayRestrictionService)
+	mfaHandler := handlers.NewMFAHandler(mfaService)
+	roleHandler := handlers.NewRoleHandler(roleService)
 
-	// 7. Create Fiber App
-	app := fiber.New()
-
-	// 8. Add Middleware
-	app.Use(logger.New())
-
-	// 9. Setup Routes
-	routes.SetupRoutes(app, authHandler, roomHandler, bookingHandler, reviewHandler, cfg)
-
-	// 10. Start Server
-	port := ":" + cfg.ServerPort
-	log.Printf("🚀 Server berjalan di http://localhost%s", port)
-	if err := app.Listen(port); err != nil {
-		log.Fatalf("❌ Gagal menjalankan server: %v", err)
+	// Role bawaan (admin, member, front_desk, housekeeping, accounting) dibuat sekali per tenant
+	if err := roleService.SeedDefaultRoles(); err != nil {
+		log.Printf("gagal membuat role bawaan: %v", err)
 	}
+
+	// Setup Routes
+	app := fiber.New()
+	routes.SetupRoutes(app, authHandler, roomHandler, roomTypeHandler, bookingHandler, reviewHandler, ratePlanHandler, promoCodeHandler, taxFeeHandler, exchangeRateHandler, paymentHandler, cancellationPolicyHandler, propertyHandler, amenityHandler, stayRestrictionHandler, mfaHandler, roleHandler, authService, roleService, cfg)
+	return app
 }
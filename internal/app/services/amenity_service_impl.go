@@ -0,0 +1,75 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type amenityServiceImpl struct {
+	amenityRepo repositories.AmenityRepository
+}
+
+func NewAmenityService(aRepo repositories.AmenityRepository) AmenityService {
+	return &amenityServiceImpl{amenityRepo: aRepo}
+}
+
+// validateAmenity: Validasi fasilitas sebelum disimpan; kode diseragamkan huruf besar agar cocok dengan filter
+func validateAmenity(amenity *models.Amenity) error {
+	amenity.Code = strings.ToUpper(strings.TrimSpace(amenity.Code))
+	amenity.Name = strings.TrimSpace(amenity.Name)
+	if amenity.Code == "" || amenity.Name == "" || strings.Contains(amenity.Code, ",") {
+		return errors.New("data fasilitas tidak lengkap atau tidak valid")
+	}
+	return nil
+}
+
+// GetAmenities: Mengambil katalog fasilitas
+func (s *amenityServiceImpl) GetAmenities(pagination *models.Pagination) ([]models.Amenity, error) {
+	return s.amenityRepo.FindAll(pagination)
+}
+
+// GetAmenityByID: Mengambil detail fasilitas
+func (s *amenityServiceImpl) GetAmenityByID(amenityID uint) (*models.Amenity, error) {
+	amenity, err := s.amenityRepo.FindByID(amenityID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrAmenityNotFound
+		}
+		return nil, err
+	}
+	return amenity, nil
+}
+
+// CreateAmenity: Menambah fasilitas ke katalog (Admin Only)
+func (s *amenityServiceImpl) CreateAmenity(amenity *models.Amenity) (*models.Amenity, error) {
+	if err := validateAmenity(amenity); err != nil {
+		return nil, err
+	}
+	if err := s.amenityRepo.Create(amenity); err != nil {
+		return nil, err
+	}
+	return amenity, nil
+}
+
+// UpdateAmenity: Mengubah fasilitas (Admin Only)
+func (s *amenityServiceImpl) UpdateAmenity(amenity *models.Amenity) (*models.Amenity, error) {
+	if err := validateAmenity(amenity); err != nil {
+		return nil, err
+	}
+	if err := s.amenityRepo.Update(amenity); err != nil {
+		return nil, err
+	}
+	return amenity, nil
+}
+
+// DeleteAmenity: Menghapus fasilitas sekaligus melepasnya dari semua kamar (Admin Only)
+func (s *amenityServiceImpl) DeleteAmenity(amenityID uint) error {
+	if _, err := s.GetAmenityByID(amenityID); err != nil {
+		return err
+	}
+	return s.amenityRepo.Delete(amenityID)
+}
@@ -2,9 +2,30 @@ package services
 
 import (
 	"backend/internal/domain/models"
+	"time"
 )
 
 type AuthService interface {
 	Register(user *models.User) (*models.User, error)
-	Login(username, password string) (string, *models.User, error)
+	// Login memverifikasi password; user dengan 2FA aktif mendapat MFA challenge alih-alih token
+	Login(username, password string) (*models.LoginResult, error)
+	// VerifyMFALogin menukar MFA challenge dan kode TOTP/recovery code dengan pasangan token
+	VerifyMFALogin(challengeToken, code string) (*models.TokenPair, *models.User, error)
+	// Refresh merotasi refresh token dan menerbitkan pasangan token baru
+	Refresh(refreshToken string) (*models.TokenPair, error)
+	// Logout mencabut access token (jti) beserta seluruh refresh token sesinya
+	Logout(userID uint, jti string, expiresAt time.Time) error
+	// IsTokenRevoked mengecek daftar pencabutan access token (dipakai JWTMiddleware)
+	IsTokenRevoked(jti string) (bool, error)
+	// RevokeSessions mencabut semua sesi login user (mis. setelah role atau password berubah)
+	RevokeSessions(userID uint) error
+
+	// ForgotPassword mengirim tautan reset password bila email terdaftar; selalu berhasil
+	// untuk email tak dikenal agar keberadaan akun tidak bocor
+	ForgotPassword(email string) error
+	// ResetPassword mengganti password dengan token reset lalu mencabut semua sesi user
+	ResetPassword(token, newPassword string) error
+	VerifyEmail(token string) (*models.User, error)
+	// ResendVerification mengirim ulang tautan verifikasi email (token lama tidak berlaku lagi)
+	ResendVerification(userID uint) error
 }
\ No newline at end of file
@@ -4,41 +4,205 @@ import (
 	"backend/internal/config"
 	"backend/internal/domain/models"
 	"backend/internal/domain/repositories"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // AuthService implementasi dari interface AuthService
 type authServiceImpl struct {
-	userRepo repositories.UserRepository
-	cfg      *config.Config
+	userRepo  repositories.UserRepository
+	tokenRepo repositories.AuthTokenRepository
+	mfa       MFAService
+	mailer    MailSender
+	cfg       *config.Config
+	clock     Clock
 }
 
 // NewAuthService adalah constructor
-func NewAuthService(userRepo repositories.UserRepository, cfg *config.Config) AuthService {
-	return &authServiceImpl{userRepo: userRepo, cfg: cfg}
+func NewAuthService(userRepo repositories.UserRepository, tokenRepo repositories.AuthTokenRepository, mfa MFAService, mailer MailSender, cfg *config.Config, clock Clock) AuthService {
+	return &authServiceImpl{userRepo: userRepo, tokenRepo: tokenRepo, mfa: mfa, mailer: mailer, cfg: cfg, clock: clock}
 }
 
-// Helper: generateToken membuat JWT Token
-func (s *authServiceImpl) generateToken(user *models.User) (string, error) {
+// maxMFAAttempts adalah batas kode salah per MFA challenge; setelahnya user harus login ulang
+const maxMFAAttempts = 5
+
+// randomToken membuat token acak URL-safe sepanjang n byte entropi
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// accessTTL adalah masa berlaku access token
+func (s *authServiceImpl) accessTTL() time.Duration {
+	return time.Duration(s.cfg.AccessTokenTTLMinutes) * time.Minute
+}
+
+// Helper: generateToken membuat JWT access token berumur pendek dengan jti untuk pencabutan.
+// mfa menandai sesi yang login-nya diselesaikan dengan kode 2FA.
+func (s *authServiceImpl) generateToken(user *models.User, jti string, mfa bool, now time.Time) (string, time.Time, error) {
 	// Waktu kedaluwarsa token
-	expirationTime := time.Now().Add(time.Duration(s.cfg.JWTExpHours) * time.Hour)
+	expirationTime := now.Add(s.accessTTL())
 
 	// Payload/Claims Token (data user yang disimpan)
 	claims := models.Claims{
 		UserID: user.ID,
 		Role:   user.Role,
+		// Staf properti hanya dapat mengakses data propertinya sendiri
+		PropertyID: staffPropertyID(user),
+		TenantID:   user.TenantID,
+		MFA:        mfa,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	// Menandatangani token dengan Secret Key dari .env
-	return token.SignedString([]byte(s.cfg.JWTSecret))
+	signed, err := token.SignedString([]byte(s.cfg.JWTSecret))
+	return signed, expirationTime, err
+}
+
+// issueTokens menerbitkan access token dan refresh token baru dalam sesi familyID.
+// Hanya hash refresh token yang disimpan; nilai aslinya hanya dikirim sekali ke klien.
+func (s *authServiceImpl) issueTokens(user *models.User, familyID string, mfa bool, now time.Time) (*models.TokenPair, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, accessExpiresAt, err := s.generateToken(user, jti, mfa, now)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	refreshExpiresAt := now.Add(time.Duration(s.cfg.RefreshTokenTTLHours) * time.Hour)
+	if err := s.tokenRepo.CreateRefreshToken(&models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: models.HashToken(refreshToken),
+		AccessJTI: jti,
+		MFA:       mfa,
+		ExpiresAt: refreshExpiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &models.TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// revokeAccessTokens memasukkan jti access token ke daftar pencabutan; token tersebut paling lambat
+// kedaluwarsa satu TTL dari sekarang
+func (s *authServiceImpl) revokeAccessTokens(jtis []string, now time.Time) error {
+	return revokeAccessTokens(s.tokenRepo, jtis, now, s.accessTTL())
+}
+
+func revokeAccessTokens(tokenRepo repositories.AuthTokenRepository, jtis []string, now time.Time, accessTTL time.Duration) error {
+	revoked := make([]models.RevokedToken, len(jtis))
+	for i, jti := range jtis {
+		revoked[i] = models.RevokedToken{JTI: jti, ExpiresAt: now.Add(accessTTL)}
+	}
+	return tokenRepo.RevokeAccessTokens(revoked)
+}
+
+// revokeSession mencabut semua refresh token satu sesi beserta access token yang pernah diterbitkannya
+func (s *authServiceImpl) revokeSession(familyID string, now time.Time) error {
+	jtis, err := s.tokenRepo.RevokeFamily(familyID, now)
+	if err != nil {
+		return err
+	}
+	return s.revokeAccessTokens(jtis, now)
+}
+
+// issueAccountToken membuat token sekali pakai untuk user; token sebelumnya dengan tujuan yang sama
+// tidak berlaku lagi sehingga hanya tautan di email terakhir yang bisa dipakai
+func (s *authServiceImpl) issueAccountToken(userID uint, purpose string, ttl time.Duration) (string, error) {
+	now := s.clock.Now()
+	if err := s.tokenRepo.InvalidateAccountTokens(userID, purpose, now); err != nil {
+		return "", err
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	if err := s.tokenRepo.CreateAccountToken(&models.AccountToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: models.HashToken(token),
+		ExpiresAt: now.Add(ttl),
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// findAccountToken memuat token akun yang belum dipakai dan belum kedaluwarsa
+func (s *authServiceImpl) findAccountToken(purpose, token string) (*models.AccountToken, error) {
+	accountToken, err := s.tokenRepo.FindAccountToken(purpose, models.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrInvalidAccountToken
+		}
+		return nil, err
+	}
+	if !accountToken.IsUsable(s.clock.Now()) {
+		return nil, models.ErrInvalidAccountToken
+	}
+	return accountToken, nil
+}
+
+// consumeAccountToken memvalidasi token lalu menandainya terpakai (sekali pakai)
+func (s *authServiceImpl) consumeAccountToken(purpose, token string) (*models.AccountToken, error) {
+	accountToken, err := s.findAccountToken(purpose, token)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.tokenRepo.UseAccountToken(accountToken.ID, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, models.ErrInvalidAccountToken
+	}
+	return accountToken, nil
+}
+
+// sendVerificationEmail mengirim tautan verifikasi email ke user
+func (s *authServiceImpl) sendVerificationEmail(user *models.User) error {
+	ttl := time.Duration(s.cfg.EmailVerificationTTLHours) * time.Hour
+	token, err := s.issueAccountToken(user.ID, models.AccountTokenEmailVerification, ttl)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(MailMessage{
+		To:      user.Email,
+		Subject: "Verifikasi email akun MyHotel",
+		Body: fmt.Sprintf("Halo %s,\n\nSilakan verifikasi email Anda melalui tautan berikut:\n%s/verify-email?token=%s\n\n"+
+			"Tautan berlaku selama %d jam. Abaikan email ini jika Anda tidak merasa mendaftar.\n",
+			user.FullName, s.cfg.AppBaseURL, token, s.cfg.EmailVerificationTTLHours),
+	})
 }
 
 // Register melakukan hashing dan menyimpan user ke DB
@@ -60,33 +224,288 @@ func (s *authServiceImpl) Register(user *models.User) (*models.User, error) {
 		return nil, err
 	}
 
+	// 4. Kirim Tautan Verifikasi Email. Pendaftaran tetap berhasil bila gagal; user bisa minta kirim ulang.
+	if err := s.sendVerificationEmail(user); err != nil {
+		log.Printf("gagal mengirim email verifikasi untuk user %d: %v", user.ID, err)
+	}
+
 	// Sembunyikan password sebelum dikembalikan
 	user.Password = ""
 	return user, nil
 }
 
-// Login memverifikasi user, password, dan membuat token
-func (s *authServiceImpl) Login(username, password string) (string, *models.User, error) {
+// Login memverifikasi user dan password. User dengan 2FA aktif mendapat MFA challenge yang harus
+// ditukar lewat VerifyMFALogin; user lain langsung mendapat token (sesi refresh token baru).
+func (s *authServiceImpl) Login(username, password string) (*models.LoginResult, error) {
 	// 1. Cari User di DB berdasarkan username
 	user, err := s.userRepo.FindByUsername(username)
 	if err != nil {
 		// Gunakan error gorm.ErrRecordNotFound untuk penanganan di Handler
-		return "", nil, err
+		return nil, err
 	}
 
 	// 2. Verifikasi Password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
 		// Password salah
-		return "", nil, models.ErrInvalidCredentials
+		return nil, models.ErrInvalidCredentials
 	}
+	// Sembunyikan password sebelum dikembalikan
+	user.Password = ""
 
-	// 3. Buat JWT Token
-	tokenString, err := s.generateToken(user)
+	// 3. User dengan 2FA aktif: terbitkan challenge, token baru diberikan setelah kode diverifikasi
+	mfaEnabled, err := s.mfa.IsEnabled(user.ID)
 	if err != nil {
-		return "", nil, err
+		return nil, err
+	}
+	if mfaEnabled {
+		ttl := time.Duration(s.cfg.MFAChallengeTTLMinutes) * time.Minute
+		token, err := s.issueAccountToken(user.ID, models.AccountTokenMFAChallenge, ttl)
+		if err != nil {
+			return nil, err
+		}
+		return &models.LoginResult{
+			User:      user,
+			Challenge: &models.MFAChallenge{Token: token, ExpiresAt: s.clock.Now().Add(ttl)},
+		}, nil
+	}
+
+	// 4. Buat Access Token & Refresh Token
+	tokens, err := s.startSession(user, false)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LoginResult{
+		User:                  user,
+		Tokens:                tokens,
+		MFAEnrollmentRequired: s.cfg.RequiresMFA(user.Role),
+	}, nil
+}
+
+// startSession membuka sesi login baru (family refresh token baru)
+func (s *authServiceImpl) startSession(user *models.User, mfa bool) (*models.TokenPair, error) {
+	familyID, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokens(user, familyID, mfa, s.clock.Now())
+}
+
+// VerifyMFALogin menyelesaikan login dengan MFA challenge dan kode TOTP/recovery code.
+// Setelah maxMFAAttempts kode salah, challenge tidak berlaku dan user harus login ulang.
+func (s *authServiceImpl) VerifyMFALogin(challengeToken, code string) (*models.TokenPair, *models.User, error) {
+	challenge, err := s.findAccountToken(models.AccountTokenMFAChallenge, challengeToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.mfa.Verify(challenge.UserID, code); err != nil {
+		if errors.Is(err, models.ErrInvalidMFACode) {
+			if err := s.tokenRepo.FailAccountToken(challenge.ID, maxMFAAttempts, s.clock.Now()); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, err
+	}
+
+	used, err := s.tokenRepo.UseAccountToken(challenge.ID, s.clock.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	if !used {
+		return nil, nil, models.ErrInvalidAccountToken
+	}
+
+	user, err := s.userRepo.FindByID(challenge.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, models.ErrInvalidAccountToken
+		}
+		return nil, nil, err
+	}
+	tokens, err := s.startSession(user, true)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Sembunyikan password sebelum dikembalikan
 	user.Password = ""
-	return tokenString, user, nil
+	return tokens, user, nil
+}
+
+// Refresh merotasi refresh token: token lama dicabut dan pasangan token baru diterbitkan di sesi yang sama.
+// Token yang sudah pernah dirotasi lalu dipakai lagi dianggap bocor sehingga seluruh sesi dicabut.
+func (s *authServiceImpl) Refresh(refreshToken string) (*models.TokenPair, error) {
+	now := s.clock.Now()
+	token, err := s.tokenRepo.FindRefreshTokenByHash(models.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if token.RevokedAt != nil {
+		if err := s.revokeSession(token.FamilyID, now); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrRefreshTokenReused
+	}
+	if !token.IsActive(now) {
+		return nil, models.ErrInvalidRefreshToken
+	}
+
+	// Update bersyarat: dua request paralel dengan token yang sama juga dianggap pemakaian ulang
+	rotated, err := s.tokenRepo.RevokeRefreshToken(token.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		if err := s.revokeSession(token.FamilyID, now); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrRefreshTokenReused
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	return s.issueTokens(user, token.FamilyID, token.MFA, now)
+}
+
+// Logout mencabut access token yang sedang dipakai dan seluruh refresh token sesinya
+func (s *authServiceImpl) Logout(userID uint, jti string, expiresAt time.Time) error {
+	now := s.clock.Now()
+	session, err := s.tokenRepo.FindRefreshTokenByAccessJTI(jti)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if session != nil && session.UserID == userID {
+		if err := s.revokeSession(session.FamilyID, now); err != nil {
+			return err
+		}
+	}
+	return s.tokenRepo.RevokeAccessTokens([]models.RevokedToken{{JTI: jti, ExpiresAt: expiresAt}})
+}
+
+// IsTokenRevoked mengecek apakah access token dengan jti tersebut sudah dicabut
+func (s *authServiceImpl) IsTokenRevoked(jti string) (bool, error) {
+	return s.tokenRepo.IsAccessTokenRevoked(jti)
+}
+
+// RevokeSessions mencabut semua refresh token user beserta access token yang pernah diterbitkannya
+func (s *authServiceImpl) RevokeSessions(userID uint) error {
+	return revokeUserSessions(s.tokenRepo, userID, s.clock.Now(), s.accessTTL())
+}
+
+// revokeUserSessions adalah RevokeSessions untuk service yang tidak bisa bergantung pada
+// AuthService (mis. MFAService, yang justru dipakai AuthService)
+func revokeUserSessions(tokenRepo repositories.AuthTokenRepository, userID uint, now time.Time, accessTTL time.Duration) error {
+	jtis, err := tokenRepo.RevokeUserSessions(userID, now)
+	if err != nil {
+		return err
+	}
+	return revokeAccessTokens(tokenRepo, jtis, now, accessTTL)
+}
+
+// ForgotPassword mengirim tautan reset password ke email yang terdaftar
+func (s *authServiceImpl) ForgotPassword(email string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // Jangan bocorkan apakah email terdaftar
+		}
+		return err
+	}
+
+	ttl := time.Duration(s.cfg.PasswordResetTTLMinutes) * time.Minute
+	token, err := s.issueAccountToken(user.ID, models.AccountTokenPasswordReset, ttl)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(MailMessage{
+		To:      user.Email,
+		Subject: "Reset password akun MyHotel",
+		Body: fmt.Sprintf("Halo %s,\n\nKami menerima permintaan reset password akun Anda. Buat password baru melalui tautan berikut:\n%s/reset-password?token=%s\n\n"+
+			"Tautan berlaku selama %d menit dan hanya bisa dipakai sekali. Abaikan email ini jika Anda tidak memintanya.\n",
+			user.FullName, s.cfg.AppBaseURL, token, s.cfg.PasswordResetTTLMinutes),
+	})
+}
+
+// ResetPassword mengganti password dengan token reset. Semua sesi login user dicabut agar
+// penyerang yang mungkin memegang sesi lama ikut keluar.
+func (s *authServiceImpl) ResetPassword(token, newPassword string) error {
+	accountToken, err := s.consumeAccountToken(models.AccountTokenPasswordReset, token)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(accountToken.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrInvalidAccountToken
+		}
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	now := s.clock.Now()
+	user.Password = string(hashedPassword)
+	// Tautan reset diterima lewat email, sekaligus membuktikan kepemilikan email
+	if user.EmailVerifiedAt == nil {
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+	return s.RevokeSessions(user.ID)
+}
+
+// VerifyEmail menandai email user terverifikasi dengan token dari email verifikasi
+func (s *authServiceImpl) VerifyEmail(token string) (*models.User, error) {
+	accountToken, err := s.consumeAccountToken(models.AccountTokenEmailVerification, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(accountToken.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrInvalidAccountToken
+		}
+		return nil, err
+	}
+	if user.EmailVerifiedAt == nil {
+		now := s.clock.Now()
+		user.EmailVerifiedAt = &now
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// ResendVerification mengirim ulang tautan verifikasi untuk user yang belum verifikasi
+func (s *authServiceImpl) ResendVerification(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return models.ErrEmailAlreadyVerified
+	}
+	return s.sendVerificationEmail(user)
+}
+
+// staffPropertyID: Properti tempat staf bertugas, 0 untuk staf grup & member
+func staffPropertyID(user *models.User) uint {
+	if user.PropertyID == nil {
+		return 0
+	}
+	return *user.PropertyID
 }
@@ -1,18 +1,54 @@
 package services
 
-import "backend/internal/domain/models"
+import (
+	"backend/internal/domain/models"
+	"time"
+)
+
+// BookingOptions berisi input tambahan saat membuat booking/hold
+type BookingOptions struct {
+	QuoteToken string // Opsional: quote token dari POST /api/room-types/:id/quote
+	PromoCode  string // Opsional: kode promo
+	Currency   string // Opsional: mata uang tagihan tamu (default mata uang dasar)
+}
+
+// BookingChanges berisi perubahan booking, field nil berarti tidak diubah
+type BookingChanges struct {
+	RoomTypeID   *uint
+	CheckInDate  *time.Time
+	CheckOutDate *time.Time
+	Adults       *int   // Jumlah dewasa baru, nil = tetap
+	ChildAges    *[]int // Usia anak baru, nil = tetap
+}
 
 // BookingService mendefinisikan kontrak untuk semua operasi pemesanan
 type BookingService interface {
 	// Untuk Member
-	CreateBooking(booking *models.Booking) (*models.Booking, error)
+	CreateBooking(booking *models.Booking, opts BookingOptions) (*models.Booking, error)
+	CreateHold(booking *models.Booking, opts BookingOptions) (*models.Booking, error)
+	FinalizeHold(bookingID uint, userID uint) (*models.Booking, error)
 	GetUserBookings(userID uint, pagination *models.Pagination) ([]models.Booking, error)
-	CancelBooking(bookingID uint, userID uint) error
-	
+	// CancelBooking membatalkan booking sesuai kebijakan pembatalan dan mengembalikan rincian penalti/refund
+	CancelBooking(bookingID uint, userID uint) (*models.CancellationBreakdown, error)
+	PreviewCancellation(bookingID uint, userID uint) (*models.CancellationBreakdown, error)
+	// ModifyBooking mengubah tanggal/tipe kamar/rombongan tamu dan mengembalikan catatan selisih harganya
+	ModifyBooking(bookingID uint, userID uint, changes BookingChanges) (*models.Booking, *models.BookingModification, error)
+	// GetBookingModifications: userID 0 berarti admin
+	GetBookingModifications(bookingID uint, userID uint) ([]models.BookingModification, error)
+
 	// Untuk Admin
-	GetAllBookings(pagination *models.Pagination) ([]models.Booking, error)
-	UpdatePaymentStatus(bookingID uint, newStatus string) (*models.Booking, error)
-	
+	GetAllBookings(propertyID uint, pagination *models.Pagination) ([]models.Booking, error)
+	GetBookingByID(bookingID uint) (*models.Booking, error)
+
+	// Untuk Admin/Front Desk: siklus hidup pemesanan
+	TransitionBooking(bookingID uint, toStatus string, performedBy uint, note string) (*models.Booking, error)
+	GetBookingTransitions(bookingID uint) ([]models.BookingTransition, error)
+
+	// Untuk Admin/Front Desk: penempatan kamar fisik (roomID 0 = pilih kamar kosong otomatis)
+	AssignRoom(bookingID uint, roomID uint) (*models.Booking, error)
+	CheckIn(bookingID uint, roomID uint, performedBy uint, note string) (*models.Booking, error)
+	GetRoomBoard(propertyID uint, date time.Time) (*models.RoomBoard, error)
+
 	// Fitur Review/Ulasan (setelah booking selesai)
 	CreateReview(review *models.Review) (*models.Review, error)
-}
\ No newline at end of file
+}
@@ -1,12 +1,13 @@
 package services
 
 import (
+	"backend/internal/config"
 	"backend/internal/domain/models"
+	"backend/internal/domain/money"
 	"backend/internal/domain/repositories"
 	"errors"
-	"math"
+	"time"
 
-	"github.com/araddon/dateparse"
 	"gorm.io/gorm"
 )
 
@@ -14,99 +15,667 @@ type bookingServiceImpl struct {
 	bookingRepo repositories.BookingRepository
 	roomRepo    repositories.RoomRepository
 	reviewRepo  repositories.ReviewRepository
+	userRepo    repositories.UserRepository
+	transactor  repositories.Transactor
+	pricing     PricingService
+	payments    PaymentService
+	cfg         *config.Config
+	clock       Clock
 }
 
-func NewBookingService(bRepo repositories.BookingRepository, rRepo repositories.RoomRepository, revRepo repositories.ReviewRepository) BookingService {
-	return &bookingServiceImpl{bookingRepo: bRepo, roomRepo: rRepo, reviewRepo: revRepo}
+func NewBookingService(bRepo repositories.BookingRepository, rRepo repositories.RoomRepository, revRepo repositories.ReviewRepository, uRepo repositories.UserRepository, transactor repositories.Transactor, pricing PricingService, payments PaymentService, cfg *config.Config, clock Clock) BookingService {
+	return &bookingServiceImpl{bookingRepo: bRepo, roomRepo: rRepo, reviewRepo: revRepo, userRepo: uRepo, transactor: transactor, pricing: pricing, payments: payments, cfg: cfg, clock: clock}
 }
 
-// Helper: calculateTotalPrice menghitung total harga berdasarkan hari
-func calculateTotalPrice(pricePerNight float64, checkInStr, checkOutStr string) (float64, error) {
-	in, errIn := dateparse.ParseAny(checkInStr)
-	out, errOut := dateparse.ParseAny(checkOutStr)
+// -------------------------------------------------------------------------
+// --- OPERASI MEMBER ---
+// -------------------------------------------------------------------------
+
+// CreateBooking: Logika terberat: cek overlap, hitung harga, simpan.
+func (s *bookingServiceImpl) CreateBooking(booking *models.Booking, opts BookingOptions) (*models.Booking, error) {
+	return s.reserve(booking, models.StatusPending, opts)
+}
 
-	if errIn != nil || errOut != nil {
-		return 0, errors.New("format tanggal check-in/out tidak valid")
+// CreateHold: Menahan kamar selama HoldTTLMinutes saat tamu memulai checkout.
+// Hold menempati inventori seperti booking biasa sampai difinalisasi atau kedaluwarsa.
+func (s *bookingServiceImpl) CreateHold(booking *models.Booking, opts BookingOptions) (*models.Booking, error) {
+	expiresAt := s.clock.Now().Add(time.Duration(s.cfg.HoldTTLMinutes) * time.Minute)
+	booking.HoldExpiresAt = &expiresAt
+	return s.reserve(booking, models.StatusHold, opts)
+}
+
+// ensureEmailVerified menolak booking dari member yang belum memverifikasi email
+func (s *bookingServiceImpl) ensureEmailVerified(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.Role == models.RoleMember && user.EmailVerifiedAt == nil {
+		return models.ErrEmailNotVerified
+	}
+	return nil
+}
+
+// signedQuote memverifikasi quote token (jika ada) dan memastikan isinya sesuai dengan booking
+func (s *bookingServiceImpl) signedQuote(booking *models.Booking, opts BookingOptions) (*models.Quote, error) {
+	if opts.QuoteToken == "" {
+		return nil, nil
+	}
+
+	quote, err := s.pricing.ParseQuoteToken(opts.QuoteToken)
+	if err != nil {
+		return nil, err
 	}
+	if quote.RoomTypeID != booking.RoomTypeID ||
+		quote.CheckInDate != booking.CheckInDate.Format("2006-01-02") ||
+		quote.CheckOutDate != booking.CheckOutDate.Format("2006-01-02") {
+		return nil, models.ErrQuoteMismatch
+	}
+	// Rombongan tamu mengikuti quote jika tidak diisi saat booking
+	if quote.Guests != 0 {
+		if booking.Guests == 0 {
+			booking.SetParty(quote.Party())
+		} else if !booking.Party().Equal(quote.Party()) {
+			return nil, models.ErrQuoteMismatch
+		}
+	}
+	// Harga di token sudah final; promo lain tidak boleh ditambahkan
+	if promoCode := models.NormalizePromoCode(opts.PromoCode); promoCode != "" && promoCode != quote.PromoCode {
+		return nil, models.ErrQuoteMismatch
+	}
+	// Kurs di token juga sudah final; mata uang tagihan harus sama dengan quote
+	if currency := money.NormalizeCurrency(opts.Currency); currency != "" && currency != quoteCurrency(quote) {
+		return nil, models.ErrQuoteMismatch
+	}
+	return quote, nil
+}
 
-	// Hitung durasi hari
-	duration := out.Sub(in)
-	days := math.Ceil(duration.Hours() / 24)
+// priceBooking menghitung harga booking di dalam transaksi. Jika signed tidak nil (quote token),
+// harga yang ditandatangani yang dipakai; promo di dalamnya tetap divalidasi ulang dengan lock.
+// Mengembalikan promo yang harus dicatat redemption-nya setelah booking tersimpan.
+func (s *bookingServiceImpl) priceBooking(tx repositories.TxRepositories, booking *models.Booking, roomType *models.RoomType, signed *models.Quote, opts BookingOptions) (*models.Quote, *models.PromoCode, error) {
+	promoCode := opts.PromoCode
+	quote := signed
+	if quote == nil {
+		var err error
+		quote, err = buildQuote(tx.RatePlans, roomType, booking.CheckInDate, booking.CheckOutDate, booking.Party())
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		promoCode = quote.PromoCode
+	}
 
-	// Minimal 1 malam jika check-out > check-in
-	if days < 1 {
-		return 0, errors.New("durasi pemesanan minimal 1 malam")
+	promo, err := s.lockPromo(tx, booking, roomType, quote, signed != nil, promoCode)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return pricePerNight * days, nil
+	// Quote bertanda tangan sudah memuat pajak, biaya, dan kurs saat quote dibuat
+	if signed == nil {
+		if err := applyTaxesAndFees(tx.TaxFees, quote); err != nil {
+			return nil, nil, err
+		}
+		if err := convertQuote(tx.Rates, quote, opts.Currency); err != nil {
+			return nil, nil, err
+		}
+	}
+	return quote, promo, nil
 }
 
-// -------------------------------------------------------------------------
-// --- OPERASI MEMBER ---
-// -------------------------------------------------------------------------
+// lockPromo mengunci & memvalidasi promo booking. Untuk quote yang belum ditandatangani,
+// diskonnya sekaligus ditambahkan ke quote.
+func (s *bookingServiceImpl) lockPromo(tx repositories.TxRepositories, booking *models.Booking, roomType *models.RoomType, quote *models.Quote, signed bool, promoCode string) (*models.PromoCode, error) {
+	promoCode = models.NormalizePromoCode(promoCode)
+	if promoCode == "" {
+		return nil, nil
+	}
 
-// CreateBooking: Logika terberat: cek overlap, hitung harga, simpan
-func (s *bookingServiceImpl) CreateBooking(booking *models.Booking) (*models.Booking, error) {
-	// 1. Validasi Keberadaan Kamar dan Harga
-	room, err := s.roomRepo.FindByID(booking.RoomID)
+	// Lock baris promo agar kuota tidak terlampaui oleh booking paralel
+	promo, err := tx.Promos.LockByCode(promoCode)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("kamar tidak ditemukan")
+			return nil, models.ErrPromoNotFound
 		}
 		return nil, err
 	}
+	if promo.PerUserLimit > 0 {
+		used, err := tx.Promos.CountUserRedemptions(promo.ID, booking.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(promo.PerUserLimit) {
+			return nil, models.ErrPromoPerUserLimit
+		}
+	}
 
-	// 2. Cek Overlap (Fitur Pencegahan Double Booking)
-	isOverlap, err := s.bookingRepo.CheckOverlap(booking.RoomID, booking.CheckInDate.Format("2006-01-02"), booking.CheckOutDate.Format("2006-01-02"))
+	now := s.clock.Now()
+	if signed {
+		if err := promo.Validate(now, len(quote.Nights), roomType.Code); err != nil {
+			return nil, err
+		}
+	} else if err := applyPromo(quote, promo, roomType, now); err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+// reserve menyimpan booking dengan status awal tertentu.
+// Seluruh langkah berjalan di dalam satu transaksi: baris tipe kamar di-lock (FOR UPDATE)
+// sebelum sisa kamar dihitung, sehingga dua request paralel untuk tipe & tanggal yang sama
+// tidak bisa sama-sama mengambil kamar terakhir. Kamar fisik ditetapkan belakangan (lihat AssignRoom).
+func (s *bookingServiceImpl) reserve(booking *models.Booking, status string, opts BookingOptions) (*models.Booking, error) {
+	if err := s.ensureEmailVerified(booking.UserID); err != nil {
+		return nil, err
+	}
+	signed, err := s.signedQuote(booking, opts)
 	if err != nil {
 		return nil, err
 	}
-	if isOverlap {
-		return nil, errors.New("kamar sudah dibooking pada periode tersebut")
+	if booking.Guests <= 0 {
+		booking.SetParty(models.Party{Adults: 1})
+	}
+	if err := booking.Party().Validate(); err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.WithinTransaction(func(tx repositories.TxRepositories) error {
+		// 1. Validasi Keberadaan Tipe Kamar dan Lock Baris Tipe Kamar
+		roomType, err := tx.RoomTypes.LockByID(booking.RoomTypeID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrRoomTypeNotFound
+			}
+			return err
+		}
+
+		// 2. Lepas hold kedaluwarsa di tipe kamar ini yang belum sempat dibersihkan sweeper
+		if err := expireHolds(tx, s.clock.Now(), booking.RoomTypeID); err != nil {
+			return err
+		}
+
+		// 3. Cek Batasan Masa Inap (min/max LOS, CTA/CTD, jendela pemesanan) dan Sisa Kamar (Pencegahan Overbooking)
+		rules, err := stayRules(tx, roomType.ID, booking.CheckInDate, booking.CheckOutDate)
+		if err != nil {
+			return err
+		}
+		if err := ensureInventory(tx, roomType.ID, booking.CheckInDate, booking.CheckOutDate, 0, rules, s.clock.Now()); err != nil {
+			return err
+		}
+
+		// 4. Hitung Harga Per Malam (rate plan + promo + pajak/biaya) dan simpan rinciannya bersama booking.
+		// Jika tamu membawa quote token, harga yang ditandatangani yang dipakai.
+		quote, promo, err := s.priceBooking(tx, booking, roomType, signed, opts)
+		if err != nil {
+			return err
+		}
+		booking.Subtotal = quote.Subtotal
+		booking.TotalPrice = quote.GrandTotal
+		booking.NightPrices = quote.Nights
+		booking.PriceComponents = quote.Components(s.clock.Now())
+		booking.DiscountAmount = quote.TotalDiscount()
+		applyGuestPrice(booking, quote)
+		if promo != nil {
+			booking.PromoCodeID = &promo.ID
+		}
+
+		// 5. Snapshot Kebijakan Pembatalan yang berlaku saat booking dibuat
+		if err := snapshotCancellationPolicy(tx, booking, roomType); err != nil {
+			return err
+		}
+
+		// 6. Set Status Awal (booking pending wajib dibayar sebelum PaymentDueAt)
+		booking.PaymentStatus = models.StatusPending
+		booking.BookingStatus = status
+		if status == models.StatusPending {
+			booking.PaymentDueAt = s.paymentDueAt()
+		}
+
+		// 7. Simpan Booking di properti tipe kamarnya (kamar fisik belum ditetapkan)
+		booking.PropertyID = roomType.PropertyID
+		booking.RoomID = nil
+		if err := tx.Bookings.Create(booking); err != nil {
+			return err
+		}
+
+		// 8. Catat Redemption Promo (atomik bersama booking)
+		if promo == nil {
+			return nil
+		}
+		return tx.Promos.CreateRedemption(&models.PromoRedemption{
+			PromoCodeID:    promo.ID,
+			UserID:         booking.UserID,
+			BookingID:      booking.ID,
+			DiscountAmount: booking.DiscountAmount,
+			CreatedAt:      s.clock.Now(),
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
+	return booking, nil
+}
 
-	// 3. Hitung Total Harga
-	totalPrice, err := calculateTotalPrice(room.Price, booking.CheckInDate.Format("2006-01-02"), booking.CheckOutDate.Format("2006-01-02"))
+// stayRules memuat batasan masa inap tipe kamar (level tipe & level kamar) untuk masa inap tersebut
+func stayRules(tx repositories.TxRepositories, roomTypeID uint, checkIn, checkOut time.Time) (models.StayRules, error) {
+	restrictions, err := tx.Restrictions.FindForStay([]uint{roomTypeID}, checkIn.Format("2006-01-02"), checkOut.Format("2006-01-02"))
 	if err != nil {
 		return nil, err
 	}
-	booking.TotalPrice = totalPrice
+	return models.StayRules(restrictions), nil
+}
+
+// ensureInventory memastikan masa inap tidak melanggar batasan penjualan (rules, dinilai pada now) dan
+// masih ada kamar fisik bertipe roomTypeID yang belum terjual sepanjang masa inap.
+// Baris tipe kamar harus sudah di-lock oleh pemanggil.
+func ensureInventory(tx repositories.TxRepositories, roomTypeID uint, checkIn, checkOut time.Time, excludeBookingID uint, rules models.StayRules, now time.Time) error {
+	if err := rules.ForRoomType().Check(checkIn, checkOut, now); err != nil {
+		return err
+	}
+
+	sellable, err := tx.Rooms.CountSellable(roomTypeID)
+	if err != nil {
+		return err
+	}
+	overlapping, err := tx.Bookings.FindOverlappingByRoomType(roomTypeID, checkIn.Format("2006-01-02"), checkOut.Format("2006-01-02"), excludeBookingID, now)
+	if err != nil {
+		return err
+	}
+	if models.RemainingRooms(int(sellable), overlapping, checkIn, checkOut) < 1 {
+		return models.ErrNoRoomsAvailable
+	}
+
+	// Kamar dengan batasan sendiri tidak dapat menampung masa inap ini
+	restricted, violation := rules.CheckRooms(checkIn, checkOut, now)
+	if int(sellable)-restricted < 1 {
+		return violation
+	}
+	return nil
+}
+
+// snapshotCancellationPolicy menyalin aturan kebijakan pembatalan ke booking.
+// Policy rate plan (malam pertama) diprioritaskan di atas policy tipe kamar; tanpa policy pembatalan gratis.
+func snapshotCancellationPolicy(tx repositories.TxRepositories, booking *models.Booking, roomType *models.RoomType) error {
+	policyID := roomType.CancellationPolicyID
+	if len(booking.NightPrices) > 0 && booking.NightPrices[0].RatePlanID != nil {
+		plan, err := tx.RatePlans.FindByID(*booking.NightPrices[0].RatePlanID)
+		if err != nil {
+			return err
+		}
+		if plan.CancellationPolicyID != nil {
+			policyID = plan.CancellationPolicyID
+		}
+	}
+	if policyID == nil {
+		return nil
+	}
 
-	// 4. Set Status Default
-	booking.PaymentStatus = models.StatusPending
-	booking.BookingStatus = models.StatusConfirmed
+	policy, err := tx.Policies.FindByID(*policyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // Policy sudah dihapus
+		}
+		return err
+	}
+	booking.CancellationPolicyID = &policy.ID
+	booking.CancellationTerms = policy.Terms()
+	return nil
+}
 
-	// 5. Simpan Transaksi
-	if err := s.bookingRepo.Create(booking); err != nil {
+// FinalizeHold: Mengubah hold menjadi pemesanan (hold -> pending) sebelum waktunya habis
+func (s *bookingServiceImpl) FinalizeHold(bookingID uint, userID uint) (*models.Booking, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.UserID != userID {
+		return nil, models.ErrBookingForbidden
+	}
+	if booking.IsHoldExpired(s.clock.Now()) {
+		return nil, models.ErrHoldExpired
+	}
+
+	err = s.transactor.WithinTransaction(func(tx repositories.TxRepositories) error {
+		if err := applyTransition(tx, booking, models.StatusPending, userID, "hold difinalisasi", s.clock.Now()); err != nil {
+			return err
+		}
+		booking.HoldExpiresAt = nil
+		booking.PaymentDueAt = s.paymentDueAt()
+		return tx.Bookings.Update(booking)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return booking, nil
 }
 
+// paymentDueAt menghitung batas pembayaran untuk booking pending yang baru dibuat
+func (s *bookingServiceImpl) paymentDueAt() *time.Time {
+	dueAt := s.clock.Now().Add(time.Duration(s.cfg.PaymentDeadlineMinutes) * time.Minute)
+	return &dueAt
+}
+
+// expireHolds mengubah hold yang kedaluwarsa menjadi expired dan melepas malamnya.
+// roomTypeID 0 berarti semua tipe kamar. Hold yang lebih dulu ditangani proses lain
+// (sweeper, finalisasi) dilewati, bukan menggagalkan transaksi pemanggil.
+func expireHolds(tx repositories.TxRepositories, now time.Time, roomTypeID uint) error {
+	holds, err := tx.Bookings.FindExpiredHolds(now, roomTypeID, 0)
+	if err != nil {
+		return err
+	}
+	for i := range holds {
+		if err := applyTransition(tx, &holds[i], models.StatusExpired, 0, "hold kedaluwarsa", now); err != nil {
+			if errors.Is(err, models.ErrInvalidTransition) {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// applyTransition menjalankan satu transisi state machine di dalam transaksi:
+// validasi, update bersyarat, pencatatan riwayat, dan pelepasan inventori bila perlu.
+// performedBy 0 berarti transisi dilakukan oleh sistem (job terjadwal).
+func applyTransition(tx repositories.TxRepositories, booking *models.Booking, toStatus string, performedBy uint, note string, at time.Time) error {
+	fromStatus := booking.BookingStatus
+	transition, err := booking.TransitionTo(toStatus, performedBy, note, at)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Bookings.TransitionStatus(booking.ID, fromStatus, toStatus); err != nil {
+		return err
+	}
+	if err := tx.Bookings.CreateTransition(transition); err != nil {
+		return err
+	}
+	if models.ReleasesInventory(toStatus) {
+		if err := tx.Bookings.ReleaseNights(booking.ID); err != nil {
+			return err
+		}
+		// Kuota promo dikembalikan untuk booking yang batal/tidak jadi
+		return tx.Promos.ReleaseRedemption(booking.ID)
+	}
+	return nil
+}
+
 // GetUserBookings: Mengambil riwayat pemesanan member
 func (s *bookingServiceImpl) GetUserBookings(userID uint, pagination *models.Pagination) ([]models.Booking, error) {
 	return s.bookingRepo.FindByUserID(userID, pagination)
 }
 
-// CancelBooking: Membatalkan pemesanan oleh member
-func (s *bookingServiceImpl) CancelBooking(bookingID uint, userID uint) error {
+// checkInAt mengembalikan waktu check-in booking (tanggal check-in + jam check-in hotel)
+func (s *bookingServiceImpl) checkInAt(booking *models.Booking) time.Time {
+	y, m, d := booking.CheckInDate.Date()
+	return time.Date(y, m, d, s.cfg.CheckInHour, 0, 0, 0, time.Local)
+}
+
+// PreviewCancellation: Menghitung penalti & refund jika booking dibatalkan sekarang
+func (s *bookingServiceImpl) PreviewCancellation(bookingID uint, userID uint) (*models.CancellationBreakdown, error) {
 	booking, err := s.bookingRepo.FindByID(bookingID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if booking.UserID != userID {
+		return nil, models.ErrBookingForbidden
+	}
+	if !models.CanTransition(booking.BookingStatus, models.StatusCancelled) {
+		return nil, models.ErrInvalidTransition
+	}
+	return booking.CancellationBreakdown(s.checkInAt(booking), s.clock.Now())
+}
+
+// CancelBooking: Membatalkan pemesanan oleh member. Penalti dihitung dari snapshot kebijakan
+// pembatalan; sisa pembayaran direfund melalui payment layer di transaksi yang sama.
+func (s *bookingServiceImpl) CancelBooking(bookingID uint, userID uint) (*models.CancellationBreakdown, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Logika Bisnis: Hanya user yang bersangkutan yang boleh membatalkan
 	if booking.UserID != userID {
-		return errors.New("anda tidak memiliki izin membatalkan pemesanan ini")
+		return nil, models.ErrBookingForbidden
 	}
 
-	// Logika Bisnis: Hanya boleh dibatalkan jika statusnya belum Paid/Completed
-	if booking.PaymentStatus == models.StatusPaid || booking.BookingStatus == models.StatusCompleted {
-		return errors.New("pemesanan yang sudah dibayar/selesai tidak dapat dibatalkan")
+	var breakdown *models.CancellationBreakdown
+	err = s.transactor.WithinTransaction(func(tx repositories.TxRepositories) error {
+		locked, err := lockBookingPayments(tx, booking.ID)
+		if err != nil {
+			return err
+		}
+		now := s.clock.Now()
+		breakdown, err = locked.CancellationBreakdown(s.checkInAt(locked), now)
+		if err != nil {
+			return err
+		}
+
+		// Update status (divalidasi state machine) dan lepaskan inventori malam
+		if err := applyTransition(tx, locked, models.StatusCancelled, userID, "dibatalkan oleh member", now); err != nil {
+			return err
+		}
+		if !breakdown.Refund.IsPositive() {
+			return nil
+		}
+		return s.payments.RefundWithin(tx, locked, breakdown.Refund, userID, "refund pembatalan oleh member")
+	})
+	if err != nil {
+		return nil, err
 	}
+	return breakdown, nil
+}
+
+// ModifyBooking: Mengubah tanggal, tipe kamar, dan/atau jumlah tamu booking milik member.
+// Sisa kamar dicek ulang tanpa menghitung booking itu sendiri, harga dihitung ulang dengan
+// promo & snapshot kurs yang sama, lalu selisihnya menjadi tambahan tagihan (sisa tagihan bertambah)
+// atau refund kelebihan bayar. Seluruh langkah berjalan di satu transaksi dan dicatat di riwayat perubahan.
+func (s *bookingServiceImpl) ModifyBooking(bookingID uint, userID uint, changes BookingChanges) (*models.Booking, *models.BookingModification, error) {
+	var booking *models.Booking
+	var modification *models.BookingModification
+	err := s.transactor.WithinTransaction(func(tx repositories.TxRepositories) error {
+		// 1. Lock booking beserta ledger pembayarannya
+		locked, err := lockBookingPayments(tx, bookingID)
+		if err != nil {
+			return err
+		}
+		if locked.UserID != userID {
+			return models.ErrBookingForbidden
+		}
+		if !locked.CanBeModified() {
+			return models.ErrBookingNotModifiable
+		}
 
-	// Update status
-	return s.bookingRepo.UpdateStatus(bookingID, models.StatusCancelled)
+		// 2. Tentukan nilai baru, field yang tidak dikirim tetap memakai nilai lama
+		now := s.clock.Now()
+		modification = &models.BookingModification{
+			BookingID:      locked.ID,
+			PerformedBy:    userID,
+			FromRoomTypeID: locked.RoomTypeID,
+			ToRoomTypeID:   locked.RoomTypeID,
+			FromRoomID:     locked.RoomID,
+			FromCheckIn:    locked.CheckInDate,
+			FromCheckOut:   locked.CheckOutDate,
+			ToCheckIn:      locked.CheckInDate,
+			ToCheckOut:     locked.CheckOutDate,
+			FromGuests:     locked.Guests,
+			FromAdults:     locked.Party().Adults,
+			FromChildAges:  locked.ChildAges,
+			Currency:       locked.GuestTotal.Currency,
+			OldTotal:       locked.GuestTotal,
+			CreatedAt:      now,
+		}
+		if changes.RoomTypeID != nil {
+			modification.ToRoomTypeID = *changes.RoomTypeID
+		}
+		if changes.CheckInDate != nil {
+			modification.ToCheckIn = *changes.CheckInDate
+		}
+		if changes.CheckOutDate != nil {
+			modification.ToCheckOut = *changes.CheckOutDate
+		}
+		party := locked.Party()
+		if changes.Adults != nil {
+			party.Adults = *changes.Adults
+		}
+		if changes.ChildAges != nil {
+			party.ChildAges = *changes.ChildAges
+		}
+		modification.SetToParty(party)
+		if modification.ToRoomTypeID == modification.FromRoomTypeID &&
+			modification.ToCheckIn.Equal(modification.FromCheckIn) &&
+			modification.ToCheckOut.Equal(modification.FromCheckOut) &&
+			party.Equal(locked.Party()) {
+			return models.ErrNoBookingChanges
+		}
+		if err := party.Validate(); err != nil {
+			return err
+		}
+		today := now.Format("2006-01-02")
+		if modification.ToCheckIn.Format("2006-01-02") < today {
+			return models.ErrCheckInDatePassed
+		}
+
+		// 3. Lock tipe kamar tujuan lalu cek sisa kamar tanpa menghitung booking ini sendiri
+		roomType, err := tx.RoomTypes.LockByID(modification.ToRoomTypeID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrRoomTypeNotFound
+			}
+			return err
+		}
+		// Booking tidak bisa dipindah ke properti lain; tamu harus membatalkan dan memesan ulang
+		if roomType.PropertyID != locked.PropertyID {
+			return models.ErrPropertyMismatch
+		}
+		if err := expireHolds(tx, now, roomType.ID); err != nil {
+			return err
+		}
+		// Batasan masa inap hanya dinilai ulang bila tipe kamar atau tanggal berubah
+		var rules models.StayRules
+		if modification.ToRoomTypeID != modification.FromRoomTypeID ||
+			!modification.ToCheckIn.Equal(modification.FromCheckIn) ||
+			!modification.ToCheckOut.Equal(modification.FromCheckOut) {
+			if rules, err = stayRules(tx, roomType.ID, modification.ToCheckIn, modification.ToCheckOut); err != nil {
+				return err
+			}
+		}
+		if err := ensureInventory(tx, roomType.ID, modification.ToCheckIn, modification.ToCheckOut, locked.ID, rules, now); err != nil {
+			return err
+		}
+
+		// 4. Hitung ulang harga: rate plan, promo yang sudah ditebus, pajak/biaya, dan kurs snapshot booking
+		quote, err := buildQuote(tx.RatePlans, roomType, modification.ToCheckIn, modification.ToCheckOut, party)
+		if err != nil {
+			return err
+		}
+		if locked.PromoCodeID != nil {
+			promo, err := tx.Promos.FindByID(*locked.PromoCodeID)
+			if err != nil {
+				return err
+			}
+			if err := promo.AppliesTo(len(quote.Nights), roomType.Code); err != nil {
+				return err
+			}
+			addPromoDiscount(quote, promo)
+		}
+		if err := applyTaxesAndFees(tx.TaxFees, quote); err != nil {
+			return err
+		}
+		convertQuoteAt(quote, locked.Currency, locked.ExchangeRate)
+
+		// 5. Simpan booking dengan harga baru
+		locked.RoomTypeID = roomType.ID
+		locked.CheckInDate = modification.ToCheckIn
+		locked.CheckOutDate = modification.ToCheckOut
+		locked.SetParty(party)
+		locked.Subtotal = quote.Subtotal
+		locked.TotalPrice = quote.GrandTotal
+		locked.DiscountAmount = quote.TotalDiscount()
+		locked.NightPrices = quote.Nights
+		locked.PriceComponents = quote.Components(now)
+		applyGuestPrice(locked, quote)
+		if err := tx.Bookings.ReplacePriceDetails(locked); err != nil {
+			return err
+		}
+		if err := s.keepAssignedRoom(tx, locked); err != nil {
+			return err
+		}
+		modification.ToRoomID = locked.RoomID
+		if locked.PromoCodeID != nil {
+			if err := tx.Promos.UpdateRedemptionDiscount(locked.ID, locked.DiscountAmount); err != nil {
+				return err
+			}
+		}
+		locked.RefreshPaymentStatus()
+		if err := tx.Bookings.Update(locked); err != nil {
+			return err
+		}
+
+		// 6. Selisih harga: kekurangan menjadi sisa tagihan, kelebihan bayar langsung direfund
+		modification.NewTotal = locked.GuestTotal
+		modification.Difference = locked.GuestTotal.Sub(modification.OldTotal)
+		modification.Refunded = money.New(0, locked.GuestTotal.Currency)
+		summary := locked.PaymentSummary()
+		excess := summary.Paid.Sub(summary.Refunded).Sub(locked.GuestTotal)
+		if excess.IsPositive() {
+			if err := s.payments.RefundWithin(tx, locked, excess, userID, "refund selisih perubahan pemesanan"); err != nil {
+				return err
+			}
+			modification.Refunded = excess
+		}
+
+		// 7. Catat riwayat perubahan
+		if err := tx.Bookings.CreateModification(modification); err != nil {
+			return err
+		}
+		booking = locked
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return booking, modification, nil
+}
+
+// keepAssignedRoom mempertahankan kamar fisik booking yang diubah bila tipenya sama dan kamar itu
+// masih kosong untuk tanggal baru; selain itu penempatan dilepas dan kamar ditetapkan ulang nanti.
+func (s *bookingServiceImpl) keepAssignedRoom(tx repositories.TxRepositories, booking *models.Booking) error {
+	if booking.RoomID == nil {
+		return nil
+	}
+	if err := tx.Bookings.ReleaseNights(booking.ID); err != nil {
+		return err
+	}
+
+	room, err := tx.Rooms.LockByID(*booking.RoomID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if room != nil && room.RoomTypeID == booking.RoomTypeID {
+		isOverlap, err := tx.Bookings.CheckOverlap(room.ID, booking.CheckInDate.Format("2006-01-02"), booking.CheckOutDate.Format("2006-01-02"), booking.ID, s.clock.Now())
+		if err != nil {
+			return err
+		}
+		if !isOverlap {
+			return tx.Bookings.ReserveNights(booking)
+		}
+	}
+	booking.RoomID = nil
+	booking.Room = nil
+	return nil
+}
+
+// GetBookingModifications: Mengambil riwayat perubahan booking. userID 0 berarti admin (tanpa cek kepemilikan).
+func (s *bookingServiceImpl) GetBookingModifications(bookingID uint, userID uint) ([]models.BookingModification, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != 0 && booking.UserID != userID {
+		return nil, models.ErrBookingForbidden
+	}
+	return s.bookingRepo.FindModifications(bookingID)
 }
 
 // -------------------------------------------------------------------------
@@ -114,25 +683,192 @@ func (s *bookingServiceImpl) CancelBooking(bookingID uint, userID uint) error {
 // -------------------------------------------------------------------------
 
 // GetAllBookings: Mengambil semua riwayat booking
-func (s *bookingServiceImpl) GetAllBookings(pagination *models.Pagination) ([]models.Booking, error) {
-	return s.bookingRepo.FindAll(pagination)
+func (s *bookingServiceImpl) GetAllBookings(propertyID uint, pagination *models.Pagination) ([]models.Booking, error) {
+	return s.bookingRepo.FindAll(propertyID, pagination)
+}
+
+// GetBookingByID: Mengambil detail pemesanan (Admin)
+func (s *bookingServiceImpl) GetBookingByID(bookingID uint) (*models.Booking, error) {
+	return s.bookingRepo.FindByID(bookingID)
 }
 
-// UpdatePaymentStatus: Mengubah status pembayaran (misalnya dari Pending ke Paid)
-func (s *bookingServiceImpl) UpdatePaymentStatus(bookingID uint, newStatus string) (*models.Booking, error) {
+// TransitionBooking: Mengubah status pemesanan oleh admin/front desk (check-in, check-out, no-show, dll)
+func (s *bookingServiceImpl) TransitionBooking(bookingID uint, toStatus string, performedBy uint, note string) (*models.Booking, error) {
 	booking, err := s.bookingRepo.FindByID(bookingID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Logika Bisnis: Update status pembayaran
-	booking.PaymentStatus = newStatus
-	if err := s.bookingRepo.Update(booking); err != nil {
+	// Check-in selalu melalui CheckIn agar booking mendapat kamar fisik
+	if toStatus == models.StatusCheckedIn {
+		return s.CheckIn(bookingID, 0, performedBy, note)
+	}
+
+	err = s.transactor.WithinTransaction(func(tx repositories.TxRepositories) error {
+		return applyTransition(tx, booking, toStatus, performedBy, note, s.clock.Now())
+	})
+	if err != nil {
 		return nil, err
 	}
 	return booking, nil
 }
 
+// assignRoom menetapkan kamar fisik untuk booking (roomID 0 = kamar kosong pertama bertipe sama)
+// dan mengklaim malamnya di room_nights. Booking harus sudah di-lock oleh pemanggil.
+func assignRoom(tx repositories.TxRepositories, booking *models.Booking, roomID uint, now time.Time) error {
+	checkIn := booking.CheckInDate.Format("2006-01-02")
+	checkOut := booking.CheckOutDate.Format("2006-01-02")
+
+	if roomID == 0 {
+		rooms, err := tx.Rooms.FindFreeForStay(booking.RoomTypeID, checkIn, checkOut, booking.ID)
+		if err != nil {
+			return err
+		}
+		if len(rooms) == 0 {
+			return models.ErrNoRoomsAvailable
+		}
+		roomID = rooms[0].ID
+
+		// Utamakan kamar yang batasan masa inapnya sendiri tidak dilanggar (dinilai saat booking dibuat)
+		rules, err := stayRules(tx, booking.RoomTypeID, booking.CheckInDate, booking.CheckOutDate)
+		if err != nil {
+			return err
+		}
+		for _, room := range rooms {
+			if rules.ForRoom(room.ID).Check(booking.CheckInDate, booking.CheckOutDate, booking.CreatedAt) == nil {
+				roomID = room.ID
+				break
+			}
+		}
+	}
+
+	room, err := tx.Rooms.LockByID(roomID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrRoomNotFound
+		}
+		return err
+	}
+	if room.RoomTypeID != booking.RoomTypeID {
+		return models.ErrRoomTypeMismatch
+	}
+	if room.Status == "maintenance" {
+		return models.ErrRoomUnderMaintenance
+	}
+	isOverlap, err := tx.Bookings.CheckOverlap(room.ID, checkIn, checkOut, booking.ID, now)
+	if err != nil {
+		return err
+	}
+	if isOverlap {
+		return models.ErrRoomAlreadyBooked
+	}
+
+	// Pindah kamar: malam di kamar lama dilepas sebelum klaim kamar baru
+	if err := tx.Bookings.ReleaseNights(booking.ID); err != nil {
+		return err
+	}
+	booking.RoomID = &room.ID
+	booking.Room = nil
+	if err := tx.Bookings.ReserveNights(booking); err != nil {
+		return err
+	}
+	if err := tx.Bookings.Update(booking); err != nil {
+		return err
+	}
+	booking.Room = room
+	return nil
+}
+
+// AssignRoom: Menetapkan/memindahkan kamar fisik sebuah booking dari room board (Admin/Front Desk)
+func (s *bookingServiceImpl) AssignRoom(bookingID uint, roomID uint) (*models.Booking, error) {
+	var booking *models.Booking
+	err := s.transactor.WithinTransaction(func(tx repositories.TxRepositories) error {
+		locked, err := tx.Bookings.LockByID(bookingID)
+		if err != nil {
+			return err
+		}
+		switch locked.BookingStatus {
+		case models.StatusPending, models.StatusConfirmed, models.StatusCheckedIn:
+		default:
+			return models.ErrRoomNotAssignable
+		}
+		if err := assignRoom(tx, locked, roomID, s.clock.Now()); err != nil {
+			return err
+		}
+		booking = locked
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// CheckIn: confirmed -> checked_in. Booking yang belum mendapat kamar fisik ditetapkan di sini
+// (roomID 0 = kamar kosong pertama bertipe sama); roomID lain memindahkan penempatan yang ada.
+func (s *bookingServiceImpl) CheckIn(bookingID uint, roomID uint, performedBy uint, note string) (*models.Booking, error) {
+	var booking *models.Booking
+	err := s.transactor.WithinTransaction(func(tx repositories.TxRepositories) error {
+		locked, err := tx.Bookings.LockByID(bookingID)
+		if err != nil {
+			return err
+		}
+		if !models.CanTransition(locked.BookingStatus, models.StatusCheckedIn) {
+			return models.ErrInvalidTransition
+		}
+		if roomID != 0 || locked.RoomID == nil {
+			if err := assignRoom(tx, locked, roomID, s.clock.Now()); err != nil {
+				return err
+			}
+		}
+		if err := applyTransition(tx, locked, models.StatusCheckedIn, performedBy, note, s.clock.Now()); err != nil {
+			return err
+		}
+		booking = locked
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// GetRoomBoard: Papan penempatan kamar pada satu tanggal: setiap kamar fisik beserta tamunya,
+// dan booking aktif yang belum mendapat kamar (Admin/Front Desk)
+func (s *bookingServiceImpl) GetRoomBoard(propertyID uint, date time.Time) (*models.RoomBoard, error) {
+	day := date.Format("2006-01-02")
+	rooms, err := s.roomRepo.FindAll(models.RoomFilter{PropertyFilter: models.PropertyFilter{PropertyID: propertyID}}, &models.Pagination{Sort: "property_id asc, room_number asc"})
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := s.bookingRepo.FindActiveOn(propertyID, day, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	board := &models.RoomBoard{Date: day, Rooms: make([]models.RoomBoardEntry, 0, len(rooms)), Unassigned: []models.Booking{}}
+	byRoom := make(map[uint]*models.Booking, len(bookings))
+	for i := range bookings {
+		if bookings[i].RoomID == nil {
+			board.Unassigned = append(board.Unassigned, bookings[i])
+			continue
+		}
+		byRoom[*bookings[i].RoomID] = &bookings[i]
+	}
+	for _, room := range rooms {
+		board.Rooms = append(board.Rooms, models.RoomBoardEntry{Room: room, Booking: byRoom[room.ID]})
+	}
+	return board, nil
+}
+
+// GetBookingTransitions: Mengambil riwayat perubahan status sebuah pemesanan
+func (s *bookingServiceImpl) GetBookingTransitions(bookingID uint) ([]models.BookingTransition, error) {
+	if _, err := s.bookingRepo.FindByID(bookingID); err != nil {
+		return nil, err
+	}
+	return s.bookingRepo.FindTransitions(bookingID)
+}
+
 // -------------------------------------------------------------------------
 // --- FITUR ULASAN ---
 // -------------------------------------------------------------------------
@@ -145,8 +881,8 @@ func (s *bookingServiceImpl) CreateReview(review *models.Review) (*models.Review
 		return nil, errors.New("booking terkait tidak ditemukan")
 	}
 
-	// 2. Validasi: Pastikan Booking sudah selesai (Completed)
-	if booking.BookingStatus != models.StatusCompleted {
+	// 2. Validasi: Pastikan tamu sudah check-out (Checked Out/Completed)
+	if !booking.CanBeReviewed() {
 		return nil, errors.New("ulasan hanya dapat dibuat untuk pemesanan yang sudah selesai")
 	}
 
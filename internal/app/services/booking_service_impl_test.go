@@ -0,0 +1,145 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/internal/infra/http/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// concurrentRequests adalah jumlah request paralel pada test perebutan kamar terakhir
+const concurrentRequests = 10
+
+// TestCreateBookingConcurrentLastRoom memastikan request paralel untuk tipe kamar & tanggal yang sama
+// hanya menghasilkan satu booking saat tersisa satu kamar (lock FOR UPDATE pada baris tipe kamar)
+func TestCreateBookingConcurrentLastRoom(t *testing.T) {
+	env := newTestEnv(t)
+	roomType, _ := env.createRoomType("DLX", 500000, 1)
+
+	members := make([]*models.User, concurrentRequests)
+	for i := range members {
+		members[i] = env.createMember(fmt.Sprintf("guest%d", i))
+	}
+
+	// Route member tanpa JWT: userID diambil dari header agar setiap request milik member berbeda
+	app := fiber.New()
+	bookingHandler := handlers.NewBookingHandler(env.bookings)
+	app.Post("/api/member/bookings", func(c *fiber.Ctx) error {
+		userID, _ := strconv.Atoi(c.Get("X-Test-User"))
+		c.Locals("userID", uint(userID))
+		return c.Next()
+	}, bookingHandler.CreateBooking)
+
+	checkIn, checkOut := stay(10, 2)
+	body := fmt.Sprintf(`{"room_type_id": %d, "check_in_date": %q, "check_out_date": %q}`,
+		roomType.ID, checkIn.Format("2006-01-02"), checkOut.Format("2006-01-02"))
+
+	statuses := make([]int, concurrentRequests)
+	var wg sync.WaitGroup
+	for i, member := range members {
+		wg.Add(1)
+		go func(i int, userID uint) {
+			defer wg.Done()
+			req := httptest.NewRequest(fiber.MethodPost, "/api/member/bookings", strings.NewReader(body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			req.Header.Set("X-Test-User", strconv.Itoa(int(userID)))
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Errorf("request %d gagal: %v", i, err)
+				return
+			}
+			statuses[i] = resp.StatusCode
+		}(i, member.ID)
+	}
+	wg.Wait()
+
+	created, conflicts := 0, 0
+	for _, status := range statuses {
+		switch status {
+		case fiber.StatusCreated:
+			created++
+		case fiber.StatusConflict:
+			conflicts++
+		default:
+			t.Errorf("status tidak terduga %d", status)
+		}
+	}
+	if created != 1 || conflicts != concurrentRequests-1 {
+		t.Fatalf("ingin 1 booking (201) dan %d konflik (409), dapat %d dan %d", concurrentRequests-1, created, conflicts)
+	}
+
+	var stored int64
+	if err := env.db.Model(&models.Booking{}).Where("room_type_id = ?", roomType.ID).Count(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored != 1 {
+		t.Fatalf("ingin 1 booking tersimpan, dapat %d", stored)
+	}
+
+	// Setelah tipe kamar penuh, service mengembalikan ErrNoRoomsAvailable
+	late := env.createMember("late")
+	if _, err := env.bookings.CreateBooking(newBooking(late.ID, roomType.ID, checkIn, checkOut), services.BookingOptions{}); !errors.Is(err, models.ErrNoRoomsAvailable) {
+		t.Fatalf("ingin ErrNoRoomsAvailable, dapat %v", err)
+	}
+}
+
+// TestAssignRoomConcurrentSameRoom memastikan penempatan paralel beberapa booking ke kamar fisik yang
+// sama hanya dimenangkan satu booking (lock kamar dan unique index room_nights (room_id, date))
+func TestAssignRoomConcurrentSameRoom(t *testing.T) {
+	env := newTestEnv(t)
+	roomType, rooms := env.createRoomType("STD", 300000, concurrentRequests)
+	member := env.createMember("guest")
+
+	checkIn, checkOut := stay(5, 3)
+	bookingIDs := make([]uint, concurrentRequests)
+	for i := range bookingIDs {
+		booking, err := env.bookings.CreateBooking(newBooking(member.ID, roomType.ID, checkIn, checkOut), services.BookingOptions{})
+		if err != nil {
+			t.Fatalf("gagal membuat booking %d: %v", i, err)
+		}
+		bookingIDs[i] = booking.ID
+	}
+
+	errs := make([]error, concurrentRequests)
+	var wg sync.WaitGroup
+	for i, bookingID := range bookingIDs {
+		wg.Add(1)
+		go func(i int, bookingID uint) {
+			defer wg.Done()
+			_, errs[i] = env.bookings.AssignRoom(bookingID, rooms[0].ID)
+		}(i, bookingID)
+	}
+	wg.Wait()
+
+	assigned, conflicts := 0, 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			assigned++
+		case errors.Is(err, models.ErrRoomAlreadyBooked):
+			conflicts++
+		default:
+			t.Errorf("booking %d: error tidak terduga %v", bookingIDs[i], err)
+		}
+	}
+	if assigned != 1 || conflicts != concurrentRequests-1 {
+		t.Fatalf("ingin 1 penempatan dan %d ErrRoomAlreadyBooked, dapat %d dan %d", concurrentRequests-1, assigned, conflicts)
+	}
+
+	var nights int64
+	if err := env.db.Model(&models.RoomNight{}).Where("room_id = ?", rooms[0].ID).Count(&nights).Error; err != nil {
+		t.Fatal(err)
+	}
+	if nights != 3 {
+		t.Fatalf("ingin 3 malam terisi di kamar %d, dapat %d", rooms[0].ID, nights)
+	}
+}
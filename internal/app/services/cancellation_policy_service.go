@@ -0,0 +1,12 @@
+package services
+
+import "backend/internal/domain/models"
+
+// CancellationPolicyService mendefinisikan kontrak untuk pengelolaan kebijakan pembatalan (Admin)
+type CancellationPolicyService interface {
+	GetCancellationPolicies(pagination *models.Pagination) ([]models.CancellationPolicy, error)
+	GetCancellationPolicyByID(policyID uint) (*models.CancellationPolicy, error)
+	CreateCancellationPolicy(policy *models.CancellationPolicy) (*models.CancellationPolicy, error)
+	UpdateCancellationPolicy(policy *models.CancellationPolicy) (*models.CancellationPolicy, error)
+	DeleteCancellationPolicy(policyID uint) error
+}
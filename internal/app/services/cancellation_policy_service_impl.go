@@ -0,0 +1,78 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type cancellationPolicyServiceImpl struct {
+	policyRepo repositories.CancellationPolicyRepository
+}
+
+func NewCancellationPolicyService(cpRepo repositories.CancellationPolicyRepository) CancellationPolicyService {
+	return &cancellationPolicyServiceImpl{policyRepo: cpRepo}
+}
+
+// resolveCancellationPolicy memvalidasi referensi policy dari tipe kamar/rate plan; ID 0 berarti tanpa policy
+func resolveCancellationPolicy(repo repositories.CancellationPolicyRepository, policyID *uint) (*uint, error) {
+	if policyID == nil || *policyID == 0 {
+		return nil, nil
+	}
+	if _, err := repo.FindByID(*policyID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrCancellationPolicyNotFound
+		}
+		return nil, err
+	}
+	return policyID, nil
+}
+
+// GetCancellationPolicies: Mengambil semua kebijakan pembatalan
+func (s *cancellationPolicyServiceImpl) GetCancellationPolicies(pagination *models.Pagination) ([]models.CancellationPolicy, error) {
+	return s.policyRepo.FindAll(pagination)
+}
+
+// GetCancellationPolicyByID: Mengambil detail kebijakan pembatalan beserta aturannya
+func (s *cancellationPolicyServiceImpl) GetCancellationPolicyByID(policyID uint) (*models.CancellationPolicy, error) {
+	policy, err := s.policyRepo.FindByID(policyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrCancellationPolicyNotFound
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// CreateCancellationPolicy: Membuat kebijakan pembatalan baru
+func (s *cancellationPolicyServiceImpl) CreateCancellationPolicy(policy *models.CancellationPolicy) (*models.CancellationPolicy, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.policyRepo.Create(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// UpdateCancellationPolicy: Mengubah kebijakan pembatalan (booking lama tetap memakai snapshot aturannya)
+func (s *cancellationPolicyServiceImpl) UpdateCancellationPolicy(policy *models.CancellationPolicy) (*models.CancellationPolicy, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.policyRepo.Update(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// DeleteCancellationPolicy: Menghapus kebijakan pembatalan
+func (s *cancellationPolicyServiceImpl) DeleteCancellationPolicy(policyID uint) error {
+	if _, err := s.GetCancellationPolicyByID(policyID); err != nil {
+		return err
+	}
+	return s.policyRepo.Delete(policyID)
+}
@@ -0,0 +1,19 @@
+package services
+
+import "time"
+
+// Clock membungkus time.Now agar job terjadwal (sweeper, dll) dapat diuji dengan waktu palsu
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+// NewSystemClock mengembalikan Clock yang memakai waktu sistem
+func NewSystemClock() Clock {
+	return systemClock{}
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
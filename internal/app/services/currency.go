@@ -0,0 +1,78 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/internal/domain/repositories"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// exchangeRateFor mengembalikan kurs mata uang dasar -> currency (1 untuk mata uang dasar)
+func exchangeRateFor(rates repositories.ExchangeRateRepository, currency string) (float64, error) {
+	if currency == money.BaseCurrency() {
+		return 1, nil
+	}
+	rate, err := rates.FindByCurrency(currency)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, models.ErrExchangeRateNotFound
+		}
+		return 0, err
+	}
+	return rate.Rate, nil
+}
+
+// convertQuote mengisi quote.Guest dengan nominal dalam mata uang tamu.
+// currency kosong atau sama dengan mata uang dasar berarti tanpa konversi.
+func convertQuote(rates repositories.ExchangeRateRepository, quote *models.Quote, currency string) error {
+	currency = money.NormalizeCurrency(currency)
+	if currency == "" || currency == money.BaseCurrency() {
+		quote.Guest = nil
+		return nil
+	}
+
+	rate, err := exchangeRateFor(rates, currency)
+	if err != nil {
+		return err
+	}
+	convertQuoteAt(quote, currency, rate)
+	return nil
+}
+
+// convertQuoteAt mengonversi quote memakai kurs tertentu, misalnya snapshot kurs booking
+// saat booking diubah agar tamu tidak terkena selisih kurs
+func convertQuoteAt(quote *models.Quote, currency string, rate float64) {
+	currency = money.NormalizeCurrency(currency)
+	if currency == "" || currency == money.BaseCurrency() {
+		quote.Guest = nil
+		return
+	}
+	quote.Guest = &models.GuestPrice{
+		Currency:   currency,
+		Rate:       rate,
+		Subtotal:   quote.Subtotal.Convert(currency, rate),
+		GrandTotal: quote.GrandTotal.Convert(currency, rate),
+	}
+}
+
+// quoteCurrency mengembalikan mata uang tagihan sebuah quote
+func quoteCurrency(quote *models.Quote) string {
+	if quote.Guest != nil {
+		return quote.Guest.Currency
+	}
+	return money.BaseCurrency()
+}
+
+// applyGuestPrice menyalin nominal tamu & snapshot kurs dari quote ke booking
+func applyGuestPrice(booking *models.Booking, quote *models.Quote) {
+	booking.Currency = money.BaseCurrency()
+	booking.ExchangeRate = 1
+	booking.GuestTotal = quote.GrandTotal
+	if quote.Guest != nil {
+		booking.Currency = quote.Guest.Currency
+		booking.ExchangeRate = quote.Guest.Rate
+		booking.GuestTotal = quote.Guest.GrandTotal
+	}
+}
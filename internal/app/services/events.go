@@ -0,0 +1,37 @@
+package services
+
+import (
+	"log"
+	"time"
+)
+
+// --- Jenis Event ---
+const (
+	EventBookingPaymentExpired = "booking.payment_expired"
+	EventBookingPaid           = "booking.paid"
+)
+
+// Event adalah notifikasi domain yang dikirim setelah transaksi berhasil di-commit
+type Event struct {
+	Type       string
+	BookingID  uint
+	UserID     uint
+	OccurredAt time.Time
+	Message    string
+}
+
+// EventPublisher mengirim event ke sistem notifikasi (email, queue, dll)
+type EventPublisher interface {
+	Publish(event Event)
+}
+
+type logEventPublisher struct{}
+
+// NewLogEventPublisher mengembalikan EventPublisher yang hanya menulis event ke log
+func NewLogEventPublisher() EventPublisher {
+	return logEventPublisher{}
+}
+
+func (logEventPublisher) Publish(event Event) {
+	log.Printf("[event] %s booking=%d user=%d: %s", event.Type, event.BookingID, event.UserID, event.Message)
+}
@@ -0,0 +1,14 @@
+package services
+
+import "backend/internal/domain/models"
+
+// ExchangeRateService mendefinisikan kontrak untuk kurs offline & konversi harga tampilan
+type ExchangeRateService interface {
+	// Untuk Admin
+	GetExchangeRates() ([]models.ExchangeRate, error)
+	SetExchangeRate(currency string, rate float64) (*models.ExchangeRate, error)
+	DeleteExchangeRate(currency string) error
+
+	// LocalizeRoomTypes mengisi DisplayPrice setiap tipe kamar dalam currency (kosong = tanpa konversi)
+	LocalizeRoomTypes(roomTypes []models.RoomType, currency string) error
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/internal/domain/repositories"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type exchangeRateServiceImpl struct {
+	rateRepo repositories.ExchangeRateRepository
+}
+
+func NewExchangeRateService(erRepo repositories.ExchangeRateRepository) ExchangeRateService {
+	return &exchangeRateServiceImpl{rateRepo: erRepo}
+}
+
+// GetExchangeRates: Mengambil semua kurs
+func (s *exchangeRateServiceImpl) GetExchangeRates() ([]models.ExchangeRate, error) {
+	return s.rateRepo.FindAll()
+}
+
+// SetExchangeRate: Membuat atau mengubah kurs sebuah mata uang.
+// Booking yang sudah ada tetap memakai snapshot kurs saat dibuat.
+func (s *exchangeRateServiceImpl) SetExchangeRate(currency string, rate float64) (*models.ExchangeRate, error) {
+	currency = money.NormalizeCurrency(currency)
+	if len(currency) != 3 {
+		return nil, errors.New("kode mata uang harus 3 huruf (ISO 4217)")
+	}
+	if currency == money.BaseCurrency() {
+		return nil, errors.New("kurs mata uang dasar selalu 1")
+	}
+	if rate <= 0 {
+		return nil, errors.New("kurs harus lebih dari 0")
+	}
+
+	exchangeRate := &models.ExchangeRate{Currency: currency, Rate: rate}
+	if err := s.rateRepo.Upsert(exchangeRate); err != nil {
+		return nil, err
+	}
+	return s.rateRepo.FindByCurrency(currency)
+}
+
+// DeleteExchangeRate: Menghapus kurs (mata uang tersebut tidak bisa dipakai lagi)
+func (s *exchangeRateServiceImpl) DeleteExchangeRate(currency string) error {
+	err := s.rateRepo.Delete(money.NormalizeCurrency(currency))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.ErrExchangeRateNotFound
+	}
+	return err
+}
+
+// LocalizeRoomTypes: Mengonversi harga tipe kamar ke mata uang tamu untuk ditampilkan
+func (s *exchangeRateServiceImpl) LocalizeRoomTypes(roomTypes []models.RoomType, currency string) error {
+	currency = money.NormalizeCurrency(currency)
+	if currency == "" {
+		return nil
+	}
+
+	rate, err := exchangeRateFor(s.rateRepo, currency)
+	if err != nil {
+		return err
+	}
+	for i := range roomTypes {
+		displayPrice := roomTypes[i].Price.Convert(currency, rate)
+		roomTypes[i].DisplayPrice = &displayPrice
+	}
+	return nil
+}
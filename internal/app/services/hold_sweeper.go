@@ -0,0 +1,76 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// holdSweepBatchSize membatasi jumlah hold yang diproses per putaran sweeper
+const holdSweepBatchSize = 100
+
+// HoldSweeper secara berkala melepas booking hold yang sudah kedaluwarsa
+type HoldSweeper struct {
+	bookingRepo repositories.BookingRepository
+	transactor  repositories.Transactor
+	clock       Clock
+	interval    time.Duration
+}
+
+func NewHoldSweeper(bRepo repositories.BookingRepository, transactor repositories.Transactor, clock Clock, interval time.Duration) *HoldSweeper {
+	return &HoldSweeper{bookingRepo: bRepo, transactor: transactor, clock: clock, interval: interval}
+}
+
+// SweepOnce melepas satu batch hold yang HoldExpiresAt-nya sudah lewat menurut clock dan
+// mengembalikan jumlahnya. Aman dijalankan paralel dengan sweeper lain maupun pembuatan booking:
+// setiap hold diproses dalam transaksinya sendiri, di-lock (FOR UPDATE) dan dicek ulang, dan
+// hold yang lebih dulu ditangani proses lain dilewati.
+func (s *HoldSweeper) SweepOnce() (int, error) {
+	now := s.clock.Now()
+	candidates, err := s.bookingRepo.FindExpiredHolds(now, 0, holdSweepBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, candidate := range candidates {
+		swept := false
+		err := s.transactor.WithinTransaction(func(tx repositories.TxRepositories) error {
+			locked, err := tx.Bookings.LockByID(candidate.ID)
+			if err != nil {
+				return err
+			}
+			// Hold mungkin sudah difinalisasi atau di-expire proses lain sejak query kandidat
+			if !locked.IsHoldExpired(now) {
+				return nil
+			}
+			if err := applyTransition(tx, locked, models.StatusExpired, 0, "hold kedaluwarsa", now); err != nil {
+				return err
+			}
+			swept = true
+			return nil
+		})
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, models.ErrInvalidTransition) {
+				continue
+			}
+			return expired, err
+		}
+		if swept {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// Run menjalankan SweepOnce setiap interval sampai ctx dibatalkan
+func (s *HoldSweeper) Run(ctx context.Context) {
+	runPeriodically(ctx, "hold-sweeper", s.interval, func() error {
+		_, err := s.SweepOnce()
+		return err
+	})
+}
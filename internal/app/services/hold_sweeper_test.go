@@ -0,0 +1,225 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	domainrepos "backend/internal/domain/repositories"
+)
+
+func TestHoldSweeperSweepOnce(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration // Waktu berlalu sejak hold dibuat (HoldTTLMinutes = 15)
+		want    string
+	}{
+		{name: "sebelum TTL hold tetap", elapsed: 14 * time.Minute, want: models.StatusHold},
+		{name: "tepat saat TTL hold kedaluwarsa", elapsed: 15 * time.Minute, want: models.StatusExpired},
+		{name: "setelah TTL hold kedaluwarsa", elapsed: 16 * time.Minute, want: models.StatusExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomType, _ := env.createRoomType("DLX", 500000, 1)
+			member := env.createMember("guest")
+
+			checkIn, checkOut := stay(7, 2)
+			hold, err := env.bookings.CreateHold(newBooking(member.ID, roomType.ID, checkIn, checkOut), services.BookingOptions{})
+			if err != nil {
+				t.Fatalf("gagal membuat hold: %v", err)
+			}
+
+			env.clock.Advance(tt.elapsed)
+			sweeper := services.NewHoldSweeper(env.bookingRepo, env.transactor, env.clock, time.Minute)
+			swept, err := sweeper.SweepOnce()
+			if err != nil {
+				t.Fatalf("SweepOnce: %v", err)
+			}
+			if got := env.bookingStatus(hold.ID); got != tt.want {
+				t.Fatalf("status hold = %q, ingin %q", got, tt.want)
+			}
+			if wantSwept := map[bool]int{true: 1, false: 0}[tt.want == models.StatusExpired]; swept != wantSwept {
+				t.Fatalf("SweepOnce mengembalikan %d, ingin %d", swept, wantSwept)
+			}
+		})
+	}
+}
+
+// TestExpiredHoldFreesInventory memastikan hold yang kedaluwarsa menurut clock tidak lagi menempati
+// inventori, baik di pencarian ketersediaan maupun saat booking, walaupun sweeper belum berjalan
+func TestExpiredHoldFreesInventory(t *testing.T) {
+	env := newTestEnv(t)
+	roomType, _ := env.createRoomType("DLX", 500000, 1)
+	holder := env.createMember("holder")
+	guest := env.createMember("guest")
+
+	checkIn, checkOut := stay(7, 2)
+	if _, err := env.bookings.CreateHold(newBooking(holder.ID, roomType.ID, checkIn, checkOut), services.BookingOptions{}); err != nil {
+		t.Fatalf("gagal membuat hold: %v", err)
+	}
+
+	available := func() int {
+		t.Helper()
+		roomTypes, _, err := env.roomTypes.GetAvailableRoomTypes(checkIn.Format("2006-01-02"), checkOut.Format("2006-01-02"), models.RoomFilter{}, models.Party{}, &models.Pagination{Sort: "id asc"})
+		if err != nil {
+			t.Fatalf("GetAvailableRoomTypes: %v", err)
+		}
+		return len(roomTypes)
+	}
+
+	// Selama hold berlaku kamar terakhir tidak bisa dijual
+	env.clock.Advance(10 * time.Minute)
+	if n := available(); n != 0 {
+		t.Fatalf("ingin 0 tipe tersedia selama hold berlaku, dapat %d", n)
+	}
+	if _, err := env.bookings.CreateBooking(newBooking(guest.ID, roomType.ID, checkIn, checkOut), services.BookingOptions{}); !errors.Is(err, models.ErrNoRoomsAvailable) {
+		t.Fatalf("ingin ErrNoRoomsAvailable selama hold berlaku, dapat %v", err)
+	}
+
+	// Setelah TTL lewat, kamar kembali tersedia tanpa menunggu sweeper
+	env.clock.Advance(10 * time.Minute)
+	if n := available(); n != 1 {
+		t.Fatalf("ingin 1 tipe tersedia setelah hold kedaluwarsa, dapat %d", n)
+	}
+	booking, err := env.bookings.CreateBooking(newBooking(guest.ID, roomType.ID, checkIn, checkOut), services.BookingOptions{})
+	if err != nil {
+		t.Fatalf("booking setelah hold kedaluwarsa gagal: %v", err)
+	}
+	if booking.BookingStatus != models.StatusPending {
+		t.Fatalf("status booking = %q, ingin %q", booking.BookingStatus, models.StatusPending)
+	}
+}
+
+// TestHoldSweeperConcurrentWithBookings memastikan sweeper paralel dan booking baru yang sama-sama
+// meng-expire hold tidak saling menggagalkan, dan setiap hold hanya di-expire sekali
+func TestHoldSweeperConcurrentWithBookings(t *testing.T) {
+	const holds, workers = 5, 3
+
+	env := newTestEnv(t)
+	roomType, _ := env.createRoomType("DLX", 500000, holds+workers)
+	checkIn, checkOut := stay(7, 2)
+
+	holdIDs := make([]uint, holds)
+	for i := range holdIDs {
+		member := env.createMember(fmt.Sprintf("holder%d", i))
+		hold, err := env.bookings.CreateHold(newBooking(member.ID, roomType.ID, checkIn, checkOut), services.BookingOptions{})
+		if err != nil {
+			t.Fatalf("gagal membuat hold %d: %v", i, err)
+		}
+		holdIDs[i] = hold.ID
+	}
+	guests := make([]*models.User, workers)
+	for i := range guests {
+		guests[i] = env.createMember(fmt.Sprintf("guest%d", i))
+	}
+
+	env.clock.Advance(16 * time.Minute)
+
+	var (
+		wg          sync.WaitGroup
+		mu          sync.Mutex
+		sweptTotal  int
+		sweepErrs   = make([]error, workers)
+		bookingErrs = make([]error, workers)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			swept, err := services.NewHoldSweeper(env.bookingRepo, env.transactor, env.clock, time.Minute).SweepOnce()
+			sweepErrs[i] = err
+			mu.Lock()
+			sweptTotal += swept
+			mu.Unlock()
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			_, bookingErrs[i] = env.bookings.CreateBooking(newBooking(guests[i].ID, roomType.ID, checkIn, checkOut), services.BookingOptions{})
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < workers; i++ {
+		if sweepErrs[i] != nil {
+			t.Errorf("sweeper %d gagal: %v", i, sweepErrs[i])
+		}
+		if bookingErrs[i] != nil {
+			t.Errorf("booking %d gagal: %v", i, bookingErrs[i])
+		}
+	}
+	if sweptTotal > holds {
+		t.Errorf("sweeper meng-expire %d hold, maksimal %d", sweptTotal, holds)
+	}
+
+	for _, id := range holdIDs {
+		if got := env.bookingStatus(id); got != models.StatusExpired {
+			t.Errorf("hold %d berstatus %q, ingin %q", id, got, models.StatusExpired)
+		}
+		var transitions int64
+		if err := env.db.Model(&models.BookingTransition{}).Where("booking_id = ? AND to_status = ?", id, models.StatusExpired).Count(&transitions).Error; err != nil {
+			t.Fatal(err)
+		}
+		if transitions != 1 {
+			t.Errorf("hold %d tercatat %d kali expired, ingin 1", id, transitions)
+		}
+	}
+}
+
+// staleHoldRepository mengembalikan daftar kandidat hold yang sudah usang, seperti sweeper yang
+// membaca kandidat tepat sebelum proses lain meng-expire hold tersebut
+type staleHoldRepository struct {
+	domainrepos.BookingRepository
+	holds []models.Booking
+}
+
+func (r *staleHoldRepository) FindExpiredHolds(now time.Time, roomTypeID uint, limit int) ([]models.Booking, error) {
+	return r.holds, nil
+}
+
+// TestHoldSweeperSkipsHandledHolds memastikan sweeper melewati hold yang sudah di-expire oleh
+// booking baru sejak kandidatnya dibaca, tanpa error dan tanpa riwayat transisi ganda
+func TestHoldSweeperSkipsHandledHolds(t *testing.T) {
+	env := newTestEnv(t)
+	roomType, _ := env.createRoomType("DLX", 500000, 1)
+	holder := env.createMember("holder")
+	guest := env.createMember("guest")
+
+	checkIn, checkOut := stay(7, 2)
+	hold, err := env.bookings.CreateHold(newBooking(holder.ID, roomType.ID, checkIn, checkOut), services.BookingOptions{})
+	if err != nil {
+		t.Fatalf("gagal membuat hold: %v", err)
+	}
+
+	env.clock.Advance(16 * time.Minute)
+	candidates, err := env.bookingRepo.FindExpiredHolds(env.clock.Now(), 0, 0)
+	if err != nil || len(candidates) != 1 {
+		t.Fatalf("ingin 1 kandidat hold, dapat %d (%v)", len(candidates), err)
+	}
+
+	// Booking baru meng-expire hold di transaksinya sebelum sweeper sempat memprosesnya
+	if _, err := env.bookings.CreateBooking(newBooking(guest.ID, roomType.ID, checkIn, checkOut), services.BookingOptions{}); err != nil {
+		t.Fatalf("booking gagal: %v", err)
+	}
+
+	sweeper := services.NewHoldSweeper(&staleHoldRepository{BookingRepository: env.bookingRepo, holds: candidates}, env.transactor, env.clock, time.Minute)
+	swept, err := sweeper.SweepOnce()
+	if err != nil {
+		t.Fatalf("SweepOnce: %v", err)
+	}
+	if swept != 0 {
+		t.Fatalf("SweepOnce mengembalikan %d, ingin 0", swept)
+	}
+
+	var transitions int64
+	if err := env.db.Model(&models.BookingTransition{}).Where("booking_id = ? AND to_status = ?", hold.ID, models.StatusExpired).Count(&transitions).Error; err != nil {
+		t.Fatal(err)
+	}
+	if transitions != 1 {
+		t.Fatalf("hold tercatat %d kali expired, ingin 1", transitions)
+	}
+}
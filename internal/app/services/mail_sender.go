@@ -0,0 +1,13 @@
+package services
+
+// MailMessage adalah email teks biasa untuk satu penerima
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// MailSender adalah abstraksi pengiriman email. Implementasi ada di internal/infra/mail.
+type MailSender interface {
+	Send(msg MailMessage) error
+}
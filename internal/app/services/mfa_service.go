@@ -0,0 +1,25 @@
+package services
+
+import "backend/internal/domain/models"
+
+// MFAService mendefinisikan kontrak autentikasi dua faktor (TOTP + recovery code)
+type MFAService interface {
+	GetStatus(userID uint) (*models.MFAStatus, error)
+	// Enroll membuat secret TOTP baru; 2FA belum aktif sampai Confirm berhasil
+	Enroll(userID uint) (*models.MFAEnrollment, error)
+	// Confirm mengaktifkan 2FA dengan kode pertama dari authenticator dan mengembalikan recovery code
+	// (hanya ditampilkan sekali)
+	Confirm(userID uint, code string) ([]string, error)
+	// RegenerateRecoveryCodes mengganti seluruh recovery code; code = kode TOTP/recovery code saat ini
+	RegenerateRecoveryCodes(userID uint, code string) ([]string, error)
+	// Disable menonaktifkan 2FA milik sendiri; ditolak bila role wajib 2FA
+	Disable(userID uint, code string) error
+	// Reset menghapus 2FA user lain, mis. saat perangkat dan recovery code hilang, lalu mencabut
+	// semua sesinya. actor tunduk pada batasan yang sama dengan RoleService.AssignRole.
+	Reset(actorID, userID uint) error
+
+	// IsEnabled & Verify dipakai AuthService pada langkah kedua login
+	IsEnabled(userID uint) (bool, error)
+	// Verify menerima kode TOTP (sekali pakai per time-step) atau recovery code
+	Verify(userID uint, code string) error
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"backend/internal/config"
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// recoveryCodeCount adalah jumlah recovery code yang diterbitkan setiap kali
+const recoveryCodeCount = 10
+
+type mfaServiceImpl struct {
+	mfaRepo   repositories.MFARepository
+	userRepo  repositories.UserRepository
+	roleRepo  repositories.RoleRepository
+	tokenRepo repositories.AuthTokenRepository
+	cfg       *config.Config
+	clock     Clock
+}
+
+func NewMFAService(mfaRepo repositories.MFARepository, userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, tokenRepo repositories.AuthTokenRepository, cfg *config.Config, clock Clock) MFAService {
+	return &mfaServiceImpl{mfaRepo: mfaRepo, userRepo: userRepo, roleRepo: roleRepo, tokenRepo: tokenRepo, cfg: cfg, clock: clock}
+}
+
+// findMFA memuat pendaftaran 2FA user, nil jika belum pernah mendaftar
+func (s *mfaServiceImpl) findMFA(userID uint) (*models.UserMFA, error) {
+	mfa, err := s.mfaRepo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return mfa, nil
+}
+
+// newRecoveryCodes membuat recovery code baru; nilai asli dikembalikan untuk ditampilkan sekali,
+// yang disimpan hanya hash-nya
+func (s *mfaServiceImpl) newRecoveryCodes(userID uint) ([]string, []models.MFARecoveryCode, error) {
+	codes := make([]string, recoveryCodeCount)
+	records := make([]models.MFARecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = code
+		records[i] = models.MFARecoveryCode{UserID: userID, CodeHash: models.HashToken(normalizeMFACode(code))}
+	}
+	return codes, records, nil
+}
+
+func (s *mfaServiceImpl) GetStatus(userID uint) (*models.MFAStatus, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	mfa, err := s.findMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.MFAStatus{Required: s.cfg.RequiresMFA(user.Role)}
+	if mfa.IsEnabled() {
+		status.Enabled = true
+		status.ConfirmedAt = mfa.ConfirmedAt
+		if status.RecoveryCodesRemaining, err = s.mfaRepo.CountRecoveryCodes(userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// Enroll membuat (atau mengganti) secret TOTP yang belum diverifikasi
+func (s *mfaServiceImpl) Enroll(userID uint) (*models.MFAEnrollment, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	mfa, err := s.findMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.IsEnabled() {
+		return nil, models.ErrMFAAlreadyEnabled
+	}
+
+	// 160 bit, panjang secret yang direkomendasikan RFC 4226 untuk HMAC-SHA1
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	sealed, err := sealSecret(s.cfg.MFAEncryptionKey, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa == nil {
+		mfa = &models.UserMFA{UserID: userID}
+	}
+	mfa.Secret = sealed
+	mfa.LastUsedStep = 0
+	if err := s.mfaRepo.Save(mfa); err != nil {
+		return nil, err
+	}
+
+	return &models.MFAEnrollment{
+		Secret:     base32NoPadding.EncodeToString(secret),
+		OTPAuthURI: otpauthURI(s.cfg.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm mengaktifkan 2FA setelah user membuktikan authenticator-nya menghasilkan kode yang benar
+func (s *mfaServiceImpl) Confirm(userID uint, code string) ([]string, error) {
+	mfa, err := s.findMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, models.ErrMFANotEnrolled
+	}
+	if mfa.IsEnabled() {
+		return nil, models.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := openSecret(s.cfg.MFAEncryptionKey, mfa.Secret)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now()
+	step, ok := matchTOTP(secret, normalizeMFACode(code), now)
+	if !ok {
+		return nil, models.ErrInvalidMFACode
+	}
+
+	codes, records, err := s.newRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	mfa.ConfirmedAt = &now
+	mfa.LastUsedStep = step
+	if err := s.mfaRepo.Enable(mfa, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *mfaServiceImpl) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	if err := s.Verify(userID, code); err != nil {
+		return nil, err
+	}
+	codes, records, err := s.newRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *mfaServiceImpl) Disable(userID uint, code string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if s.cfg.RequiresMFA(user.Role) {
+		return models.ErrMFARequired
+	}
+	if err := s.Verify(userID, code); err != nil {
+		return err
+	}
+	return s.mfaRepo.Delete(userID)
+}
+
+func (s *mfaServiceImpl) Reset(actorID, userID uint) error {
+	// 2FA milik sendiri dinonaktifkan lewat Disable yang meminta kode
+	if actorID == userID {
+		return models.ErrPermissionDenied
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	actor, err := s.userRepo.FindByID(actorID)
+	if err != nil {
+		return err
+	}
+	// Reset melemahkan login user, jadi dijaga seperti mengganti role-nya: staf tidak boleh
+	// mereset 2FA admin atau user dengan izin yang tidak ia miliki
+	if err := checkManageUser(s.roleRepo, actor, user); err != nil {
+		return err
+	}
+	if err := s.mfaRepo.Delete(userID); err != nil {
+		return err
+	}
+	// Sesi yang mungkin dibuka oleh pihak yang memegang perangkat lama ikut dicabut
+	accessTTL := time.Duration(s.cfg.AccessTokenTTLMinutes) * time.Minute
+	return revokeUserSessions(s.tokenRepo, userID, s.clock.Now(), accessTTL)
+}
+
+func (s *mfaServiceImpl) IsEnabled(userID uint) (bool, error) {
+	mfa, err := s.findMFA(userID)
+	if err != nil {
+		return false, err
+	}
+	return mfa.IsEnabled(), nil
+}
+
+// Verify memvalidasi kode TOTP (setiap time-step hanya bisa dipakai sekali) atau recovery code
+func (s *mfaServiceImpl) Verify(userID uint, code string) error {
+	mfa, err := s.findMFA(userID)
+	if err != nil {
+		return err
+	}
+	if !mfa.IsEnabled() {
+		return models.ErrMFANotEnabled
+	}
+
+	now := s.clock.Now()
+	code = normalizeMFACode(code)
+	if !isTOTPCode(code) {
+		used, err := s.mfaRepo.UseRecoveryCode(userID, models.HashToken(code), now)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return models.ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	secret, err := openSecret(s.cfg.MFAEncryptionKey, mfa.Secret)
+	if err != nil {
+		return err
+	}
+	step, ok := matchTOTP(secret, code, now)
+	if !ok {
+		return models.ErrInvalidMFACode
+	}
+	fresh, err := s.mfaRepo.MarkStepUsed(userID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return models.ErrInvalidMFACode // Kode sudah pernah dipakai (replay)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/internal/infra/database/mysql/mysqltest"
+	"backend/internal/infra/gorm/repositories"
+)
+
+// TestResetMFAPreventsEscalation memastikan staf dengan izin staff.manage hanya dapat mereset 2FA
+// user yang role-nya juga boleh ia ganti, dan reset mencabut sesi user tersebut
+func TestResetMFAPreventsEscalation(t *testing.T) {
+	env := newTestEnv(t)
+	roleRepo := repositories.NewGormRoleRepository(env.db)
+	userRepo := repositories.NewGormRepository(env.db)
+	mfaRepo := repositories.NewGormMFARepository(env.db)
+	tokenRepo := repositories.NewGormAuthTokenRepository(env.db)
+	roleService := services.NewRoleService(roleRepo, userRepo, nil)
+	mfaService := services.NewMFAService(mfaRepo, userRepo, roleRepo, tokenRepo, env.cfg, env.clock)
+	if err := roleService.SeedDefaultRoles(); err != nil {
+		t.Fatal(err)
+	}
+
+	admin := env.createStaff("admin", models.RoleAdmin)
+	staffManager, err := roleService.CreateRole(admin.ID, &models.Role{
+		Name:        "staff_manager",
+		Permissions: []string{models.PermStaffManage, models.PermBookingView, models.PermPaymentView},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := roleService.CreateRole(admin.ID, &models.Role{Name: "viewer", Permissions: []string{models.PermBookingView}}); err != nil {
+		t.Fatal(err)
+	}
+	manager := env.createStaff("manager", staffManager.Name)
+
+	tests := []struct {
+		name    string
+		actorID uint
+		role    string // Role user yang 2FA-nya direset; kosong = actor sendiri
+		wantErr error
+	}{
+		{name: "mereset staf yang izinnya dimiliki", actorID: manager.ID, role: "viewer"},
+		{name: "mereset member", actorID: manager.ID, role: models.RoleMember},
+		{name: "mereset pemegang role yang lebih kuat", actorID: manager.ID, role: models.RoleAccounting, wantErr: models.ErrPermissionNotHeld},
+		{name: "mereset admin", actorID: manager.ID, role: models.RoleAdmin, wantErr: models.ErrPermissionDenied},
+		{name: "mereset diri sendiri", actorID: manager.ID, wantErr: models.ErrPermissionDenied},
+		{name: "admin mereset admin lain", actorID: admin.ID, role: models.RoleAdmin},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := manager
+			if tt.role != "" {
+				target = env.createStaff(fmt.Sprintf("target%d", i), tt.role)
+			}
+			confirmedAt := env.clock.Now()
+			mysqltest.Create(t, env.db, &models.UserMFA{UserID: target.ID, Secret: "x", ConfirmedAt: &confirmedAt})
+			session := &models.RefreshToken{
+				UserID:    target.ID,
+				FamilyID:  fmt.Sprintf("family%d", i),
+				TokenHash: models.HashToken(fmt.Sprintf("refresh%d", i)),
+				AccessJTI: fmt.Sprintf("jti%d", i),
+				ExpiresAt: env.clock.Now().Add(24 * time.Hour),
+			}
+			if err := tokenRepo.CreateRefreshToken(session); err != nil {
+				t.Fatal(err)
+			}
+
+			err := mfaService.Reset(tt.actorID, target.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ingin error %v, dapat %v", tt.wantErr, err)
+			}
+
+			enabled, err := mfaService.IsEnabled(target.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if enabled != (tt.wantErr != nil) {
+				t.Fatalf("2FA aktif %v setelah reset, ingin %v", enabled, tt.wantErr != nil)
+			}
+			stored, err := tokenRepo.FindRefreshTokenByHash(session.TokenHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if active := stored.IsActive(env.clock.Now()); active != (tt.wantErr != nil) {
+				t.Fatalf("sesi aktif %v setelah reset, ingin %v", active, tt.wantErr != nil)
+			}
+			revoked, err := tokenRepo.IsAccessTokenRevoked(session.AccessJTI)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if revoked != (tt.wantErr == nil) {
+				t.Fatalf("access token dicabut %v, ingin %v", revoked, tt.wantErr == nil)
+			}
+		})
+	}
+}
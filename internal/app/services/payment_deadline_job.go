@@ -0,0 +1,82 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// paymentDeadlineBatchSize membatasi jumlah booking yang diproses per putaran job
+const paymentDeadlineBatchSize = 100
+
+// PaymentDeadlineJob membatalkan booking pending yang belum dibayar melewati PaymentDueAt
+type PaymentDeadlineJob struct {
+	bookingRepo repositories.BookingRepository
+	transactor  repositories.Transactor
+	publisher   EventPublisher
+	clock       Clock
+	interval    time.Duration
+}
+
+func NewPaymentDeadlineJob(bRepo repositories.BookingRepository, transactor repositories.Transactor, publisher EventPublisher, clock Clock, interval time.Duration) *PaymentDeadlineJob {
+	return &PaymentDeadlineJob{bookingRepo: bRepo, transactor: transactor, publisher: publisher, clock: clock, interval: interval}
+}
+
+// RunOnce membatalkan satu batch booking yang lewat batas bayar dan mengembalikan jumlahnya.
+// Aman dijalankan di banyak instance sekaligus: setiap booking di-lock (FOR UPDATE) dan
+// kondisinya dicek ulang di dalam transaksi, sehingga hanya satu instance yang membatalkan.
+func (j *PaymentDeadlineJob) RunOnce() (int, error) {
+	now := j.clock.Now()
+	candidates, err := j.bookingRepo.FindOverduePending(now, paymentDeadlineBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, candidate := range candidates {
+		var booking *models.Booking
+		err := j.transactor.WithinTransaction(func(tx repositories.TxRepositories) error {
+			locked, err := tx.Bookings.LockByID(candidate.ID)
+			if err != nil {
+				return err
+			}
+			// Booking mungkin sudah dibayar/dibatalkan oleh proses lain sejak query kandidat
+			if !locked.IsPaymentOverdue(now) {
+				return nil
+			}
+			booking = locked
+			return applyTransition(tx, locked, models.StatusCancelled, 0, "batas waktu pembayaran terlewati", now)
+		})
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, models.ErrInvalidTransition) {
+				continue
+			}
+			return cancelled, err
+		}
+		if booking == nil {
+			continue
+		}
+
+		cancelled++
+		j.publisher.Publish(Event{
+			Type:       EventBookingPaymentExpired,
+			BookingID:  booking.ID,
+			UserID:     booking.UserID,
+			OccurredAt: now,
+			Message:    "pemesanan dibatalkan otomatis karena belum dibayar sampai batas waktu",
+		})
+	}
+	return cancelled, nil
+}
+
+// Run menjalankan RunOnce setiap interval sampai ctx dibatalkan
+func (j *PaymentDeadlineJob) Run(ctx context.Context) {
+	runPeriodically(ctx, "payment-deadline", j.interval, func() error {
+		_, err := j.RunOnce()
+		return err
+	})
+}
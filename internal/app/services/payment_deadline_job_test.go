@@ -0,0 +1,301 @@
+package services_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	domainrepos "backend/internal/domain/repositories"
+	"backend/internal/infra/database/mysql"
+)
+
+// recordingPublisher menyimpan event yang dikirim agar test dapat menghitungnya
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []services.Event
+}
+
+func (p *recordingPublisher) Publish(event services.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+// count menghitung event berjenis eventType untuk booking tersebut
+func (p *recordingPublisher) count(eventType string, bookingID uint) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, event := range p.events {
+		if event.Type == eventType && event.BookingID == bookingID {
+			n++
+		}
+	}
+	return n
+}
+
+// pendingBooking membuat booking pending milik member baru untuk tipe kamar tersebut
+func (e *testEnv) pendingBooking(roomTypeID uint, username string) *models.Booking {
+	e.t.Helper()
+
+	member := e.createMember(username)
+	checkIn, checkOut := stay(7, 2)
+	booking, err := e.bookings.CreateBooking(newBooking(member.ID, roomTypeID, checkIn, checkOut), services.BookingOptions{})
+	if err != nil {
+		e.t.Fatalf("gagal membuat booking: %v", err)
+	}
+	return booking
+}
+
+func (e *testEnv) paymentDeadlineJob(bookingRepo domainrepos.BookingRepository, publisher services.EventPublisher) *services.PaymentDeadlineJob {
+	return services.NewPaymentDeadlineJob(bookingRepo, e.transactor, publisher, e.clock, time.Minute)
+}
+
+func TestPaymentDeadlineJobRunOnce(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration // Waktu berlalu sejak booking dibuat (PaymentDeadlineMinutes = 60)
+		want    string
+	}{
+		{name: "sebelum batas bayar booking tetap", elapsed: 59 * time.Minute, want: models.StatusPending},
+		{name: "tepat saat batas bayar booking dibatalkan", elapsed: 60 * time.Minute, want: models.StatusCancelled},
+		{name: "setelah batas bayar booking dibatalkan", elapsed: 61 * time.Minute, want: models.StatusCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomType, _ := env.createRoomType("DLX", 500000, 1)
+			booking := env.pendingBooking(roomType.ID, "guest")
+
+			env.clock.Advance(tt.elapsed)
+			publisher := &recordingPublisher{}
+			cancelled, err := env.paymentDeadlineJob(env.bookingRepo, publisher).RunOnce()
+			if err != nil {
+				t.Fatalf("RunOnce: %v", err)
+			}
+			if got := env.bookingStatus(booking.ID); got != tt.want {
+				t.Fatalf("status booking = %q, ingin %q", got, tt.want)
+			}
+			want := map[bool]int{true: 1, false: 0}[tt.want == models.StatusCancelled]
+			if cancelled != want {
+				t.Fatalf("RunOnce mengembalikan %d, ingin %d", cancelled, want)
+			}
+			if n := publisher.count(services.EventBookingPaymentExpired, booking.ID); n != want {
+				t.Fatalf("%d event %s terkirim, ingin %d", n, services.EventBookingPaymentExpired, want)
+			}
+		})
+	}
+}
+
+// TestPaymentDeadlineJobReleasesNights memastikan booking yang dibatalkan job melepas malam kamar
+// fisiknya dan inventori tipe kamarnya dapat dipesan lagi
+func TestPaymentDeadlineJobReleasesNights(t *testing.T) {
+	env := newTestEnv(t)
+	roomType, rooms := env.createRoomType("DLX", 500000, 1)
+	booking := env.pendingBooking(roomType.ID, "guest")
+
+	// Kamar fisik sudah ditempatkan (mis. lewat room board) sehingga malamnya tercatat di room_nights
+	booking.RoomID = &rooms[0].ID
+	if err := env.db.Model(&models.Booking{}).Where("id = ?", booking.ID).Update("room_id", rooms[0].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := env.bookingRepo.ReserveNights(booking); err != nil {
+		t.Fatal(err)
+	}
+
+	nights := func() int64 {
+		t.Helper()
+		var count int64
+		if err := env.db.Model(&models.RoomNight{}).Where("booking_id = ?", booking.ID).Count(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+	if n := nights(); n != 2 {
+		t.Fatalf("ingin 2 malam tercatat, dapat %d", n)
+	}
+
+	env.clock.Advance(61 * time.Minute)
+	if _, err := env.paymentDeadlineJob(env.bookingRepo, &recordingPublisher{}).RunOnce(); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if got := env.bookingStatus(booking.ID); got != models.StatusCancelled {
+		t.Fatalf("status booking = %q, ingin %q", got, models.StatusCancelled)
+	}
+	if n := nights(); n != 0 {
+		t.Fatalf("ingin malam dilepas, masih %d", n)
+	}
+
+	// Kamar terakhir tipe tersebut kembali dapat dipesan
+	env.pendingBooking(roomType.ID, "next")
+}
+
+// staleOverdueRepository mengembalikan daftar kandidat yang sudah usang, seperti job yang membaca
+// kandidat tepat sebelum tamu melunasi booking tersebut
+type staleOverdueRepository struct {
+	domainrepos.BookingRepository
+	bookings []models.Booking
+}
+
+func (r *staleOverdueRepository) FindOverduePending(now time.Time, limit int) ([]models.Booking, error) {
+	return r.bookings, nil
+}
+
+// TestPaymentDeadlineJobSkipsPaidBooking memastikan booking yang dibayar setelah kandidat dibaca
+// tetapi sebelum dikunci tidak dibatalkan
+// TestPaymentDeadlineJobCancelsFailedPayment memastikan percobaan pembayaran yang gagal tidak
+// membuat booking lolos dari batas pembayaran dan menahan inventori selamanya
+func TestPaymentDeadlineJobCancelsFailedPayment(t *testing.T) {
+	env := newTestEnv(t)
+	booking, intent := env.pendingBookingWithIntent()
+
+	payload, signature := env.signedWebhook(models.PaymentEventFailed, intent)
+	if err := env.payments.HandleWebhook(payload, signature); err != nil {
+		t.Fatalf("HandleWebhook: %v", err)
+	}
+	if failed := env.reloadBooking(booking.ID); failed.PaymentStatus != models.StatusFailed {
+		t.Fatalf("status pembayaran = %q, ingin %q", failed.PaymentStatus, models.StatusFailed)
+	}
+
+	env.clock.Advance(60 * time.Minute)
+	publisher := &recordingPublisher{}
+	cancelled, err := env.paymentDeadlineJob(env.bookingRepo, publisher).RunOnce()
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if cancelled != 1 || env.bookingStatus(booking.ID) != models.StatusCancelled {
+		t.Fatalf("ingin 1 booking dibatalkan, dapat %d (status %q)", cancelled, env.bookingStatus(booking.ID))
+	}
+	if n := publisher.count(services.EventBookingPaymentExpired, booking.ID); n != 1 {
+		t.Fatalf("ingin 1 event pembatalan, dapat %d", n)
+	}
+
+	// Kamar satu-satunya tipe tersebut kembali dapat dipesan
+	env.pendingBooking(booking.RoomTypeID, "next")
+}
+
+func TestPaymentDeadlineJobSkipsPaidBooking(t *testing.T) {
+	env := newTestEnv(t)
+	roomType, _ := env.createRoomType("DLX", 500000, 1)
+	booking := env.pendingBooking(roomType.ID, "guest")
+
+	env.clock.Advance(61 * time.Minute)
+	candidates, err := env.bookingRepo.FindOverduePending(env.clock.Now(), 0)
+	if err != nil || len(candidates) != 1 {
+		t.Fatalf("ingin 1 kandidat, dapat %d (%v)", len(candidates), err)
+	}
+
+	// Pembayaran diterima di front desk sebelum job sempat mengunci booking
+	if _, err := env.payments.RecordPayment(booking.ID, &models.Payment{Method: "cash"}); err != nil {
+		t.Fatalf("RecordPayment: %v", err)
+	}
+
+	publisher := &recordingPublisher{}
+	job := env.paymentDeadlineJob(&staleOverdueRepository{BookingRepository: env.bookingRepo, bookings: candidates}, publisher)
+	cancelled, err := job.RunOnce()
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if cancelled != 0 {
+		t.Fatalf("RunOnce mengembalikan %d, ingin 0", cancelled)
+	}
+	if got := env.bookingStatus(booking.ID); got != models.StatusConfirmed {
+		t.Fatalf("status booking = %q, ingin %q", got, models.StatusConfirmed)
+	}
+	if n := publisher.count(services.EventBookingPaymentExpired, booking.ID); n != 0 {
+		t.Fatalf("%d event %s terkirim untuk booking yang sudah dibayar", n, services.EventBookingPaymentExpired)
+	}
+}
+
+// TestPaymentDeadlineJobConcurrent memastikan beberapa instance job yang berjalan bersamaan
+// membatalkan setiap booking tepat sekali dan hanya mengirim satu event per booking
+func TestPaymentDeadlineJobConcurrent(t *testing.T) {
+	const bookings, workers = 3, 4
+
+	env := newTestEnv(t)
+	roomType, _ := env.createRoomType("DLX", 500000, bookings)
+	ids := make([]uint, bookings)
+	for i := range ids {
+		ids[i] = env.pendingBooking(roomType.ID, fmt.Sprintf("guest%d", i)).ID
+	}
+
+	env.clock.Advance(61 * time.Minute)
+	publisher := &recordingPublisher{}
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		total     int
+		runErrors = make([]error, workers)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cancelled, err := env.paymentDeadlineJob(env.bookingRepo, publisher).RunOnce()
+			runErrors[i] = err
+			mu.Lock()
+			total += cancelled
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range runErrors {
+		if err != nil {
+			t.Errorf("job %d gagal: %v", i, err)
+		}
+	}
+	if total != bookings {
+		t.Errorf("total dibatalkan %d, ingin %d", total, bookings)
+	}
+	for _, id := range ids {
+		if got := env.bookingStatus(id); got != models.StatusCancelled {
+			t.Errorf("booking %d berstatus %q, ingin %q", id, got, models.StatusCancelled)
+		}
+		var transitions int64
+		if err := env.db.Model(&models.BookingTransition{}).Where("booking_id = ? AND to_status = ?", id, models.StatusCancelled).Count(&transitions).Error; err != nil {
+			t.Fatal(err)
+		}
+		if transitions != 1 {
+			t.Errorf("booking %d tercatat %d kali dibatalkan, ingin 1", id, transitions)
+		}
+		if n := publisher.count(services.EventBookingPaymentExpired, id); n != 1 {
+			t.Errorf("booking %d mendapat %d event %s, ingin 1", id, n, services.EventBookingPaymentExpired)
+		}
+	}
+}
+
+// TestPaymentDeadlineJobCancelsLegacyBookings memastikan booking pending lama tanpa batas bayar
+// mendapat batas dari BackfillPaymentDueAt lalu ikut dibatalkan job
+func TestPaymentDeadlineJobCancelsLegacyBookings(t *testing.T) {
+	env := newTestEnv(t)
+	roomType, _ := env.createRoomType("DLX", 500000, 1)
+	booking := env.pendingBooking(roomType.ID, "guest")
+
+	// Bentuk booking sebelum ada batas pembayaran, dibuat 30 menit sebelum testNow
+	err := env.db.Model(&models.Booking{}).Where("id = ?", booking.ID).
+		UpdateColumns(map[string]interface{}{"payment_due_at": nil, "created_at": testNow.Add(-30 * time.Minute)}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env.clock.Advance(31 * time.Minute)
+	job := env.paymentDeadlineJob(env.bookingRepo, &recordingPublisher{})
+	if cancelled, err := job.RunOnce(); err != nil || cancelled != 0 {
+		t.Fatalf("sebelum backfill ingin 0 dibatalkan, dapat %d (%v)", cancelled, err)
+	}
+
+	mysql.BackfillPaymentDueAt(env.root, time.Duration(env.cfg.PaymentDeadlineMinutes)*time.Minute)
+	if due := env.reloadBooking(booking.ID).PaymentDueAt; due == nil || !due.Equal(testNow.Add(30*time.Minute)) {
+		t.Fatalf("batas bayar hasil backfill %v, ingin %v", due, testNow.Add(30*time.Minute))
+	}
+	if cancelled, err := job.RunOnce(); err != nil || cancelled != 1 {
+		t.Fatalf("setelah backfill ingin 1 dibatalkan, dapat %d (%v)", cancelled, err)
+	}
+	if got := env.bookingStatus(booking.ID); got != models.StatusCancelled {
+		t.Fatalf("status booking = %q, ingin %q", got, models.StatusCancelled)
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import "backend/internal/domain/money"
+
+// --- Status Payment Intent/Refund di Provider ---
+const (
+	IntentRequiresPayment = "requires_payment"
+	IntentSucceeded       = "succeeded"
+	IntentFailed          = "failed"
+	RefundSucceeded       = "succeeded"
+	RefundPending         = "pending"
+)
+
+// PaymentIntent adalah permintaan pembayaran di provider untuk sebuah booking
+type PaymentIntent struct {
+	ID           string      `json:"id"`
+	Provider     string      `json:"provider"`
+	Amount       money.Money `json:"amount"`
+	Status       string      `json:"status"`
+	ClientSecret string      `json:"client_secret,omitempty"` // Dipakai frontend untuk menyelesaikan pembayaran
+}
+
+// PaymentRefund adalah hasil permintaan refund ke provider
+type PaymentRefund struct {
+	ID       string      `json:"id"`
+	IntentID string      `json:"intent_id"`
+	Amount   money.Money `json:"amount"`
+	Status   string      `json:"status"`
+}
+
+// PaymentEvent adalah event webhook yang sudah diverifikasi signature-nya
+type PaymentEvent struct {
+	ID       string
+	Type     string // models.PaymentEvent*
+	IntentID string
+	RefundID string // Hanya untuk event refund
+	Amount   money.Money
+}
+
+// PaymentProvider adalah abstraksi payment gateway. Implementasi ada di internal/infra/payments.
+type PaymentProvider interface {
+	Name() string
+	// CreateIntent membuat payment intent; reference adalah referensi internal (mis. booking ID)
+	CreateIntent(reference string, amount money.Money) (*PaymentIntent, error)
+	// Capture menagih intent yang sudah diotorisasi
+	Capture(intentID string, amount money.Money) (*PaymentIntent, error)
+	Refund(intentID string, amount money.Money) (*PaymentRefund, error)
+	// VerifyWebhook memverifikasi signature payload lalu mengembalikan event-nya,
+	// models.ErrInvalidWebhookSignature jika signature tidak valid
+	VerifyWebhook(payload []byte, signature string) (*PaymentEvent, error)
+}
+
+// WebhookSimulator diimplementasikan provider lokal (mock) untuk membuat webhook bertanda tangan
+// tanpa gateway sungguhan, sehingga alur pembayaran bisa dijalankan end-to-end secara lokal.
+type WebhookSimulator interface {
+	SimulateWebhook(eventType, intentID string, amount money.Money) (payload []byte, signature string, err error)
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/internal/domain/repositories"
+)
+
+// PaymentService mendefinisikan kontrak untuk ledger pembayaran booking (melalui PaymentProvider atau manual)
+type PaymentService interface {
+	// Untuk Member
+	// CreatePaymentIntent membuat intent sebesar amount (mis. deposit); amount nol berarti seluruh sisa tagihan
+	CreatePaymentIntent(bookingID uint, userID uint, amount money.Money) (*PaymentIntent, error)
+	// GetPaymentSummary mengembalikan ledger & sisa tagihan; userID 0 berarti admin (tanpa cek kepemilikan)
+	GetPaymentSummary(bookingID uint, userID uint) (*models.PaymentSummary, error)
+
+	// Untuk Admin
+	// RecordPayment mencatat pembayaran manual (tunai, transfer, EDC di front desk)
+	RecordPayment(bookingID uint, payment *models.Payment) (*models.Booking, error)
+	CapturePayment(bookingID uint) (*models.Booking, error)
+	// RefundPayment mengembalikan amount; amount nol berarti seluruh pembayaran bersih
+	RefundPayment(bookingID uint, amount money.Money, performedBy uint, note string) (*models.Booking, error)
+	// RefundWithin sama seperti RefundPayment di dalam transaksi milik pemanggil (mis. pembatalan booking).
+	// Booking harus sudah dikunci beserta Payments-nya.
+	RefundWithin(tx repositories.TxRepositories, booking *models.Booking, amount money.Money, performedBy uint, note string) error
+
+	// HandleWebhook memverifikasi signature lalu menerapkan event ke ledger pembayaran booking
+	HandleWebhook(payload []byte, signature string) error
+	// WebhookTenant memverifikasi signature lalu mengembalikan tenant pemilik pembayaran event-nya.
+	// Dipakai service dengan koneksi tanpa tenant untuk meneruskan webhook ke tenant yang tepat.
+	WebhookTenant(payload []byte, signature string) (uint, error)
+	// SimulateWebhook mengirim webhook bertanda tangan untuk intent milik userID (hanya provider lokal/mock)
+	SimulateWebhook(intentID string, userID uint, eventType string) error
+}
@@ -0,0 +1,492 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/internal/domain/repositories"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type paymentServiceImpl struct {
+	bookingRepo repositories.BookingRepository
+	paymentRepo repositories.PaymentRepository
+	transactor  repositories.Transactor
+	provider    PaymentProvider
+	publisher   EventPublisher
+	clock       Clock
+}
+
+func NewPaymentService(bRepo repositories.BookingRepository, pRepo repositories.PaymentRepository, transactor repositories.Transactor, provider PaymentProvider, publisher EventPublisher, clock Clock) PaymentService {
+	return &paymentServiceImpl{bookingRepo: bRepo, paymentRepo: pRepo, transactor: transactor, provider: provider, publisher: publisher, clock: clock}
+}
+
+// paymentAmount memvalidasi nominal pembayaran terhadap sisa tagihan; nominal nol berarti seluruh sisa tagihan
+func paymentAmount(booking *models.Booking, amount money.Money) (money.Money, error) {
+	outstanding := booking.PaymentSummary().Outstanding
+	if amount.IsZero() {
+		amount = outstanding
+	}
+	if amount.Currency != "" && amount.Currency != booking.GuestTotal.Currency {
+		return money.Money{}, models.ErrPaymentCurrencyMismatch
+	}
+	amount.Currency = booking.GuestTotal.Currency
+	if !amount.IsPositive() || amount.Sub(outstanding).IsPositive() {
+		return money.Money{}, models.ErrInvalidPaymentAmount
+	}
+	return amount, nil
+}
+
+// CreatePaymentIntent: Membuat payment intent untuk sebagian (deposit) atau seluruh sisa tagihan tamu
+func (s *paymentServiceImpl) CreatePaymentIntent(bookingID uint, userID uint, amount money.Money) (*PaymentIntent, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.UserID != userID {
+		return nil, models.ErrBookingForbidden
+	}
+	if !booking.AcceptsPayment() || booking.PaymentStatus == models.StatusPaid {
+		return nil, models.ErrPaymentNotAllowed
+	}
+	amount, err = paymentAmount(booking, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	intent, err := s.provider.CreateIntent(fmt.Sprintf("booking-%d", booking.ID), amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Intent dicatat sebagai pembayaran pending; statusnya diperbarui oleh webhook
+	err = s.paymentRepo.Create(&models.Payment{
+		TenantID:          booking.TenantID,
+		BookingID:         booking.ID,
+		Kind:              models.PaymentKindPayment,
+		Amount:            amount,
+		Currency:          amount.Currency,
+		Method:            s.provider.Name(),
+		Provider:          s.provider.Name(),
+		ProviderReference: intent.ID,
+		Status:            models.PaymentPending,
+		RecordedBy:        userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return intent, nil
+}
+
+// GetPaymentSummary: Mengambil ledger pembayaran beserta total dibayar & sisa tagihan
+func (s *paymentServiceImpl) GetPaymentSummary(bookingID uint, userID uint) (*models.PaymentSummary, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != 0 && booking.UserID != userID {
+		return nil, models.ErrBookingForbidden
+	}
+	summary := booking.PaymentSummary()
+	return &summary, nil
+}
+
+// RecordPayment: Mencatat pembayaran manual yang diterima di front desk (Admin)
+func (s *paymentServiceImpl) RecordPayment(bookingID uint, payment *models.Payment) (*models.Booking, error) {
+	if payment.Method == "" {
+		return nil, errors.New("metode pembayaran wajib diisi")
+	}
+
+	var paidBooking *models.Booking
+	err := s.transactor.WithinTransaction(func(tx repositories.TxRepositories) error {
+		booking, err := lockBookingPayments(tx, bookingID)
+		if err != nil {
+			return err
+		}
+		if !booking.AcceptsPayment() {
+			return models.ErrPaymentNotAllowed
+		}
+		amount, err := paymentAmount(booking, payment.Amount)
+		if err != nil {
+			return err
+		}
+
+		payment.TenantID = booking.TenantID
+		payment.BookingID = booking.ID
+		payment.Kind = models.PaymentKindPayment
+		payment.Amount = amount
+		payment.Currency = amount.Currency
+		payment.Status = models.PaymentSucceeded
+		if err := tx.Payments.Create(payment); err != nil {
+			return err
+		}
+		paidBooking, err = s.settle(tx, booking, payment.RecordedBy, "pembayaran manual diterima")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishPaid(paidBooking, payment.Method)
+	return s.bookingRepo.FindByID(bookingID)
+}
+
+// CapturePayment: Menagih intent terakhir booking yang sudah diotorisasi (Admin)
+func (s *paymentServiceImpl) CapturePayment(bookingID uint) (*models.Booking, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !booking.AcceptsPayment() {
+		return nil, models.ErrPaymentNotAllowed
+	}
+
+	var pending *models.Payment
+	for i := range booking.Payments {
+		payment := &booking.Payments[i]
+		if payment.Kind == models.PaymentKindPayment && payment.Status == models.PaymentPending && payment.Provider == s.provider.Name() {
+			pending = payment
+		}
+	}
+	if pending == nil {
+		return nil, models.ErrPaymentNotFound
+	}
+
+	intent, err := s.provider.Capture(pending.ProviderReference, pending.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status == IntentSucceeded {
+		event := &PaymentEvent{ID: "capture:" + intent.ID, Type: models.PaymentEventSucceeded, IntentID: intent.ID, Amount: intent.Amount}
+		if err := s.applyEvent(event, false); err != nil {
+			return nil, err
+		}
+	}
+	return s.bookingRepo.FindByID(bookingID)
+}
+
+// RefundPayment: Mengembalikan pembayaran booking, dialokasikan dari pembayaran terbaru (Admin).
+// Pembayaran lewat provider direfund melalui provider, pembayaran manual langsung dicatat.
+func (s *paymentServiceImpl) RefundPayment(bookingID uint, amount money.Money, performedBy uint, note string) (*models.Booking, error) {
+	err := s.transactor.WithinTransaction(func(tx repositories.TxRepositories) error {
+		booking, err := lockBookingPayments(tx, bookingID)
+		if err != nil {
+			return err
+		}
+		return s.RefundWithin(tx, booking, amount, performedBy, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.bookingRepo.FindByID(bookingID)
+}
+
+// RefundWithin mengalokasikan amount ke pembayaran yang berhasil (terbaru lebih dulu) lalu mencatat refund-nya.
+// Booking harus sudah dikunci beserta Payments-nya. Provider dipanggil di dalam transaksi agar refund
+// paralel tidak melebihi total dibayar; jika transaksi gagal setelah provider berhasil, webhook
+// refund.succeeded akan mencatat ulang refund tersebut.
+func (s *paymentServiceImpl) RefundWithin(tx repositories.TxRepositories, booking *models.Booking, amount money.Money, performedBy uint, note string) error {
+	summary := booking.PaymentSummary()
+	net := summary.Paid.Sub(summary.Refunded)
+	if !net.IsPositive() {
+		return models.ErrRefundNotAllowed
+	}
+	if amount.IsZero() {
+		amount = net
+	}
+	if amount.Currency != "" && amount.Currency != booking.GuestTotal.Currency {
+		return models.ErrPaymentCurrencyMismatch
+	}
+	amount.Currency = booking.GuestTotal.Currency
+	if !amount.IsPositive() || amount.Sub(net).IsPositive() {
+		return models.ErrInvalidRefundAmount
+	}
+
+	remaining := amount
+	for i := len(booking.Payments) - 1; i >= 0 && remaining.IsPositive(); i-- {
+		payment := booking.Payments[i]
+		if payment.Kind != models.PaymentKindPayment || payment.Status != models.PaymentSucceeded {
+			continue
+		}
+		part := booking.RefundableAmount(payment).Min(remaining)
+		if !part.IsPositive() {
+			continue
+		}
+
+		refund := &models.Payment{
+			TenantID:   booking.TenantID,
+			BookingID:  booking.ID,
+			Kind:       models.PaymentKindRefund,
+			Amount:     part,
+			Currency:   part.Currency,
+			Method:     payment.Method,
+			Provider:   payment.Provider,
+			Status:     models.PaymentSucceeded,
+			RefundOfID: &payment.ID,
+			Note:       note,
+			RecordedBy: performedBy,
+		}
+		if payment.Provider != "" {
+			if payment.Provider != s.provider.Name() {
+				return fmt.Errorf("pembayaran #%d dibuat melalui provider %s yang tidak aktif", payment.ID, payment.Provider)
+			}
+			result, err := s.provider.Refund(payment.ProviderReference, part)
+			if err != nil {
+				return err
+			}
+			refund.ProviderReference = result.ID
+			// Refund yang masih pending diselesaikan oleh webhook refund.succeeded
+			if result.Status != RefundSucceeded {
+				refund.Status = models.PaymentPending
+			}
+		}
+		if err := tx.Payments.Create(refund); err != nil {
+			return err
+		}
+		remaining = remaining.Sub(part)
+	}
+
+	_, err := s.settle(tx, booking, performedBy, note)
+	return err
+}
+
+// HandleWebhook: Verifikasi signature webhook lalu terapkan event-nya (idempoten per event ID)
+func (s *paymentServiceImpl) HandleWebhook(payload []byte, signature string) error {
+	event, err := s.provider.VerifyWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+	return s.applyEvent(event, true)
+}
+
+// WebhookTenant: Mencari tenant pemilik pembayaran yang dirujuk webhook. Provider dan secret webhook
+// dipakai bersama semua tenant, sehingga tenant ditentukan dari pembayaran, bukan dari request.
+func (s *paymentServiceImpl) WebhookTenant(payload []byte, signature string) (uint, error) {
+	event, err := s.provider.VerifyWebhook(payload, signature)
+	if err != nil {
+		return 0, err
+	}
+	payment, err := s.paymentRepo.FindByReference(s.provider.Name(), event.IntentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, models.ErrPaymentNotFound
+		}
+		return 0, err
+	}
+	return payment.TenantID, nil
+}
+
+// SimulateWebhook: Membuat & memproses webhook bertanda tangan lewat jalur yang sama dengan provider asli.
+// Hanya pemilik booking yang boleh mensimulasikan pembayaran intent-nya.
+func (s *paymentServiceImpl) SimulateWebhook(intentID string, userID uint, eventType string) error {
+	simulator, ok := s.provider.(WebhookSimulator)
+	if !ok {
+		return errors.New("payment provider tidak mendukung simulasi webhook")
+	}
+
+	payment, err := s.paymentRepo.FindByReference(s.provider.Name(), intentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrPaymentNotFound
+		}
+		return err
+	}
+	booking, err := s.bookingRepo.FindByID(payment.BookingID)
+	if err != nil {
+		return err
+	}
+	if booking.UserID != userID {
+		return models.ErrBookingForbidden
+	}
+
+	payload, signature, err := simulator.SimulateWebhook(eventType, intentID, payment.Amount)
+	if err != nil {
+		return err
+	}
+	return s.HandleWebhook(payload, signature)
+}
+
+// applyEvent menerapkan event provider ke ledger pembayaran di dalam satu transaksi.
+// record true berarti event berasal dari webhook dan dicatat agar retry tidak diproses dua kali.
+func (s *paymentServiceImpl) applyEvent(event *PaymentEvent, record bool) error {
+	var paidBooking *models.Booking
+	err := s.transactor.WithinTransaction(func(tx repositories.TxRepositories) error {
+		intent, err := tx.Payments.FindByReference(s.provider.Name(), event.IntentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrPaymentNotFound
+			}
+			return err
+		}
+		booking, err := lockBookingPayments(tx, intent.BookingID)
+		if err != nil {
+			return err
+		}
+
+		if record {
+			fresh, err := tx.Webhooks.Record(&models.PaymentWebhookEvent{
+				TenantID:    booking.TenantID,
+				Provider:    s.provider.Name(),
+				EventID:     event.ID,
+				Type:        event.Type,
+				BookingID:   booking.ID,
+				ProcessedAt: s.clock.Now(),
+			})
+			if err != nil || !fresh {
+				return err
+			}
+		}
+
+		// Baca ulang setelah booking dikunci agar tidak memakai status yang sudah basi
+		payment, err := tx.Payments.FindByID(intent.ID)
+		if err != nil {
+			return err
+		}
+
+		switch event.Type {
+		case models.PaymentEventSucceeded:
+			if payment.Status == models.PaymentSucceeded {
+				return nil
+			}
+			if event.Amount.Currency != payment.Amount.Currency || event.Amount.Amount != payment.Amount.Amount {
+				return models.ErrPaymentAmountMismatch
+			}
+			payment.Status = models.PaymentSucceeded
+
+		case models.PaymentEventFailed:
+			if payment.Status != models.PaymentPending {
+				return nil
+			}
+			payment.Status = models.PaymentFailed
+
+		case models.RefundEventSucceeded:
+			return s.applyRefundEvent(tx, booking, payment, event)
+
+		default:
+			return nil // Jenis event lain diabaikan
+		}
+
+		if err := tx.Payments.Update(payment); err != nil {
+			return err
+		}
+		if payment.Status == models.PaymentSucceeded && !booking.AcceptsPayment() {
+			return s.refundLatePayment(tx, booking, payment)
+		}
+		paidBooking, err = s.settle(tx, booking, 0, "pembayaran diterima")
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publishPaid(paidBooking, s.provider.Name())
+	return nil
+}
+
+// refundLatePayment mengembalikan pembayaran provider yang baru berhasil setelah booking tidak lagi
+// menerima pembayaran (dibatalkan, expired, no-show). Malamnya sudah dilepas dan mungkin sudah dijual
+// ulang, jadi booking tidak boleh dilunasi; dana dicatat lalu langsung direfund ke tamu.
+func (s *paymentServiceImpl) refundLatePayment(tx repositories.TxRepositories, booking *models.Booking, payment *models.Payment) error {
+	var err error
+	if booking.Payments, err = tx.Payments.FindByBookingID(booking.ID); err != nil {
+		return err
+	}
+	note := "refund otomatis, pembayaran diterima setelah booking " + booking.BookingStatus
+	return s.RefundWithin(tx, booking, booking.RefundableAmount(*payment), 0, note)
+}
+
+// applyRefundEvent menyelesaikan refund pending, atau mencatat refund yang dibuat langsung di provider
+func (s *paymentServiceImpl) applyRefundEvent(tx repositories.TxRepositories, booking *models.Booking, payment *models.Payment, event *PaymentEvent) error {
+	if event.RefundID == "" {
+		return errors.New("event refund tidak memiliki refund ID")
+	}
+	refund, err := tx.Payments.FindByReference(s.provider.Name(), event.RefundID)
+	switch {
+	case err == nil:
+		if refund.Status == models.PaymentSucceeded {
+			return nil
+		}
+		refund.Status = models.PaymentSucceeded
+		err = tx.Payments.Update(refund)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if event.Amount.Currency != payment.Amount.Currency || event.Amount.Sub(booking.RefundableAmount(*payment)).IsPositive() {
+			return models.ErrPaymentAmountMismatch
+		}
+		err = tx.Payments.Create(&models.Payment{
+			TenantID:          booking.TenantID,
+			BookingID:         booking.ID,
+			Kind:              models.PaymentKindRefund,
+			Amount:            event.Amount,
+			Currency:          event.Amount.Currency,
+			Method:            payment.Method,
+			Provider:          payment.Provider,
+			ProviderReference: event.RefundID,
+			Status:            models.PaymentSucceeded,
+			RefundOfID:        &payment.ID,
+			Note:              "refund dari provider",
+		})
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.settle(tx, booking, 0, "refund diterima")
+	return err
+}
+
+// settle menghitung ulang status pembayaran booking dari ledger. Booking pending otomatis
+// terkonfirmasi setelah pembayaran pertama (deposit) berhasil. Mengembalikan booking jika baru lunas.
+func (s *paymentServiceImpl) settle(tx repositories.TxRepositories, booking *models.Booking, performedBy uint, note string) (*models.Booking, error) {
+	payments, err := tx.Payments.FindByBookingID(booking.ID)
+	if err != nil {
+		return nil, err
+	}
+	previous := booking.PaymentStatus
+	booking.Payments = payments
+	booking.RefreshPaymentStatus()
+	if booking.PaymentStatus == previous {
+		return nil, nil
+	}
+	if err := tx.Bookings.Update(booking); err != nil {
+		return nil, err
+	}
+
+	paidNow := booking.PaymentStatus == models.StatusPaid || booking.PaymentStatus == models.StatusPartiallyPaid
+	if paidNow && booking.BookingStatus == models.StatusPending {
+		if err := applyTransition(tx, booking, models.StatusConfirmed, performedBy, note, s.clock.Now()); err != nil {
+			return nil, err
+		}
+	}
+	if booking.PaymentStatus == models.StatusPaid {
+		return booking, nil
+	}
+	return nil, nil
+}
+
+// publishPaid mengirim event booking lunas setelah transaksi di-commit
+func (s *paymentServiceImpl) publishPaid(booking *models.Booking, method string) {
+	if booking == nil {
+		return
+	}
+	s.publisher.Publish(Event{
+		Type:       EventBookingPaid,
+		BookingID:  booking.ID,
+		UserID:     booking.UserID,
+		OccurredAt: s.clock.Now(),
+		Message:    "pembayaran lunas melalui " + method,
+	})
+}
+
+// lockBookingPayments mengunci booking (SELECT ... FOR UPDATE) lalu memuat ledger pembayarannya
+func lockBookingPayments(tx repositories.TxRepositories, bookingID uint) (*models.Booking, error) {
+	booking, err := tx.Bookings.LockByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	booking.Payments, err = tx.Payments.FindByBookingID(booking.ID)
+	if err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
@@ -0,0 +1,274 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/internal/infra/database/mysql/mysqltest"
+	"backend/internal/infra/gorm/repositories"
+)
+
+// pendingBookingWithIntent membuat booking pending milik member baru beserta payment intent untuk seluruh tagihannya
+func (e *testEnv) pendingBookingWithIntent() (*models.Booking, *services.PaymentIntent) {
+	e.t.Helper()
+
+	roomType, _ := e.createRoomType("DLX", 500000, 1)
+	member := e.createMember("guest")
+	checkIn, checkOut := stay(7, 2)
+	booking, err := e.bookings.CreateBooking(newBooking(member.ID, roomType.ID, checkIn, checkOut), services.BookingOptions{})
+	if err != nil {
+		e.t.Fatalf("gagal membuat booking: %v", err)
+	}
+	intent, err := e.payments.CreatePaymentIntent(booking.ID, member.ID, money.Money{})
+	if err != nil {
+		e.t.Fatalf("gagal membuat payment intent: %v", err)
+	}
+	return booking, intent
+}
+
+// signedWebhook membuat webhook bertanda tangan dari provider mock
+func (e *testEnv) signedWebhook(eventType string, intent *services.PaymentIntent) ([]byte, string) {
+	e.t.Helper()
+
+	payload, signature, err := e.provider.(services.WebhookSimulator).SimulateWebhook(eventType, intent.ID, intent.Amount)
+	if err != nil {
+		e.t.Fatalf("gagal membuat webhook: %v", err)
+	}
+	return payload, signature
+}
+
+func (e *testEnv) reloadBooking(bookingID uint) *models.Booking {
+	e.t.Helper()
+
+	booking, err := e.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		e.t.Fatalf("gagal membaca booking %d: %v", bookingID, err)
+	}
+	return booking
+}
+
+func TestPaymentWebhookConfirmsBooking(t *testing.T) {
+	env := newTestEnv(t)
+	booking, intent := env.pendingBookingWithIntent()
+
+	payload, signature := env.signedWebhook(models.PaymentEventSucceeded, intent)
+	if err := env.payments.HandleWebhook(payload, signature); err != nil {
+		t.Fatalf("HandleWebhook: %v", err)
+	}
+
+	paid := env.reloadBooking(booking.ID)
+	if paid.BookingStatus != models.StatusConfirmed || paid.PaymentStatus != models.StatusPaid {
+		t.Fatalf("booking %s/%s, ingin %s/%s", paid.BookingStatus, paid.PaymentStatus, models.StatusConfirmed, models.StatusPaid)
+	}
+	if summary := paid.PaymentSummary(); summary.Outstanding.IsPositive() {
+		t.Fatalf("sisa tagihan %d, ingin 0", summary.Outstanding.Amount)
+	}
+}
+
+func TestPaymentWebhookDuplicateIsIdempotent(t *testing.T) {
+	env := newTestEnv(t)
+	booking, intent := env.pendingBookingWithIntent()
+
+	payload, signature := env.signedWebhook(models.PaymentEventSucceeded, intent)
+	for i := 0; i < 3; i++ {
+		if err := env.payments.HandleWebhook(payload, signature); err != nil {
+			t.Fatalf("HandleWebhook ke-%d: %v", i+1, err)
+		}
+	}
+
+	var events, transitions int64
+	if err := env.db.Model(&models.PaymentWebhookEvent{}).Where("booking_id = ?", booking.ID).Count(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := env.db.Model(&models.BookingTransition{}).Where("booking_id = ? AND to_status = ?", booking.ID, models.StatusConfirmed).Count(&transitions).Error; err != nil {
+		t.Fatal(err)
+	}
+	if events != 1 || transitions != 1 {
+		t.Fatalf("ingin 1 event tercatat dan 1 transisi confirmed, dapat %d dan %d", events, transitions)
+	}
+
+	paid := env.reloadBooking(booking.ID)
+	if summary := paid.PaymentSummary(); summary.Paid.Amount != intent.Amount.Amount {
+		t.Fatalf("total dibayar %d, ingin %d", summary.Paid.Amount, intent.Amount.Amount)
+	}
+}
+
+func TestPaymentWebhookRejectsBadSignature(t *testing.T) {
+	env := newTestEnv(t)
+	booking, intent := env.pendingBookingWithIntent()
+	payload, signature := env.signedWebhook(models.PaymentEventSucceeded, intent)
+
+	tests := []struct {
+		name      string
+		payload   []byte
+		signature string
+	}{
+		{name: "tanpa signature", payload: payload, signature: ""},
+		{name: "signature salah", payload: payload, signature: "t=1900000000,v1=deadbeef"},
+		{name: "payload diubah", payload: append([]byte(" "), payload...), signature: signature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := env.payments.HandleWebhook(tt.payload, tt.signature); !errors.Is(err, models.ErrInvalidWebhookSignature) {
+				t.Fatalf("ingin ErrInvalidWebhookSignature, dapat %v", err)
+			}
+		})
+	}
+
+	if got := env.reloadBooking(booking.ID); got.BookingStatus != models.StatusPending || got.PaymentStatus != models.StatusPending {
+		t.Fatalf("booking %s/%s berubah oleh webhook tidak sah", got.BookingStatus, got.PaymentStatus)
+	}
+}
+
+func TestPaymentRefundAfterWebhook(t *testing.T) {
+	env := newTestEnv(t)
+	booking, intent := env.pendingBookingWithIntent()
+
+	payload, signature := env.signedWebhook(models.PaymentEventSucceeded, intent)
+	if err := env.payments.HandleWebhook(payload, signature); err != nil {
+		t.Fatalf("HandleWebhook: %v", err)
+	}
+
+	refunded, err := env.payments.RefundPayment(booking.ID, money.Money{}, 0, "refund penuh")
+	if err != nil {
+		t.Fatalf("RefundPayment: %v", err)
+	}
+	if refunded.PaymentStatus != models.StatusRefunded {
+		t.Fatalf("status pembayaran %s, ingin %s", refunded.PaymentStatus, models.StatusRefunded)
+	}
+	summary := refunded.PaymentSummary()
+	if summary.Refunded.Amount != intent.Amount.Amount {
+		t.Fatalf("total refund %d, ingin %d", summary.Refunded.Amount, intent.Amount.Amount)
+	}
+
+	// Tidak ada lagi dana yang bisa direfund
+	if _, err := env.payments.RefundPayment(booking.ID, money.Money{}, 0, "refund ulang"); !errors.Is(err, models.ErrRefundNotAllowed) {
+		t.Fatalf("ingin ErrRefundNotAllowed, dapat %v", err)
+	}
+}
+
+func TestSimulateWebhookRequiresBookingOwner(t *testing.T) {
+	env := newTestEnv(t)
+	booking, intent := env.pendingBookingWithIntent()
+	other := env.createMember("other")
+
+	if err := env.payments.SimulateWebhook(intent.ID, other.ID, models.PaymentEventSucceeded); !errors.Is(err, models.ErrBookingForbidden) {
+		t.Fatalf("ingin ErrBookingForbidden, dapat %v", err)
+	}
+	if got := env.reloadBooking(booking.ID); got.PaymentStatus != models.StatusPending {
+		t.Fatalf("status pembayaran %s berubah oleh member lain", got.PaymentStatus)
+	}
+
+	if err := env.payments.SimulateWebhook(intent.ID, booking.UserID, models.PaymentEventSucceeded); err != nil {
+		t.Fatalf("SimulateWebhook oleh pemilik: %v", err)
+	}
+	if got := env.reloadBooking(booking.ID); got.PaymentStatus != models.StatusPaid {
+		t.Fatalf("status pembayaran %s, ingin %s", got.PaymentStatus, models.StatusPaid)
+	}
+}
+
+// TestPaymentWebhookRoutedToPaymentTenant memastikan webhook diteruskan ke tenant pemilik pembayaran,
+// dan tenant lain yang memakai provider & secret yang sama tidak dapat menyentuh pembayaran tersebut
+func TestPaymentWebhookRoutedToPaymentTenant(t *testing.T) {
+	env := newTestEnv(t)
+	booking, intent := env.pendingBookingWithIntent()
+	payload, signature := env.signedWebhook(models.PaymentEventSucceeded, intent)
+	publisher := services.NewLogEventPublisher()
+
+	// Koneksi tanpa tenant (aplikasi utama) menemukan tenant dari pembayaran
+	resolver := services.NewPaymentService(repositories.NewGormBookingRepository(env.root), repositories.NewGormPaymentRepository(env.root),
+		repositories.NewGormTransactor(env.root), env.provider, publisher, env.clock)
+	tenantID, err := resolver.WebhookTenant(payload, signature)
+	if err != nil {
+		t.Fatalf("WebhookTenant: %v", err)
+	}
+	if want := env.reloadBooking(booking.ID).TenantID; tenantID != want {
+		t.Fatalf("tenant webhook %d, ingin %d", tenantID, want)
+	}
+	if _, err := resolver.WebhookTenant(payload, ""); !errors.Is(err, models.ErrInvalidWebhookSignature) {
+		t.Fatalf("ingin ErrInvalidWebhookSignature, dapat %v", err)
+	}
+
+	// Aplikasi tenant lain tidak melihat pembayaran tersebut
+	other := repositories.WithTenant(env.root, mysqltest.CreateTenant(t, env.root, "other"))
+	otherPayments := services.NewPaymentService(repositories.NewGormBookingRepository(other), repositories.NewGormPaymentRepository(other),
+		repositories.NewGormTransactor(other), env.provider, publisher, env.clock)
+	if err := otherPayments.HandleWebhook(payload, signature); !errors.Is(err, models.ErrPaymentNotFound) {
+		t.Fatalf("ingin ErrPaymentNotFound dari tenant lain, dapat %v", err)
+	}
+	if got := env.reloadBooking(booking.ID); got.PaymentStatus != models.StatusPending {
+		t.Fatalf("status pembayaran %s berubah oleh tenant lain", got.PaymentStatus)
+	}
+
+	if err := env.payments.HandleWebhook(payload, signature); err != nil {
+		t.Fatalf("HandleWebhook di tenant pemilik: %v", err)
+	}
+	if got := env.reloadBooking(booking.ID); got.PaymentStatus != models.StatusPaid {
+		t.Fatalf("status pembayaran %s, ingin %s", got.PaymentStatus, models.StatusPaid)
+	}
+}
+
+// TestPaymentWebhookAfterCancellationIsRefunded memastikan dana yang baru tertangkap setelah booking
+// dibatalkan tidak melunasi booking (malamnya sudah dilepas), melainkan langsung direfund
+func TestPaymentWebhookAfterCancellationIsRefunded(t *testing.T) {
+	tests := []struct {
+		name   string
+		cancel func(t *testing.T, env *testEnv, booking *models.Booking)
+	}{
+		{
+			name: "dibatalkan job batas pembayaran",
+			cancel: func(t *testing.T, env *testEnv, booking *models.Booking) {
+				env.clock.Advance(61 * time.Minute)
+				if _, err := env.paymentDeadlineJob(env.bookingRepo, &recordingPublisher{}).RunOnce(); err != nil {
+					t.Fatalf("RunOnce: %v", err)
+				}
+			},
+		},
+		{
+			name: "dibatalkan member",
+			cancel: func(t *testing.T, env *testEnv, booking *models.Booking) {
+				if _, err := env.bookings.CancelBooking(booking.ID, booking.UserID); err != nil {
+					t.Fatalf("CancelBooking: %v", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			publisher := &recordingPublisher{}
+			paymentService := services.NewPaymentService(env.bookingRepo, repositories.NewGormPaymentRepository(env.db), env.transactor, env.provider, publisher, env.clock)
+			booking, intent := env.pendingBookingWithIntent()
+
+			tt.cancel(t, env, booking)
+			payload, signature := env.signedWebhook(models.PaymentEventSucceeded, intent)
+			if err := paymentService.HandleWebhook(payload, signature); err != nil {
+				t.Fatalf("HandleWebhook: %v", err)
+			}
+
+			late := env.reloadBooking(booking.ID)
+			if late.BookingStatus != models.StatusCancelled || late.PaymentStatus != models.StatusRefunded {
+				t.Fatalf("booking %s/%s, ingin %s/%s", late.BookingStatus, late.PaymentStatus, models.StatusCancelled, models.StatusRefunded)
+			}
+			summary := late.PaymentSummary()
+			if summary.Paid.Amount != intent.Amount.Amount || summary.Refunded.Amount != intent.Amount.Amount {
+				t.Fatalf("dibayar %d & direfund %d, ingin keduanya %d", summary.Paid.Amount, summary.Refunded.Amount, intent.Amount.Amount)
+			}
+			if n := publisher.count(services.EventBookingPaid, booking.ID); n != 0 {
+				t.Fatalf("ingin tanpa event booking.paid, dapat %d", n)
+			}
+
+			// Retry webhook yang sama tidak merefund dua kali
+			if err := paymentService.HandleWebhook(payload, signature); err != nil {
+				t.Fatalf("HandleWebhook ulang: %v", err)
+			}
+			if again := env.reloadBooking(booking.ID).PaymentSummary(); again.Refunded.Amount != intent.Amount.Amount {
+				t.Fatalf("direfund %d setelah retry, ingin %d", again.Refunded.Amount, intent.Amount.Amount)
+			}
+		})
+	}
+}
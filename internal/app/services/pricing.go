@@ -0,0 +1,130 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// buildQuote menghitung harga setiap malam dari checkIn s/d checkOut (eksklusif) memakai
+// rate plan aktif tipe kamar. Tanpa rate plan, setiap malam memakai RoomType.Price.
+// Biaya dewasa tambahan & anak rombongan ditambahkan ke setiap malam (BookingNightPrice.Surcharge).
+// Dipakai oleh endpoint quote dan CreateBooking (dengan repository transaksi) agar harga konsisten.
+func buildQuote(ratePlans repositories.RatePlanRepository, roomType *models.RoomType, checkIn, checkOut time.Time, party models.Party) (*models.Quote, error) {
+	stay := &models.Booking{CheckInDate: checkIn, CheckOutDate: checkOut}
+	nights := stay.Nights()
+	if len(nights) == 0 {
+		return nil, models.ErrInvalidStay
+	}
+	if err := party.Validate(); err != nil {
+		return nil, err
+	}
+	if err := roomType.CheckOccupancy(party); err != nil {
+		return nil, err
+	}
+
+	plan, err := ratePlans.FindActiveByRoomTypeID(roomType.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	quote := &models.Quote{
+		PropertyID:   roomType.PropertyID,
+		RoomTypeID:   roomType.ID,
+		CheckInDate:  checkIn.Format("2006-01-02"),
+		CheckOutDate: checkOut.Format("2006-01-02"),
+		Guests:       party.Total(),
+		Adults:       party.Adults,
+		ChildAges:    party.ChildAges,
+		Surcharges:   []models.PriceLine{},
+		Taxes:        []models.PriceLine{},
+		Fees:         []models.PriceLine{},
+		Discounts:    []models.PriceLine{},
+	}
+	extraAdults := roomType.ExtraAdults(party)
+	paidChildren := roomType.PaidChildren(party)
+	surcharge := roomType.ExtraAdultPrice.Mul(extraAdults).Add(roomType.ChildPrice.Mul(paidChildren))
+
+	for _, night := range nights {
+		nightPrice := models.BookingNightPrice{Date: night, Price: roomType.Price, Surcharge: surcharge}
+		if plan != nil {
+			price, err := plan.PriceFor(night, roomType.Price)
+			if err != nil {
+				return nil, err
+			}
+			nightPrice.Price = price
+			nightPrice.RatePlanID = &plan.ID
+		}
+		quote.Nights = append(quote.Nights, nightPrice)
+		quote.Subtotal = quote.Subtotal.Add(nightPrice.Price).Add(nightPrice.Surcharge)
+	}
+
+	if extraAdults > 0 && roomType.ExtraAdultPrice.IsPositive() {
+		quote.Surcharges = append(quote.Surcharges, models.PriceLine{
+			Code:   "EXTRA_ADULT",
+			Name:   fmt.Sprintf("Dewasa tambahan x%d", extraAdults),
+			Amount: roomType.ExtraAdultPrice.Mul(extraAdults * len(nights)),
+		})
+	}
+	if paidChildren > 0 && roomType.ChildPrice.IsPositive() {
+		quote.Surcharges = append(quote.Surcharges, models.PriceLine{
+			Code:   "CHILD",
+			Name:   fmt.Sprintf("Anak x%d", paidChildren),
+			Amount: roomType.ChildPrice.Mul(paidChildren * len(nights)),
+		})
+	}
+	quote.GrandTotal = quote.Subtotal
+	return quote, nil
+}
+
+// applyPromo memvalidasi promo terhadap masa inap lalu menambahkan diskonnya ke quote.
+// Batas pemakaian per user dicek terpisah saat booking karena membutuhkan user & lock.
+func applyPromo(quote *models.Quote, promo *models.PromoCode, roomType *models.RoomType, now time.Time) error {
+	if err := promo.Validate(now, len(quote.Nights), roomType.Code); err != nil {
+		return err
+	}
+	addPromoDiscount(quote, promo)
+	return nil
+}
+
+// addPromoDiscount menambahkan diskon promo ke quote tanpa validasi periode/kuota.
+// Dipakai saat mengubah booking yang promonya sudah ditebus sebelumnya.
+func addPromoDiscount(quote *models.Quote, promo *models.PromoCode) {
+	quote.PromoCode = promo.Code
+	quote.Discounts = append(quote.Discounts, models.PriceLine{
+		Code:   promo.Code,
+		Name:   "Promo " + promo.Code,
+		Amount: promo.DiscountFor(quote.Subtotal),
+	})
+	quote.Recalculate()
+}
+
+// applyTaxesAndFees menambahkan pajak & biaya aktif properti quote. Wajib dipanggil setelah applyPromo
+// karena pajak persentase dihitung dari subtotal setelah diskon.
+func applyTaxesAndFees(taxFees repositories.TaxFeeRepository, quote *models.Quote) error {
+	active, err := taxFees.FindActive(quote.PropertyID)
+	if err != nil {
+		return err
+	}
+
+	base := quote.Subtotal.Sub(quote.TotalDiscount())
+	for _, taxFee := range active {
+		line := models.PriceLine{
+			Code:      taxFee.Code,
+			Name:      taxFee.Name,
+			Amount:    taxFee.AmountFor(base, len(quote.Nights)),
+			Inclusive: taxFee.Inclusive,
+		}
+		if taxFee.Kind == models.ComponentTax {
+			quote.Taxes = append(quote.Taxes, line)
+		} else {
+			quote.Fees = append(quote.Fees, line)
+		}
+	}
+	quote.Recalculate()
+	return nil
+}
@@ -0,0 +1,15 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"time"
+)
+
+// PricingService mendefinisikan kontrak untuk penawaran harga sebelum booking
+type PricingService interface {
+	// Quote menghitung rincian harga dan menandatangani quote token berumur pendek
+	// untuk rombongan party. promoCode bersifat opsional, currency kosong berarti mata uang dasar
+	Quote(roomTypeID uint, checkIn, checkOut time.Time, party models.Party, promoCode, currency string) (*models.Quote, error)
+	// ParseQuoteToken memverifikasi tanda tangan & masa berlaku quote token
+	ParseQuoteToken(token string) (*models.Quote, error)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"backend/internal/config"
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+type pricingServiceImpl struct {
+	roomTypeRepo repositories.RoomTypeRepository
+	ratePlanRepo repositories.RatePlanRepository
+	promoRepo    repositories.PromoCodeRepository
+	taxFeeRepo   repositories.TaxFeeRepository
+	rateRepo     repositories.ExchangeRateRepository
+	cfg          *config.Config
+	clock        Clock
+}
+
+func NewPricingService(rtRepo repositories.RoomTypeRepository, rpRepo repositories.RatePlanRepository, pRepo repositories.PromoCodeRepository, tfRepo repositories.TaxFeeRepository, erRepo repositories.ExchangeRateRepository, cfg *config.Config, clock Clock) PricingService {
+	return &pricingServiceImpl{roomTypeRepo: rtRepo, ratePlanRepo: rpRepo, promoRepo: pRepo, taxFeeRepo: tfRepo, rateRepo: erRepo, cfg: cfg, clock: clock}
+}
+
+// quoteSigningKey dipisah dari kunci access token agar quote token tidak bisa dipakai untuk login
+func (s *pricingServiceImpl) quoteSigningKey() []byte {
+	return []byte(s.cfg.JWTSecret + ":quote")
+}
+
+// Quote: Menghitung rincian harga lalu menandatangani hasilnya
+func (s *pricingServiceImpl) Quote(roomTypeID uint, checkIn, checkOut time.Time, party models.Party, promoCode, currency string) (*models.Quote, error) {
+	roomType, err := s.roomTypeRepo.FindByID(roomTypeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRoomTypeNotFound
+		}
+		return nil, err
+	}
+
+	quote, err := buildQuote(s.ratePlanRepo, roomType, checkIn, checkOut, party)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	if promoCode = models.NormalizePromoCode(promoCode); promoCode != "" {
+		promo, err := s.promoRepo.FindByCode(promoCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, models.ErrPromoNotFound
+			}
+			return nil, err
+		}
+		if err := applyPromo(quote, promo, roomType, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyTaxesAndFees(s.taxFeeRepo, quote); err != nil {
+		return nil, err
+	}
+	if err := convertQuote(s.rateRepo, quote, currency); err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(time.Duration(s.cfg.QuoteTTLMinutes) * time.Minute)
+	claims := models.QuoteClaims{
+		Quote: *quote,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.quoteSigningKey())
+	if err != nil {
+		return nil, err
+	}
+	quote.Token = token
+	quote.ExpiresAt = &expiresAt
+	return quote, nil
+}
+
+// ParseQuoteToken: Memverifikasi quote token dan mengembalikan quote yang ditandatangani
+func (s *pricingServiceImpl) ParseQuoteToken(tokenString string) (*models.Quote, error) {
+	parser := jwt.Parser{}
+	token, err := parser.ParseWithClaims(tokenString, &models.QuoteClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return s.quoteSigningKey(), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, models.ErrInvalidQuote
+	}
+
+	claims, ok := token.Claims.(*models.QuoteClaims)
+	if !ok || claims.ExpiresAt == nil || !s.clock.Now().Before(claims.ExpiresAt.Time) {
+		return nil, models.ErrInvalidQuote
+	}
+	return &claims.Quote, nil
+}
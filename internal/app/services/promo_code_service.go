@@ -0,0 +1,13 @@
+package services
+
+import "backend/internal/domain/models"
+
+// PromoCodeService mendefinisikan kontrak untuk pengelolaan kode promo (Admin)
+type PromoCodeService interface {
+	GetPromoCodes(pagination *models.Pagination) ([]models.PromoCode, error)
+	GetPromoCodeByID(promoCodeID uint) (*models.PromoCode, error)
+	CreatePromoCode(promo *models.PromoCode) (*models.PromoCode, error)
+	UpdatePromoCode(promo *models.PromoCode) (*models.PromoCode, error)
+	DeletePromoCode(promoCodeID uint) error
+	GetPromoCodeStats(promoCodeID uint) (*models.PromoCodeStats, error)
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type promoCodeServiceImpl struct {
+	promoRepo repositories.PromoCodeRepository
+}
+
+func NewPromoCodeService(pRepo repositories.PromoCodeRepository) PromoCodeService {
+	return &promoCodeServiceImpl{promoRepo: pRepo}
+}
+
+// validatePromoCode: Validasi data kode promo sebelum disimpan
+func validatePromoCode(promo *models.PromoCode) error {
+	promo.Code = models.NormalizePromoCode(promo.Code)
+	if promo.DiscountType != models.DiscountPercentage && promo.DiscountType != models.DiscountFixed {
+		return errors.New("tipe diskon harus percentage atau fixed")
+	}
+	if promo.Code == "" ||
+		(promo.DiscountType == models.DiscountPercentage && promo.DiscountBasisPoints <= 0) ||
+		(promo.DiscountType == models.DiscountFixed && !promo.DiscountAmount.IsPositive()) {
+		return errors.New("data kode promo tidak lengkap atau tidak valid")
+	}
+	if promo.DiscountType == models.DiscountPercentage && promo.DiscountBasisPoints > 10000 {
+		return errors.New("diskon persentase maksimal 100")
+	}
+	if promo.ValidFrom != nil && promo.ValidUntil != nil && promo.ValidUntil.Before(*promo.ValidFrom) {
+		return errors.New("tanggal akhir promo harus setelah tanggal mulai")
+	}
+	return nil
+}
+
+// GetPromoCodes: Mengambil semua kode promo
+func (s *promoCodeServiceImpl) GetPromoCodes(pagination *models.Pagination) ([]models.PromoCode, error) {
+	return s.promoRepo.FindAll(pagination)
+}
+
+// GetPromoCodeByID: Mengambil detail kode promo
+func (s *promoCodeServiceImpl) GetPromoCodeByID(promoCodeID uint) (*models.PromoCode, error) {
+	promo, err := s.promoRepo.FindByID(promoCodeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrPromoNotFound
+		}
+		return nil, err
+	}
+	return promo, nil
+}
+
+// CreatePromoCode: Membuat kode promo baru
+func (s *promoCodeServiceImpl) CreatePromoCode(promo *models.PromoCode) (*models.PromoCode, error) {
+	if err := validatePromoCode(promo); err != nil {
+		return nil, err
+	}
+	if err := s.promoRepo.Create(promo); err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+// UpdatePromoCode: Mengubah kode promo
+func (s *promoCodeServiceImpl) UpdatePromoCode(promo *models.PromoCode) (*models.PromoCode, error) {
+	if err := validatePromoCode(promo); err != nil {
+		return nil, err
+	}
+	if err := s.promoRepo.Update(promo); err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+// DeletePromoCode: Menghapus kode promo (riwayat redemption tetap tersimpan)
+func (s *promoCodeServiceImpl) DeletePromoCode(promoCodeID uint) error {
+	if _, err := s.GetPromoCodeByID(promoCodeID); err != nil {
+		return err
+	}
+	return s.promoRepo.Delete(promoCodeID)
+}
+
+// GetPromoCodeStats: Statistik redemption kode promo
+func (s *promoCodeServiceImpl) GetPromoCodeStats(promoCodeID uint) (*models.PromoCodeStats, error) {
+	stats, err := s.promoRepo.GetStats(promoCodeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrPromoNotFound
+		}
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,24 @@
+package services
+
+import "backend/internal/domain/models"
+
+// PropertyService mendefinisikan kontrak untuk properti (hotel dalam grup), gedung, dan penugasan staf
+type PropertyService interface {
+	// Untuk Publik & Admin
+	GetProperties(city string, activeOnly bool, pagination *models.Pagination) ([]models.Property, error)
+	GetPropertyByID(propertyID uint) (*models.Property, error)
+
+	// Untuk Admin
+	CreateProperty(property *models.Property) (*models.Property, error)
+	UpdateProperty(property *models.Property) (*models.Property, error)
+	DeleteProperty(propertyID uint) error
+
+	// Gedung (opsional) di dalam properti
+	CreateBuilding(building *models.Building) (*models.Building, error)
+	GetBuilding(propertyID, buildingID uint) (*models.Building, error)
+	UpdateBuilding(building *models.Building) (*models.Building, error)
+	DeleteBuilding(propertyID, buildingID uint) error
+
+	// AssignStaff menugaskan staf ke satu properti (propertyID nil = staf grup)
+	AssignStaff(userID uint, propertyID *uint) (*models.User, error)
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type propertyServiceImpl struct {
+	propertyRepo repositories.PropertyRepository
+	roomTypeRepo repositories.RoomTypeRepository
+	roomRepo     repositories.RoomRepository
+	userRepo     repositories.UserRepository
+}
+
+func NewPropertyService(pRepo repositories.PropertyRepository, rtRepo repositories.RoomTypeRepository, rRepo repositories.RoomRepository, uRepo repositories.UserRepository) PropertyService {
+	return &propertyServiceImpl{propertyRepo: pRepo, roomTypeRepo: rtRepo, roomRepo: rRepo, userRepo: uRepo}
+}
+
+// resolveProperty memastikan properti pemilik data (tipe kamar, pajak/biaya, staf) ada
+func resolveProperty(repo repositories.PropertyRepository, propertyID uint) error {
+	if propertyID == 0 {
+		return errors.New("properti wajib diisi")
+	}
+	if _, err := repo.FindByID(propertyID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrPropertyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// GetProperties: Mengambil daftar properti (opsional difilter per kota / hanya yang aktif)
+func (s *propertyServiceImpl) GetProperties(city string, activeOnly bool, pagination *models.Pagination) ([]models.Property, error) {
+	return s.propertyRepo.FindAll(strings.TrimSpace(city), activeOnly, pagination)
+}
+
+// GetPropertyByID: Mengambil detail properti beserta gedungnya
+func (s *propertyServiceImpl) GetPropertyByID(propertyID uint) (*models.Property, error) {
+	property, err := s.propertyRepo.FindByID(propertyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrPropertyNotFound
+		}
+		return nil, err
+	}
+	return property, nil
+}
+
+// validateProperty memvalidasi data properti sebelum disimpan
+func validateProperty(property *models.Property) error {
+	property.Code = strings.ToUpper(strings.TrimSpace(property.Code))
+	property.City = strings.TrimSpace(property.City)
+	if property.Code == "" || property.Name == "" {
+		return errors.New("data properti tidak lengkap atau tidak valid")
+	}
+	return nil
+}
+
+// CreateProperty: Membuat properti baru (Admin Grup)
+func (s *propertyServiceImpl) CreateProperty(property *models.Property) (*models.Property, error) {
+	if err := validateProperty(property); err != nil {
+		return nil, err
+	}
+	if err := s.propertyRepo.Create(property); err != nil {
+		return nil, err
+	}
+	return property, nil
+}
+
+// UpdateProperty: Mengubah data properti (Admin)
+func (s *propertyServiceImpl) UpdateProperty(property *models.Property) (*models.Property, error) {
+	if err := validateProperty(property); err != nil {
+		return nil, err
+	}
+	if err := s.propertyRepo.Update(property); err != nil {
+		return nil, err
+	}
+	return property, nil
+}
+
+// DeleteProperty: Menghapus properti yang sudah tidak memiliki tipe kamar (Admin Grup)
+func (s *propertyServiceImpl) DeleteProperty(propertyID uint) error {
+	if _, err := s.GetPropertyByID(propertyID); err != nil {
+		return err
+	}
+	count, err := s.roomTypeRepo.CountByProperty(propertyID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.ErrPropertyInUse
+	}
+	return s.propertyRepo.Delete(propertyID)
+}
+
+// validateBuilding memvalidasi data gedung sebelum disimpan
+func validateBuilding(building *models.Building) error {
+	building.Code = strings.ToUpper(strings.TrimSpace(building.Code))
+	if building.Code == "" || building.Name == "" || building.Floors < 0 {
+		return errors.New("data gedung tidak lengkap atau tidak valid")
+	}
+	return nil
+}
+
+// CreateBuilding: Menambah gedung ke properti (Admin)
+func (s *propertyServiceImpl) CreateBuilding(building *models.Building) (*models.Building, error) {
+	if err := validateBuilding(building); err != nil {
+		return nil, err
+	}
+	if err := resolveProperty(s.propertyRepo, building.PropertyID); err != nil {
+		return nil, err
+	}
+	if err := s.propertyRepo.CreateBuilding(building); err != nil {
+		return nil, err
+	}
+	return building, nil
+}
+
+// GetBuilding: Mengambil gedung milik properti tertentu
+func (s *propertyServiceImpl) GetBuilding(propertyID, buildingID uint) (*models.Building, error) {
+	building, err := s.propertyRepo.FindBuilding(propertyID, buildingID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrBuildingNotFound
+		}
+		return nil, err
+	}
+	return building, nil
+}
+
+// UpdateBuilding: Mengubah data gedung (Admin)
+func (s *propertyServiceImpl) UpdateBuilding(building *models.Building) (*models.Building, error) {
+	if err := validateBuilding(building); err != nil {
+		return nil, err
+	}
+	if err := s.propertyRepo.UpdateBuilding(building); err != nil {
+		return nil, err
+	}
+	return building, nil
+}
+
+// DeleteBuilding: Menghapus gedung yang sudah tidak memiliki kamar (Admin)
+func (s *propertyServiceImpl) DeleteBuilding(propertyID, buildingID uint) error {
+	if _, err := s.GetBuilding(propertyID, buildingID); err != nil {
+		return err
+	}
+	count, err := s.roomRepo.CountByBuilding(buildingID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.ErrBuildingInUse
+	}
+	return s.propertyRepo.DeleteBuilding(propertyID, buildingID)
+}
+
+// AssignStaff: Menugaskan staf (role selain member) ke satu properti; berlaku pada token berikutnya (Admin Grup)
+func (s *propertyServiceImpl) AssignStaff(userID uint, propertyID *uint) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrStaffNotFound
+		}
+		return nil, err
+	}
+	if user.Role == models.RoleMember {
+		return nil, models.ErrUserNotStaff
+	}
+
+	if propertyID != nil && *propertyID == 0 {
+		propertyID = nil
+	}
+	if propertyID != nil {
+		if err := resolveProperty(s.propertyRepo, *propertyID); err != nil {
+			return nil, err
+		}
+	}
+	user.PropertyID = propertyID
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
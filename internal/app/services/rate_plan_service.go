@@ -0,0 +1,20 @@
+package services
+
+import "backend/internal/domain/models"
+
+// RatePlanService mendefinisikan kontrak untuk pengelolaan tarif tipe kamar (Admin)
+type RatePlanService interface {
+	GetRatePlans(roomTypeID, propertyID uint, pagination *models.Pagination) ([]models.RatePlan, error)
+	GetRatePlanByID(ratePlanID uint) (*models.RatePlan, error)
+	CreateRatePlan(plan *models.RatePlan) (*models.RatePlan, error)
+	UpdateRatePlan(plan *models.RatePlan) (*models.RatePlan, error)
+	DeleteRatePlan(ratePlanID uint) error
+
+	// Musim, harga tanggal khusus, dan blackout
+	AddSeason(season *models.RateSeason) (*models.RateSeason, error)
+	DeleteSeason(ratePlanID, seasonID uint) error
+	SetDatePrice(datePrice *models.RateDatePrice) (*models.RateDatePrice, error)
+	DeleteDatePrice(ratePlanID, datePriceID uint) error
+	AddBlackout(blackout *models.RateBlackout) (*models.RateBlackout, error)
+	DeleteBlackout(ratePlanID, blackoutID uint) error
+}
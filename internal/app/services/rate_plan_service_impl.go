@@ -0,0 +1,144 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type ratePlanServiceImpl struct {
+	ratePlanRepo repositories.RatePlanRepository
+	roomTypeRepo repositories.RoomTypeRepository
+	policyRepo   repositories.CancellationPolicyRepository
+}
+
+func NewRatePlanService(rpRepo repositories.RatePlanRepository, rtRepo repositories.RoomTypeRepository, cpRepo repositories.CancellationPolicyRepository) RatePlanService {
+	return &ratePlanServiceImpl{ratePlanRepo: rpRepo, roomTypeRepo: rtRepo, policyRepo: cpRepo}
+}
+
+// GetRatePlans: Mengambil semua rate plan (opsional difilter per tipe kamar dan/atau properti)
+func (s *ratePlanServiceImpl) GetRatePlans(roomTypeID, propertyID uint, pagination *models.Pagination) ([]models.RatePlan, error) {
+	return s.ratePlanRepo.FindAll(roomTypeID, propertyID, pagination)
+}
+
+// GetRatePlanByID: Mengambil detail rate plan beserta musim, harga tanggal, dan blackout
+func (s *ratePlanServiceImpl) GetRatePlanByID(ratePlanID uint) (*models.RatePlan, error) {
+	plan, err := s.ratePlanRepo.FindByID(ratePlanID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRatePlanNotFound
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+// CreateRatePlan: Membuat rate plan baru untuk sebuah tipe kamar
+func (s *ratePlanServiceImpl) CreateRatePlan(plan *models.RatePlan) (*models.RatePlan, error) {
+	if plan.Name == "" || plan.WeekendUpliftPercent < -100 {
+		return nil, errors.New("data rate plan tidak lengkap atau tidak valid")
+	}
+	if _, err := s.roomTypeRepo.FindByID(plan.RoomTypeID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRoomTypeNotFound
+		}
+		return nil, err
+	}
+	policyID, err := resolveCancellationPolicy(s.policyRepo, plan.CancellationPolicyID)
+	if err != nil {
+		return nil, err
+	}
+	plan.CancellationPolicyID = policyID
+
+	if err := s.ratePlanRepo.Create(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// UpdateRatePlan: Mengubah data utama rate plan
+func (s *ratePlanServiceImpl) UpdateRatePlan(plan *models.RatePlan) (*models.RatePlan, error) {
+	if plan.WeekendUpliftPercent < -100 {
+		return nil, errors.New("data rate plan tidak lengkap atau tidak valid")
+	}
+	policyID, err := resolveCancellationPolicy(s.policyRepo, plan.CancellationPolicyID)
+	if err != nil {
+		return nil, err
+	}
+	plan.CancellationPolicyID = policyID
+	plan.CancellationPolicy = nil
+	if err := s.ratePlanRepo.Update(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// DeleteRatePlan: Menghapus rate plan (booking lama tetap memakai snapshot harga per malam)
+func (s *ratePlanServiceImpl) DeleteRatePlan(ratePlanID uint) error {
+	if _, err := s.GetRatePlanByID(ratePlanID); err != nil {
+		return err
+	}
+	return s.ratePlanRepo.Delete(ratePlanID)
+}
+
+// AddSeason: Menambah harga musim ke rate plan
+func (s *ratePlanServiceImpl) AddSeason(season *models.RateSeason) (*models.RateSeason, error) {
+	if _, err := s.GetRatePlanByID(season.RatePlanID); err != nil {
+		return nil, err
+	}
+	if season.Name == "" || !season.Price.IsPositive() || !season.Price.InBaseCurrency() || season.EndDate.Before(season.StartDate) {
+		return nil, errors.New("data musim tidak lengkap atau tidak valid")
+	}
+
+	if err := s.ratePlanRepo.CreateSeason(season); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// DeleteSeason: Menghapus harga musim
+func (s *ratePlanServiceImpl) DeleteSeason(ratePlanID, seasonID uint) error {
+	return s.ratePlanRepo.DeleteSeason(ratePlanID, seasonID)
+}
+
+// SetDatePrice: Mengatur harga khusus untuk satu tanggal (menimpa harga sebelumnya)
+func (s *ratePlanServiceImpl) SetDatePrice(datePrice *models.RateDatePrice) (*models.RateDatePrice, error) {
+	if _, err := s.GetRatePlanByID(datePrice.RatePlanID); err != nil {
+		return nil, err
+	}
+	if !datePrice.Price.IsPositive() || !datePrice.Price.InBaseCurrency() {
+		return nil, errors.New("harga tanggal khusus harus lebih dari 0 dalam mata uang dasar")
+	}
+
+	if err := s.ratePlanRepo.UpsertDatePrice(datePrice); err != nil {
+		return nil, err
+	}
+	return datePrice, nil
+}
+
+// DeleteDatePrice: Menghapus harga tanggal khusus
+func (s *ratePlanServiceImpl) DeleteDatePrice(ratePlanID, datePriceID uint) error {
+	return s.ratePlanRepo.DeleteDatePrice(ratePlanID, datePriceID)
+}
+
+// AddBlackout: Menambah rentang tanggal blackout
+func (s *ratePlanServiceImpl) AddBlackout(blackout *models.RateBlackout) (*models.RateBlackout, error) {
+	if _, err := s.GetRatePlanByID(blackout.RatePlanID); err != nil {
+		return nil, err
+	}
+	if blackout.EndDate.Before(blackout.StartDate) {
+		return nil, errors.New("tanggal akhir blackout harus setelah tanggal mulai")
+	}
+
+	if err := s.ratePlanRepo.CreateBlackout(blackout); err != nil {
+		return nil, err
+	}
+	return blackout, nil
+}
+
+// DeleteBlackout: Menghapus rentang blackout
+func (s *ratePlanServiceImpl) DeleteBlackout(ratePlanID, blackoutID uint) error {
+	return s.ratePlanRepo.DeleteBlackout(ratePlanID, blackoutID)
+}
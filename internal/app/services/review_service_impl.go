@@ -25,8 +25,8 @@ func (s *reviewServiceImpl) CreateReview(review *models.Review) (*models.Review,
 		return nil, errors.New("booking terkait tidak ditemukan")
 	}
 
-	// 2. Validasi: Pastikan Booking sudah selesai (Completed)
-	if booking.BookingStatus != models.StatusCompleted {
+	// 2. Validasi: Pastikan tamu sudah check-out (Checked Out/Completed)
+	if !booking.CanBeReviewed() {
 		return nil, errors.New("ulasan hanya dapat dibuat untuk pemesanan yang sudah selesai")
 	}
 
@@ -0,0 +1,26 @@
+package services
+
+import "backend/internal/domain/models"
+
+// RoleService mendefinisikan kontrak role staf, izinnya, dan penetapan role ke user
+type RoleService interface {
+	GetPermissions() []models.PermissionInfo
+	GetRoles() ([]models.Role, error)
+	GetRoleByID(roleID uint) (*models.Role, error)
+	// CreateRole dan UpdateRole dilakukan oleh actorID: selain admin, actor tidak boleh mengubah
+	// role-nya sendiri atau memberikan izin yang tidak ia miliki
+	CreateRole(actorID uint, role *models.Role) (*models.Role, error)
+	// UpdateRole mengganti deskripsi & izin role; nama role dan role sistem tidak bisa diubah
+	UpdateRole(actorID uint, role *models.Role) (*models.Role, error)
+	DeleteRole(roleID uint) error
+
+	// AssignRole mengganti role user oleh actorID; selain admin, actor hanya boleh memberikan role
+	// dan mengganti role lama yang izinnya ia miliki semua. Sesi login user dicabut agar role baru
+	// langsung berlaku
+	AssignRole(actorID, userID uint, roleName string) (*models.User, error)
+
+	// HasPermission mengecek izin role (dipakai middleware RequirePermission)
+	HasPermission(roleName, permission string) (bool, error)
+	// SeedDefaultRoles membuat role bawaan yang belum ada di tenant
+	SeedDefaultRoles() error
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type roleServiceImpl struct {
+	roleRepo repositories.RoleRepository
+	userRepo repositories.UserRepository
+	auth     AuthService
+}
+
+func NewRoleService(roleRepo repositories.RoleRepository, userRepo repositories.UserRepository, auth AuthService) RoleService {
+	return &roleServiceImpl{roleRepo: roleRepo, userRepo: userRepo, auth: auth}
+}
+
+func (s *roleServiceImpl) GetPermissions() []models.PermissionInfo {
+	return models.Permissions
+}
+
+func (s *roleServiceImpl) GetRoles() ([]models.Role, error) {
+	return s.roleRepo.FindAll()
+}
+
+func (s *roleServiceImpl) GetRoleByID(roleID uint) (*models.Role, error) {
+	role, err := s.roleRepo.FindByID(roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *roleServiceImpl) CreateRole(actorID uint, role *models.Role) (*models.Role, error) {
+	if err := role.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkGrant(actorID, role, nil); err != nil {
+		return nil, err
+	}
+	if _, err := s.roleRepo.FindByName(role.Name); err == nil {
+		return nil, models.ErrRoleNameTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	role.IsSystem = false
+	if err := s.roleRepo.Create(role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *roleServiceImpl) UpdateRole(actorID uint, role *models.Role) (*models.Role, error) {
+	stored, err := s.GetRoleByID(role.ID)
+	if err != nil {
+		return nil, err
+	}
+	if stored.IsSystem {
+		return nil, models.ErrSystemRole
+	}
+
+	// Nama dipakai di User.Role sehingga tidak boleh berubah
+	role.Name = stored.Name
+	role.IsSystem = false
+	if err := role.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkGrant(actorID, role, stored.Permissions); err != nil {
+		return nil, err
+	}
+	if err := s.roleRepo.Update(role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// checkGrant mencegah staf dengan izin role.manage menaikkan haknya sendiri: role miliknya tidak
+// boleh diubah dan izin yang baru diberikan (di luar previous) harus sudah ia miliki. Admin bebas.
+func (s *roleServiceImpl) checkGrant(actorID uint, role *models.Role, previous []string) error {
+	actor, err := s.userRepo.FindByID(actorID)
+	if err != nil {
+		return err
+	}
+	if actor.Role == models.RoleAdmin {
+		return nil
+	}
+	if actor.Role == role.Name {
+		return models.ErrCannotChangeOwnRole
+	}
+	return checkHeld(s.roleRepo, actor, role.Permissions, previous)
+}
+
+// checkHeld memastikan setiap izin di permissions (di luar previous) dimiliki role actor
+func checkHeld(roleRepo repositories.RoleRepository, actor *models.User, permissions, previous []string) error {
+	if actor.Role == models.RoleAdmin {
+		return nil
+	}
+	actorRole, err := roleRepo.FindByName(actor.Role)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrPermissionDenied
+		}
+		return err
+	}
+	kept := make(map[string]bool, len(previous))
+	for _, permission := range previous {
+		kept[permission] = true
+	}
+	for _, permission := range permissions {
+		if !kept[permission] && !actorRole.HasPermission(permission) {
+			return models.ErrPermissionNotHeld
+		}
+	}
+	return nil
+}
+
+// checkManageUser memastikan actor boleh mengelola akun user (mengganti role, mereset 2FA): hanya
+// admin yang boleh mengelola admin, dan selain admin actor harus memiliki semua izin role user
+// tersebut (role yang sudah dihapus tidak memberi izin apa pun)
+func checkManageUser(roleRepo repositories.RoleRepository, actor, user *models.User) error {
+	if user.Role == models.RoleAdmin && actor.Role != models.RoleAdmin {
+		return models.ErrPermissionDenied
+	}
+	current, err := roleRepo.FindByName(user.Role)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return checkHeld(roleRepo, actor, current.Permissions, nil)
+}
+
+func (s *roleServiceImpl) DeleteRole(roleID uint) error {
+	role, err := s.GetRoleByID(roleID)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return models.ErrSystemRole
+	}
+	users, err := s.roleRepo.CountUsers(role.Name)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return models.ErrRoleInUse
+	}
+	return s.roleRepo.Delete(roleID)
+}
+
+func (s *roleServiceImpl) AssignRole(actorID, userID uint, roleName string) (*models.User, error) {
+	// Mencegah admin tanpa sengaja mencabut aksesnya sendiri
+	if actorID == userID {
+		return nil, models.ErrCannotChangeOwnRole
+	}
+	role, err := s.roleRepo.FindByName(roleName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrStaffNotFound
+		}
+		return nil, err
+	}
+
+	actor, err := s.userRepo.FindByID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	// Hanya admin yang boleh mengangkat atau menurunkan admin, agar staf dengan izin staff.manage
+	// tidak bisa menaikkan haknya sendiri lewat akun lain. Selain itu actor hanya boleh mengganti
+	// role user yang izinnya ia miliki semua, dan hanya boleh memberikan role yang izinnya ia miliki semua.
+	if roleName == models.RoleAdmin && actor.Role != models.RoleAdmin {
+		return nil, models.ErrPermissionDenied
+	}
+	if err := checkManageUser(s.roleRepo, actor, user); err != nil {
+		return nil, err
+	}
+	if err := checkHeld(s.roleRepo, actor, role.Permissions, nil); err != nil {
+		return nil, err
+	}
+
+	user.Role = roleName
+	if roleName == models.RoleMember {
+		user.PropertyID = nil // Member tidak bertugas di properti
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	// Token lama masih membawa role sebelumnya, jadi user harus login ulang
+	if err := s.auth.RevokeSessions(user.ID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *roleServiceImpl) HasPermission(roleName, permission string) (bool, error) {
+	role, err := s.roleRepo.FindByName(roleName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil // Role sudah dihapus: tidak ada izin
+		}
+		return false, err
+	}
+	return role.HasPermission(permission), nil
+}
+
+func (s *roleServiceImpl) SeedDefaultRoles() error {
+	for _, role := range models.DefaultRoles() {
+		_, err := s.roleRepo.FindByName(role.Name)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := s.roleRepo.Create(&role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/internal/infra/gorm/repositories"
+)
+
+// TestUpdateRolePreventsSelfEscalation memastikan staf dengan izin role.manage tidak dapat
+// menaikkan haknya lewat role miliknya sendiri atau memberikan izin yang tidak ia miliki
+func TestUpdateRolePreventsSelfEscalation(t *testing.T) {
+	env := newTestEnv(t)
+	roleRepo := repositories.NewGormRoleRepository(env.db)
+	roleService := services.NewRoleService(roleRepo, repositories.NewGormRepository(env.db), nil)
+	if err := roleService.SeedDefaultRoles(); err != nil {
+		t.Fatal(err)
+	}
+
+	admin := env.createStaff("admin", models.RoleAdmin)
+	roleManager, err := roleService.CreateRole(admin.ID, &models.Role{
+		Name:        "role_manager",
+		Permissions: []string{models.PermRoleManage, models.PermBookingView, models.PermPaymentView},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := env.createStaff("manager", roleManager.Name)
+	frontDesk, err := roleRepo.FindByName(models.RoleFrontDesk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		actorID     uint
+		roleID      uint
+		permissions []string
+		wantErr     error
+	}{
+		{
+			name:        "menambah izin ke role sendiri",
+			actorID:     manager.ID,
+			roleID:      roleManager.ID,
+			permissions: []string{models.PermRoleManage, models.PermBookingView, models.PermPaymentView, models.PermStaffManage},
+			wantErr:     models.ErrCannotChangeOwnRole,
+		},
+		{
+			name:        "mengurangi izin role sendiri",
+			actorID:     manager.ID,
+			roleID:      roleManager.ID,
+			permissions: []string{models.PermRoleManage},
+			wantErr:     models.ErrCannotChangeOwnRole,
+		},
+		{
+			name:        "memberikan izin yang tidak dimiliki",
+			actorID:     manager.ID,
+			roleID:      frontDesk.ID,
+			permissions: append(append([]string{}, frontDesk.Permissions...), models.PermPaymentRefund),
+			wantErr:     models.ErrPermissionNotHeld,
+		},
+		{
+			name:        "mempertahankan izin lama dan menambah izin yang dimiliki",
+			actorID:     manager.ID,
+			roleID:      frontDesk.ID,
+			permissions: append(append([]string{}, frontDesk.Permissions...), models.PermPaymentView),
+		},
+		{
+			name:        "admin memberikan izin apa pun",
+			actorID:     admin.ID,
+			roleID:      frontDesk.ID,
+			permissions: append(append([]string{}, frontDesk.Permissions...), models.PermPaymentRefund, models.PermStaffManage),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, err := roleService.GetRoleByID(tt.roleID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			before := append([]string{}, role.Permissions...)
+			role.Permissions = tt.permissions
+
+			_, err = roleService.UpdateRole(tt.actorID, role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ingin error %v, dapat %v", tt.wantErr, err)
+			}
+
+			stored, err := roleService.GetRoleByID(tt.roleID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := before
+			if tt.wantErr == nil {
+				want = role.Permissions
+			}
+			if len(stored.Permissions) != len(want) {
+				t.Fatalf("izin tersimpan %v, ingin %v", stored.Permissions, want)
+			}
+		})
+	}
+
+	// Role baru juga hanya boleh berisi izin yang dimiliki pembuatnya
+	_, err = roleService.CreateRole(manager.ID, &models.Role{Name: "escalated", Permissions: []string{models.PermStaffManage}})
+	if !errors.Is(err, models.ErrPermissionNotHeld) {
+		t.Fatalf("ingin ErrPermissionNotHeld saat membuat role, dapat %v", err)
+	}
+}
+
+// TestAssignRolePreventsEscalation memastikan staf dengan izin staff.manage hanya dapat memberikan
+// role yang izinnya ia miliki semua dan tidak dapat mengganti role user yang lebih kuat darinya
+func TestAssignRolePreventsEscalation(t *testing.T) {
+	env := newTestEnv(t)
+	roleRepo := repositories.NewGormRoleRepository(env.db)
+	userRepo := repositories.NewGormRepository(env.db)
+	auth := services.NewAuthService(userRepo, repositories.NewGormAuthTokenRepository(env.db), nil, nil, env.cfg, env.clock)
+	roleService := services.NewRoleService(roleRepo, userRepo, auth)
+	if err := roleService.SeedDefaultRoles(); err != nil {
+		t.Fatal(err)
+	}
+
+	admin := env.createStaff("admin", models.RoleAdmin)
+	staffManager, err := roleService.CreateRole(admin.ID, &models.Role{
+		Name:        "staff_manager",
+		Permissions: []string{models.PermStaffManage, models.PermBookingView, models.PermPaymentView},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, role := range []models.Role{
+		{Name: "viewer", Permissions: []string{models.PermBookingView}},
+		{Name: "role_manager", Permissions: []string{models.PermRoleManage}},
+	} {
+		if _, err := roleService.CreateRole(admin.ID, &role); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manager := env.createStaff("manager", staffManager.Name)
+
+	tests := []struct {
+		name    string
+		actorID uint
+		current string // Role user sebelum diganti
+		assign  string
+		wantErr error
+	}{
+		{name: "memberikan role yang izinnya dimiliki", actorID: manager.ID, current: models.RoleMember, assign: "viewer"},
+		{name: "memberikan role setara dirinya", actorID: manager.ID, current: models.RoleMember, assign: staffManager.Name},
+		{name: "menurunkan staf yang izinnya dimiliki", actorID: manager.ID, current: "viewer", assign: models.RoleMember},
+		{name: "memberikan role dengan role.manage", actorID: manager.ID, current: models.RoleMember, assign: "role_manager", wantErr: models.ErrPermissionNotHeld},
+		{name: "memberikan role bawaan yang lebih kuat", actorID: manager.ID, current: models.RoleMember, assign: models.RoleFrontDesk, wantErr: models.ErrPermissionNotHeld},
+		{name: "menurunkan pemegang role yang lebih kuat", actorID: manager.ID, current: models.RoleAccounting, assign: models.RoleMember, wantErr: models.ErrPermissionNotHeld},
+		{name: "mengganti role pemegang role.manage", actorID: manager.ID, current: "role_manager", assign: "viewer", wantErr: models.ErrPermissionNotHeld},
+		{name: "mengangkat admin", actorID: manager.ID, current: models.RoleMember, assign: models.RoleAdmin, wantErr: models.ErrPermissionDenied},
+		{name: "menurunkan admin", actorID: manager.ID, current: models.RoleAdmin, assign: models.RoleMember, wantErr: models.ErrPermissionDenied},
+		{name: "admin memberikan role apa pun", actorID: admin.ID, current: models.RoleAccounting, assign: "role_manager"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := env.createStaff(fmt.Sprintf("target%d", i), tt.current)
+
+			_, err := roleService.AssignRole(tt.actorID, target.ID, tt.assign)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ingin error %v, dapat %v", tt.wantErr, err)
+			}
+
+			stored, err := userRepo.FindByID(target.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.current
+			if tt.wantErr == nil {
+				want = tt.assign
+			}
+			if stored.Role != want {
+				t.Fatalf("role tersimpan %s, ingin %s", stored.Role, want)
+			}
+		})
+	}
+}
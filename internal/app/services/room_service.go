@@ -2,19 +2,26 @@ package services
 
 import (
 	"backend/internal/domain/models"
+	"time"
 )
 
-// RoomService mendefinisikan kontrak untuk semua operasi kamar
+// RoomService mendefinisikan kontrak untuk semua operasi kamar fisik
+// (produk yang dijual ke tamu ada di RoomTypeService)
 type RoomService interface {
 	// Untuk Member & Admin
-	GetAllRooms(pagination *models.Pagination) ([]models.Room, error)
+	GetAllRooms(filter models.RoomFilter, pagination *models.Pagination) ([]models.Room, error)
+	// GetRoomFacets menghitung jumlah kamar per fasilitas, tipe kamar, dan rentang harga untuk sidebar filter
+	GetRoomFacets(filter models.RoomFilter) (models.SearchFacets, error)
 	GetRoomByID(roomID uint) (*models.Room, error)
-	GetAvailableRooms(checkInDate, checkOutDate string, pagination *models.Pagination) ([]models.Room, error)
+	// GetRoomCalendar mengembalikan ketersediaan, harga, dan batasan setiap malam from..to (to eksklusif)
+	GetRoomCalendar(roomID uint, from, to time.Time) (*models.RoomCalendar, error)
 
 	// Untuk Admin
 	CreateRoom(room *models.Room) (*models.Room, error)
 	UpdateRoom(room *models.Room) (*models.Room, error)
 	DeleteRoom(roomID uint) error
+	// SetRoomAmenities mengganti seluruh fasilitas kamar dengan daftar fasilitas dari katalog
+	SetRoomAmenities(roomID uint, amenityIDs []uint) (*models.Room, error)
 
 	// Untuk Galeri Foto
 	AddRoomImage(image *models.RoomImage) (*models.RoomImage, error)
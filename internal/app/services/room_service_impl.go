@@ -4,6 +4,7 @@ import (
 	"backend/internal/domain/models"
 	"backend/internal/domain/repositories"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -11,15 +12,33 @@ import (
 type roomServiceImpl struct {
 	roomRepo      repositories.RoomRepository
 	roomImageRepo repositories.RoomImageRepository
+	roomTypeRepo  repositories.RoomTypeRepository
+	propertyRepo  repositories.PropertyRepository
+	amenityRepo   repositories.AmenityRepository
+	bookingRepo   repositories.BookingRepository
+	ratePlanRepo  repositories.RatePlanRepository
+	restrictRepo  repositories.StayRestrictionRepository
+	clock         Clock
 }
 
-func NewRoomService(rRepo repositories.RoomRepository, riRepo repositories.RoomImageRepository) RoomService {
-	return &roomServiceImpl{roomRepo: rRepo, roomImageRepo: riRepo}
+func NewRoomService(rRepo repositories.RoomRepository, riRepo repositories.RoomImageRepository, rtRepo repositories.RoomTypeRepository, pRepo repositories.PropertyRepository, aRepo repositories.AmenityRepository, bRepo repositories.BookingRepository, rpRepo repositories.RatePlanRepository, srRepo repositories.StayRestrictionRepository, clock Clock) RoomService {
+	return &roomServiceImpl{roomRepo: rRepo, roomImageRepo: riRepo, roomTypeRepo: rtRepo, propertyRepo: pRepo, amenityRepo: aRepo, bookingRepo: bRepo, ratePlanRepo: rpRepo, restrictRepo: srRepo, clock: clock}
 }
 
-// GetAllRooms: Mengambil semua kamar dengan pagination
-func (s *roomServiceImpl) GetAllRooms(pagination *models.Pagination) ([]models.Room, error) {
-	return s.roomRepo.FindAll(pagination)
+// GetAllRooms: Mengambil kamar yang sesuai filter dengan pagination
+func (s *roomServiceImpl) GetAllRooms(filter models.RoomFilter, pagination *models.Pagination) ([]models.Room, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return s.roomRepo.FindAll(filter, pagination)
+}
+
+// GetRoomFacets: Menghitung facet pencarian kamar untuk filter yang sama dengan GetAllRooms
+func (s *roomServiceImpl) GetRoomFacets(filter models.RoomFilter) (models.SearchFacets, error) {
+	if err := filter.Validate(); err != nil {
+		return models.SearchFacets{}, err
+	}
+	return s.roomRepo.Facets(filter)
 }
 
 // GetRoomByID: Mengambil detail kamar berdasarkan ID
@@ -34,17 +53,94 @@ func (s *roomServiceImpl) GetRoomByID(roomID uint) (*models.Room, error) {
 	return room, nil
 }
 
-// GetAvailableRooms: Mengambil kamar yang tersedia pada periode tertentu
-func (s *roomServiceImpl) GetAvailableRooms(checkInDate, checkOutDate string, pagination *models.Pagination) ([]models.Room, error) {
-	return s.roomRepo.FindAvailable(checkInDate, checkOutDate, pagination)
+// GetRoomCalendar: Kalender ketersediaan kamar. Semua data dimuat dengan sejumlah query tetap
+// (kamar, inventori, booking yang beririsan, rate plan, batasan masa inap) lalu dihitung per malam di memori.
+func (s *roomServiceImpl) GetRoomCalendar(roomID uint, from, to time.Time) (*models.RoomCalendar, error) {
+	if !from.Before(to) || to.Sub(from) > models.MaxCalendarDays*24*time.Hour {
+		return nil, models.ErrInvalidCalendarRange
+	}
+
+	room, err := s.GetRoomByID(roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room.RoomType == nil {
+		return nil, models.ErrRoomTypeNotFound
+	}
+
+	sellable, err := s.roomRepo.CountSellable(room.RoomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	overlapping, err := s.bookingRepo.FindOverlappingByRoomType(room.RoomTypeID, from.Format("2006-01-02"), to.Format("2006-01-02"), 0, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.ratePlanRepo.FindActiveByRoomTypeID(room.RoomTypeID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	// Sampai tanggal to agar closed-to-departure pada tanggal terakhir ikut terbaca
+	restrictions, err := s.restrictRepo.FindForStay([]uint{room.RoomTypeID}, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	return models.BuildRoomCalendar(models.CalendarInput{
+		Room:        room,
+		RoomType:    room.RoomType,
+		Plan:        plan,
+		Sellable:    int(sellable),
+		Overlapping: overlapping,
+		Rules:       models.StayRules(restrictions).ForRoom(room.ID),
+	}, from, to)
+}
+
+// resolveLocation memastikan tipe kamar ada dan berada di properti kamar, lalu memvalidasi
+// gedung & lantai (opsional). PropertyID 0 pada kamar baru diisi dari tipe kamarnya.
+func (s *roomServiceImpl) resolveLocation(room *models.Room) error {
+	roomType, err := s.roomTypeRepo.FindByID(room.RoomTypeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrRoomTypeNotFound
+		}
+		return err
+	}
+	if room.PropertyID == 0 {
+		room.PropertyID = roomType.PropertyID
+	}
+	if room.PropertyID != roomType.PropertyID {
+		return models.ErrPropertyMismatch
+	}
+
+	if room.BuildingID == nil {
+		if room.Floor != nil && *room.Floor < 0 {
+			return models.ErrInvalidFloor
+		}
+		return nil
+	}
+	building, err := s.propertyRepo.FindBuilding(room.PropertyID, *room.BuildingID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrBuildingNotFound
+		}
+		return err
+	}
+	if room.Floor != nil && !building.HasFloor(*room.Floor) {
+		return models.ErrInvalidFloor
+	}
+	return nil
 }
 
 // CreateRoom: Membuat kamar baru (Admin Only)
 func (s *roomServiceImpl) CreateRoom(room *models.Room) (*models.Room, error) {
 	// Validasi input
-	if room.RoomNumber == "" || room.Type == "" || room.Price <= 0 {
+	if room.RoomNumber == "" || room.RoomTypeID == 0 {
 		return nil, errors.New("data kamar tidak lengkap atau tidak valid")
 	}
+	if err := s.resolveLocation(room); err != nil {
+		return nil, err
+	}
 
 	if err := s.roomRepo.Create(room); err != nil {
 		return nil, err
@@ -62,6 +158,15 @@ func (s *roomServiceImpl) UpdateRoom(room *models.Room) (*models.Room, error) {
 		}
 		return nil, err
 	}
+	// Kamar tidak bisa dipindah ke properti lain, tipe & gedung baru harus di properti yang sama
+	if err := s.resolveLocation(room); err != nil {
+		return nil, err
+	}
+	// Relasi hasil preload dilepas agar Save tidak menimpa RoomTypeID/BuildingID yang baru;
+	// fasilitas hanya diubah lewat SetRoomAmenities
+	room.RoomType = nil
+	room.Building = nil
+	room.Amenities = nil
 
 	if err := s.roomRepo.Update(room); err != nil {
 		return nil, err
@@ -88,6 +193,35 @@ func (s *roomServiceImpl) DeleteRoom(roomID uint) error {
 	return s.roomRepo.Delete(roomID)
 }
 
+// SetRoomAmenities: Mengganti fasilitas kamar (Admin Only)
+func (s *roomServiceImpl) SetRoomAmenities(roomID uint, amenityIDs []uint) (*models.Room, error) {
+	if _, err := s.GetRoomByID(roomID); err != nil {
+		return nil, err
+	}
+
+	// Fasilitas harus ada di katalog tenant; ID ganda diabaikan
+	unique := make([]uint, 0, len(amenityIDs))
+	seen := make(map[uint]bool, len(amenityIDs))
+	for _, id := range amenityIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	amenities, err := s.amenityRepo.FindByIDs(unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(amenities) != len(unique) {
+		return nil, models.ErrAmenityNotFound
+	}
+
+	if err := s.roomRepo.SetAmenities(roomID, unique); err != nil {
+		return nil, err
+	}
+	return s.GetRoomByID(roomID)
+}
+
 // AddRoomImage: Menambah gambar kamar (Admin Only)
 func (s *roomServiceImpl) AddRoomImage(image *models.RoomImage) (*models.RoomImage, error) {
 	// Verifikasi kamar ada
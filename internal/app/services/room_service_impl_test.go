@@ -0,0 +1,122 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+)
+
+func TestGetRoomCalendar(t *testing.T) {
+	env := newTestEnv(t)
+	f := env.seedInventory()
+	guest := env.createMember("guest")
+
+	// Booking pertama ditempatkan di kamar DLX-1, booking kedua belum mendapat kamar fisik
+	checkIn, checkOut := stay(closedToArrivalDay+1, 2)
+	assigned, err := env.bookings.CreateBooking(newBooking(guest.ID, f.deluxe.ID, checkIn, checkOut), services.BookingOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.bookings.AssignRoom(assigned.ID, f.deluxeRooms[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	checkIn, checkOut = stay(closedToArrivalDay+2, 1)
+	if _, err := env.bookings.CreateBooking(newBooking(guest.ID, f.deluxe.ID, checkIn, checkOut), services.BookingOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	from, to := stay(closedToArrivalDay-1, closedToDepartureDay-closedToArrivalDay+2)
+	tests := []struct {
+		name          string
+		roomID        uint
+		offset        int
+		wantAvailable bool
+		wantRemaining int
+		wantCTA       bool
+		wantCTD       bool
+	}{
+		{name: "sebelum booking", roomID: f.deluxeRooms[0].ID, offset: closedToArrivalDay - 1, wantAvailable: true, wantRemaining: 2},
+		{name: "closed to arrival", roomID: f.deluxeRooms[0].ID, offset: closedToArrivalDay, wantAvailable: true, wantRemaining: 2, wantCTA: true},
+		{name: "kamar ditempati booking", roomID: f.deluxeRooms[0].ID, offset: closedToArrivalDay + 1, wantAvailable: false, wantRemaining: 1},
+		{name: "tipe penuh", roomID: f.deluxeRooms[0].ID, offset: closedToArrivalDay + 2, wantAvailable: false, wantRemaining: 0},
+		{name: "kamar lain saat tipe penuh", roomID: f.deluxeRooms[1].ID, offset: closedToArrivalDay + 2, wantAvailable: false, wantRemaining: 0},
+		{name: "kamar lain saat tipe masih tersisa", roomID: f.deluxeRooms[1].ID, offset: closedToArrivalDay + 1, wantAvailable: true, wantRemaining: 1},
+		{name: "tanggal check-out booking kembali tersedia", roomID: f.deluxeRooms[0].ID, offset: closedToArrivalDay + 3, wantAvailable: true, wantRemaining: 2},
+		{name: "closed to departure", roomID: f.deluxeRooms[1].ID, offset: closedToDepartureDay, wantAvailable: true, wantRemaining: 2, wantCTD: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar, err := env.rooms.GetRoomCalendar(tt.roomID, from, to)
+			if err != nil {
+				t.Fatalf("GetRoomCalendar: %v", err)
+			}
+			date, _ := stay(tt.offset, 0)
+			var day *models.CalendarDay
+			for i := range calendar.Days {
+				if calendar.Days[i].Date == date.Format("2006-01-02") {
+					day = &calendar.Days[i]
+				}
+			}
+			if day == nil {
+				t.Fatalf("tanggal %s tidak ada di kalender %s..%s", date.Format("2006-01-02"), calendar.From, calendar.To)
+			}
+			if day.Available != tt.wantAvailable || day.Remaining != tt.wantRemaining {
+				t.Errorf("available %v sisa %d, ingin %v sisa %d", day.Available, day.Remaining, tt.wantAvailable, tt.wantRemaining)
+			}
+			if day.Restrictions.ClosedToArrival != tt.wantCTA || day.Restrictions.ClosedToDeparture != tt.wantCTD {
+				t.Errorf("CTA %v CTD %v, ingin CTA %v CTD %v", day.Restrictions.ClosedToArrival, day.Restrictions.ClosedToDeparture, tt.wantCTA, tt.wantCTD)
+			}
+			if day.Price == nil || day.Price.Amount != f.deluxe.Price.Amount {
+				t.Errorf("harga %+v, ingin %d", day.Price, f.deluxe.Price.Amount)
+			}
+		})
+	}
+
+	t.Run("rentang tidak valid", func(t *testing.T) {
+		if _, err := env.rooms.GetRoomCalendar(f.deluxeRooms[0].ID, to, from); !errors.Is(err, models.ErrInvalidCalendarRange) {
+			t.Fatalf("ingin ErrInvalidCalendarRange, dapat %v", err)
+		}
+	})
+}
+
+// TestCreateBookingStayRestrictions memastikan booking menolak masa inap yang sama dengan yang
+// disembunyikan pencarian ketersediaan (lihat TestGetAvailableRoomTypes)
+func TestCreateBookingStayRestrictions(t *testing.T) {
+	env := newTestEnv(t)
+	f := env.seedInventory()
+	guest := env.createMember("guest")
+
+	tests := []struct {
+		name     string
+		offset   int
+		nights   int
+		wantCode string // Kosong = booking berhasil
+	}{
+		{name: "CTA pada tanggal check-in", offset: closedToArrivalDay, nights: 2, wantCode: models.RestrictionClosedToArrival},
+		{name: "CTA pada tanggal check-out", offset: closedToArrivalDay - 2, nights: 2},
+		{name: "CTD pada tanggal check-out", offset: closedToDepartureDay - 2, nights: 2, wantCode: models.RestrictionClosedToDeparture},
+		{name: "CTD pada tanggal check-in", offset: closedToDepartureDay, nights: 1},
+		{name: "kurang dari min LOS", offset: minStayDay, nights: 2, wantCode: models.RestrictionMinStay},
+		{name: "tepat min LOS", offset: minStayDay, nights: 3},
+		{name: "lebih dari max LOS", offset: maxStayDay, nights: 3, wantCode: models.RestrictionMaxStay},
+		{name: "tepat max LOS", offset: maxStayDay, nights: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkIn, checkOut := stay(tt.offset, tt.nights)
+			_, err := env.bookings.CreateBooking(newBooking(guest.ID, f.deluxe.ID, checkIn, checkOut), services.BookingOptions{})
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("booking gagal: %v", err)
+				}
+				return
+			}
+			var violation *models.RestrictionViolation
+			if !errors.As(err, &violation) || violation.Code != tt.wantCode {
+				t.Fatalf("ingin pelanggaran %s, dapat %v", tt.wantCode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package services
+
+import "backend/internal/domain/models"
+
+// RoomTypeService mendefinisikan kontrak untuk tipe kamar (produk yang dipesan tamu)
+type RoomTypeService interface {
+	// Untuk Member & Admin
+	GetRoomTypes(filter models.PropertyFilter, pagination *models.Pagination) ([]models.RoomType, error)
+	GetRoomTypeByID(roomTypeID uint) (*models.RoomType, error)
+	// GetAvailableRoomTypes mengembalikan tipe kamar yang masih tersisa beserta jumlah sisanya,
+	// dapat mencakup beberapa properti sekaligus
+	GetAvailableRoomTypes(checkInDate, checkOutDate string, filter models.RoomFilter, party models.Party, pagination *models.Pagination) ([]models.RoomType, models.SearchFacets, error)
+
+	// Untuk Admin
+	CreateRoomType(roomType *models.RoomType) (*models.RoomType, error)
+	UpdateRoomType(roomType *models.RoomType) (*models.RoomType, error)
+	DeleteRoomType(roomTypeID uint) error
+}
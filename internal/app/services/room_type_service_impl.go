@@ -0,0 +1,114 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type roomTypeServiceImpl struct {
+	roomTypeRepo repositories.RoomTypeRepository
+	roomRepo     repositories.RoomRepository
+	policyRepo   repositories.CancellationPolicyRepository
+	propertyRepo repositories.PropertyRepository
+	clock        Clock
+}
+
+func NewRoomTypeService(rtRepo repositories.RoomTypeRepository, rRepo repositories.RoomRepository, cpRepo repositories.CancellationPolicyRepository, pRepo repositories.PropertyRepository, clock Clock) RoomTypeService {
+	return &roomTypeServiceImpl{roomTypeRepo: rtRepo, roomRepo: rRepo, policyRepo: cpRepo, propertyRepo: pRepo, clock: clock}
+}
+
+// GetRoomTypes: Mengambil semua tipe kamar properti aktif dengan pagination
+func (s *roomTypeServiceImpl) GetRoomTypes(filter models.PropertyFilter, pagination *models.Pagination) ([]models.RoomType, error) {
+	return s.roomTypeRepo.FindAll(filter, pagination)
+}
+
+// GetRoomTypeByID: Mengambil detail tipe kamar beserta kebijakan pembatalannya
+func (s *roomTypeServiceImpl) GetRoomTypeByID(roomTypeID uint) (*models.RoomType, error) {
+	roomType, err := s.roomTypeRepo.FindByID(roomTypeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRoomTypeNotFound
+		}
+		return nil, err
+	}
+	return roomType, nil
+}
+
+// GetAvailableRoomTypes: Mengambil tipe kamar yang masih tersisa pada periode tertentu,
+// dari satu properti, satu kota, atau seluruh properti aktif
+func (s *roomTypeServiceImpl) GetAvailableRoomTypes(checkInDate, checkOutDate string, filter models.RoomFilter, party models.Party, pagination *models.Pagination) ([]models.RoomType, models.SearchFacets, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, models.SearchFacets{}, err
+	}
+	return s.roomTypeRepo.FindAvailable(checkInDate, checkOutDate, filter, party, pagination, s.clock.Now())
+}
+
+// validateRoomType memvalidasi data tipe kamar sebelum disimpan
+func (s *roomTypeServiceImpl) validateRoomType(roomType *models.RoomType) error {
+	roomType.Code = strings.TrimSpace(roomType.Code)
+	if roomType.Code == "" || roomType.Name == "" || roomType.MaxOccupancy < 1 ||
+		!roomType.Price.IsPositive() || !roomType.Price.InBaseCurrency() {
+		return errors.New("data tipe kamar tidak lengkap atau tidak valid")
+	}
+	if roomType.MaxAdults < 0 || roomType.MaxAdults > roomType.MaxOccupancy ||
+		roomType.BaseOccupancy < 0 || roomType.BaseOccupancy > roomType.MaxOccupancy ||
+		roomType.FreeChildMaxAge < 0 || roomType.FreeChildMaxAge > models.MaxChildAge ||
+		roomType.ExtraAdultPrice.IsNegative() || !roomType.ExtraAdultPrice.InBaseCurrency() ||
+		roomType.ChildPrice.IsNegative() || !roomType.ChildPrice.InBaseCurrency() {
+		return errors.New("aturan okupansi atau biaya tambahan tipe kamar tidak valid")
+	}
+	if err := resolveProperty(s.propertyRepo, roomType.PropertyID); err != nil {
+		return err
+	}
+	policyID, err := resolveCancellationPolicy(s.policyRepo, roomType.CancellationPolicyID)
+	if err != nil {
+		return err
+	}
+	roomType.CancellationPolicyID = policyID
+	return nil
+}
+
+// CreateRoomType: Membuat tipe kamar baru (Admin Only)
+func (s *roomTypeServiceImpl) CreateRoomType(roomType *models.RoomType) (*models.RoomType, error) {
+	if err := s.validateRoomType(roomType); err != nil {
+		return nil, err
+	}
+	if err := s.roomTypeRepo.Create(roomType); err != nil {
+		return nil, err
+	}
+	return roomType, nil
+}
+
+// UpdateRoomType: Mengubah tipe kamar (Admin Only). Booking lama tetap memakai snapshot harganya.
+func (s *roomTypeServiceImpl) UpdateRoomType(roomType *models.RoomType) (*models.RoomType, error) {
+	if err := s.validateRoomType(roomType); err != nil {
+		return nil, err
+	}
+	// Relasi hasil preload dilepas agar Save tidak menimpa CancellationPolicyID yang baru
+	roomType.CancellationPolicy = nil
+	roomType.Property = nil
+
+	if err := s.roomTypeRepo.Update(roomType); err != nil {
+		return nil, err
+	}
+	return roomType, nil
+}
+
+// DeleteRoomType: Menghapus tipe kamar yang sudah tidak memiliki kamar fisik (Admin Only)
+func (s *roomTypeServiceImpl) DeleteRoomType(roomTypeID uint) error {
+	if _, err := s.GetRoomTypeByID(roomTypeID); err != nil {
+		return err
+	}
+	count, err := s.roomRepo.CountByRoomType(roomTypeID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.ErrRoomTypeInUse
+	}
+	return s.roomTypeRepo.Delete(roomTypeID)
+}
@@ -0,0 +1,278 @@
+package services_test
+
+import (
+	"sort"
+	"testing"
+
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/internal/infra/database/mysql/mysqltest"
+)
+
+// inventoryFixture adalah data pencarian bersama: tiga tipe kamar dengan fasilitas, batasan
+// masa inap pada DLX, dan satu booking STD
+type inventoryFixture struct {
+	deluxe, family, standard *models.RoomType
+	deluxeRooms              []models.Room
+}
+
+// Hari (offset dari testNow) batasan masa inap DLX
+const (
+	closedToArrivalDay   = 20
+	closedToDepartureDay = 25
+	minStayDay           = 30 // Minimal 3 malam untuk check-in pada hari ini
+	maxStayDay           = 40 // Maksimal 2 malam untuk check-in pada hari ini
+	standardBookedDay    = 10 // STD terisi malam ke-10 dan ke-11
+)
+
+// seedInventory menyiapkan:
+//   - DLX: 2 kamar, maks 2 tamu, fasilitas WIFI & SEA_VIEW di semua kamar, BATHTUB hanya di satu kamar
+//   - FAM: 1 kamar, maks 4 tamu dengan maks 2 dewasa, fasilitas WIFI
+//   - STD: 1 kamar, maks 2 tamu, tanpa fasilitas, terisi booking malam standardBookedDay..+1
+func (e *testEnv) seedInventory() *inventoryFixture {
+	e.t.Helper()
+
+	f := &inventoryFixture{}
+	f.deluxe, f.deluxeRooms = e.createRoomType("DLX", 500000, 2)
+	f.family, _ = e.createRoomType("FAM", 900000, 1)
+	f.standard, _ = e.createRoomType("STD", 300000, 1)
+	if err := e.db.Model(f.family).Updates(map[string]interface{}{"max_occupancy": 4, "max_adults": 2}).Error; err != nil {
+		e.t.Fatal(err)
+	}
+
+	amenity := func(code string) *models.Amenity {
+		a := &models.Amenity{Code: code, Name: code}
+		mysqltest.Create(e.t, e.db, a)
+		return a
+	}
+	wifi, seaView, bathtub := amenity("WIFI"), amenity("SEA_VIEW"), amenity("BATHTUB")
+	familyRooms := e.roomsOf(f.family.ID)
+	for _, link := range []models.RoomAmenity{
+		{RoomID: f.deluxeRooms[0].ID, AmenityID: wifi.ID},
+		{RoomID: f.deluxeRooms[0].ID, AmenityID: seaView.ID},
+		{RoomID: f.deluxeRooms[0].ID, AmenityID: bathtub.ID},
+		{RoomID: f.deluxeRooms[1].ID, AmenityID: wifi.ID},
+		{RoomID: f.deluxeRooms[1].ID, AmenityID: seaView.ID},
+		{RoomID: familyRooms[0].ID, AmenityID: wifi.ID},
+	} {
+		mysqltest.Create(e.t, e.db, &link)
+	}
+
+	restrict := func(day int, rule models.StayRestriction) {
+		date, _ := stay(day, 0)
+		rule.PropertyID = e.property.ID
+		rule.RoomTypeID = &f.deluxe.ID
+		rule.StartDate, rule.EndDate = date, date
+		mysqltest.Create(e.t, e.db, &rule)
+	}
+	restrict(closedToArrivalDay, models.StayRestriction{ClosedToArrival: true})
+	restrict(closedToDepartureDay, models.StayRestriction{ClosedToDeparture: true})
+	restrict(minStayDay, models.StayRestriction{MinStay: 3})
+	restrict(maxStayDay, models.StayRestriction{MaxStay: 2})
+
+	guest := e.createMember("seed")
+	checkIn, checkOut := stay(standardBookedDay, 2)
+	if _, err := e.bookings.CreateBooking(newBooking(guest.ID, f.standard.ID, checkIn, checkOut), services.BookingOptions{}); err != nil {
+		e.t.Fatalf("gagal membuat booking STD: %v", err)
+	}
+	return f
+}
+
+// roomsOf membaca kamar fisik sebuah tipe kamar
+func (e *testEnv) roomsOf(roomTypeID uint) []models.Room {
+	e.t.Helper()
+
+	var rooms []models.Room
+	if err := e.db.Where("room_type_id = ?", roomTypeID).Order("id asc").Find(&rooms).Error; err != nil {
+		e.t.Fatal(err)
+	}
+	return rooms
+}
+
+// searchCodes menjalankan pencarian ketersediaan dan mengembalikan kode tipe kamar (terurut) beserta sisanya
+func (e *testEnv) searchCodes(offset, nights int, filter models.RoomFilter, party models.Party) ([]string, map[string]int, models.SearchFacets) {
+	e.t.Helper()
+
+	checkIn, checkOut := stay(offset, nights)
+	roomTypes, facets, err := e.roomTypes.GetAvailableRoomTypes(checkIn.Format("2006-01-02"), checkOut.Format("2006-01-02"),
+		filter, party, &models.Pagination{Sort: "id asc"})
+	if err != nil {
+		e.t.Fatalf("GetAvailableRoomTypes: %v", err)
+	}
+	codes := []string{}
+	remaining := make(map[string]int, len(roomTypes))
+	for _, roomType := range roomTypes {
+		codes = append(codes, roomType.Code)
+		remaining[roomType.Code] = *roomType.Remaining
+	}
+	sort.Strings(codes)
+	return codes, remaining, facets
+}
+
+func TestGetAvailableRoomTypes(t *testing.T) {
+	env := newTestEnv(t)
+	env.seedInventory()
+
+	adults := func(n int, childAges ...int) models.Party {
+		return models.Party{Adults: n, ChildAges: childAges}
+	}
+	tests := []struct {
+		name   string
+		offset int
+		nights int
+		filter models.RoomFilter
+		party  models.Party
+		want   []string
+	}{
+		// Inventori & check-out eksklusif
+		{name: "tanpa batasan semua tipe tersedia", offset: 5, nights: 2, want: []string{"DLX", "FAM", "STD"}},
+		{name: "STD penuh pada malam yang dipesan", offset: standardBookedDay + 1, nights: 2, want: []string{"DLX", "FAM"}},
+		{name: "STD penuh sebelum check-in booking lain", offset: standardBookedDay - 1, nights: 2, want: []string{"DLX", "FAM"}},
+		{name: "check-in pada tanggal check-out booking lain", offset: standardBookedDay + 2, nights: 2, want: []string{"DLX", "FAM", "STD"}},
+		{name: "check-out pada tanggal check-in booking lain", offset: standardBookedDay - 2, nights: 2, want: []string{"DLX", "FAM", "STD"}},
+
+		// Closed to arrival / departure pada tanggal batas
+		{name: "CTA pada tanggal check-in", offset: closedToArrivalDay, nights: 2, want: []string{"FAM", "STD"}},
+		{name: "CTA di tengah masa inap tidak berlaku", offset: closedToArrivalDay - 1, nights: 2, want: []string{"DLX", "FAM", "STD"}},
+		{name: "CTA pada tanggal check-out tidak berlaku", offset: closedToArrivalDay - 2, nights: 2, want: []string{"DLX", "FAM", "STD"}},
+		{name: "CTD pada tanggal check-out", offset: closedToDepartureDay - 2, nights: 2, want: []string{"FAM", "STD"}},
+		{name: "CTD pada tanggal check-in tidak berlaku", offset: closedToDepartureDay, nights: 2, want: []string{"DLX", "FAM", "STD"}},
+		{name: "CTD di tengah masa inap tidak berlaku", offset: closedToDepartureDay - 1, nights: 2, want: []string{"DLX", "FAM", "STD"}},
+
+		// Min/max LOS berdasarkan tanggal check-in
+		{name: "kurang dari min LOS", offset: minStayDay, nights: 2, want: []string{"FAM", "STD"}},
+		{name: "tepat min LOS", offset: minStayDay, nights: 3, want: []string{"DLX", "FAM", "STD"}},
+		{name: "min LOS di tengah masa inap tidak berlaku", offset: minStayDay - 1, nights: 2, want: []string{"DLX", "FAM", "STD"}},
+		{name: "lebih dari max LOS", offset: maxStayDay, nights: 3, want: []string{"FAM", "STD"}},
+		{name: "tepat max LOS", offset: maxStayDay, nights: 2, want: []string{"DLX", "FAM", "STD"}},
+
+		// Rombongan & okupansi
+		{name: "dua dewasa muat di semua tipe", offset: 5, nights: 2, party: adults(2), want: []string{"DLX", "FAM", "STD"}},
+		{name: "dua dewasa dua anak hanya FAM", offset: 5, nights: 2, party: adults(2, 5, 8), want: []string{"FAM"}},
+		{name: "tiga dewasa melebihi batas dewasa FAM", offset: 5, nights: 2, party: adults(3), want: []string{}},
+		{name: "filter kapasitas minimal", offset: 5, nights: 2, filter: models.RoomFilter{Occupancy: 3}, want: []string{"FAM"}},
+
+		// Fasilitas: hanya yang dimiliki semua kamar tipe tersebut
+		{name: "fasilitas di semua kamar DLX & FAM", offset: 5, nights: 2, filter: models.RoomFilter{Amenities: []string{"WIFI"}}, want: []string{"DLX", "FAM"}},
+		{name: "gabungan fasilitas", offset: 5, nights: 2, filter: models.RoomFilter{Amenities: []string{"WIFI", "SEA_VIEW"}}, want: []string{"DLX"}},
+		{name: "fasilitas hanya di sebagian kamar", offset: 5, nights: 2, filter: models.RoomFilter{Amenities: []string{"BATHTUB"}}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, _ := env.searchCodes(tt.offset, tt.nights, tt.filter, tt.party)
+			if len(got) != len(tt.want) {
+				t.Fatalf("tipe tersedia %v, ingin %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("tipe tersedia %v, ingin %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAvailableRoomTypesRemaining(t *testing.T) {
+	env := newTestEnv(t)
+	f := env.seedInventory()
+
+	// Booking DLX tanpa kamar fisik tetap mengurangi sisa tipe
+	guest := env.createMember("guest")
+	checkIn, checkOut := stay(5, 2)
+	if _, err := env.bookings.CreateBooking(newBooking(guest.ID, f.deluxe.ID, checkIn, checkOut), services.BookingOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		offset int
+		nights int
+		want   int
+	}{
+		{name: "beririsan penuh", offset: 5, nights: 2, want: 1},
+		{name: "beririsan satu malam", offset: 6, nights: 3, want: 1},
+		{name: "mulai di tanggal check-out", offset: 7, nights: 2, want: 2},
+		{name: "berakhir di tanggal check-in", offset: 3, nights: 2, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, remaining, _ := env.searchCodes(tt.offset, tt.nights, models.RoomFilter{}, models.Party{})
+			if remaining["DLX"] != tt.want {
+				t.Fatalf("sisa DLX %d, ingin %d", remaining["DLX"], tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAvailableRoomTypesFacets(t *testing.T) {
+	env := newTestEnv(t)
+	f := env.seedInventory()
+
+	facetCounts := func(facets []models.FacetCount) map[string]int {
+		counts := make(map[string]int, len(facets))
+		for _, facet := range facets {
+			counts[facet.Code] = facet.Count
+		}
+		return counts
+	}
+	tests := []struct {
+		name          string
+		filter        models.RoomFilter
+		wantAmenities map[string]int
+		wantRoomTypes map[string]int
+		wantMinPrice  int64
+		wantMaxPrice  int64
+	}{
+		{
+			name:          "tanpa filter",
+			wantAmenities: map[string]int{"WIFI": 2, "SEA_VIEW": 1},
+			wantRoomTypes: map[string]int{"DLX": 1, "FAM": 1, "STD": 1},
+			wantMinPrice:  300000, wantMaxPrice: 900000,
+		},
+		{
+			name:          "filter fasilitas menyaring facet tipe & harga",
+			filter:        models.RoomFilter{Amenities: []string{"SEA_VIEW"}},
+			wantAmenities: map[string]int{"WIFI": 1, "SEA_VIEW": 1},
+			wantRoomTypes: map[string]int{"DLX": 1},
+			wantMinPrice:  500000, wantMaxPrice: 500000,
+		},
+		{
+			name:          "filter tipe kamar tidak menyaring facet tipe",
+			filter:        models.RoomFilter{RoomTypeID: f.deluxe.ID},
+			wantAmenities: map[string]int{"WIFI": 1, "SEA_VIEW": 1},
+			wantRoomTypes: map[string]int{"DLX": 1, "FAM": 1, "STD": 1},
+			wantMinPrice:  500000, wantMaxPrice: 500000,
+		},
+		{
+			name:          "filter harga tidak menyaring facet harga",
+			filter:        models.RoomFilter{MinPrice: money.New(400000, "IDR"), MaxPrice: money.New(600000, "IDR")},
+			wantAmenities: map[string]int{"WIFI": 1, "SEA_VIEW": 1},
+			wantRoomTypes: map[string]int{"DLX": 1},
+			wantMinPrice:  300000, wantMaxPrice: 900000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, facets := env.searchCodes(5, 2, tt.filter, models.Party{})
+
+			amenities, roomTypes := facetCounts(facets.Amenities), facetCounts(facets.RoomTypes)
+			if len(amenities) != len(tt.wantAmenities) || len(roomTypes) != len(tt.wantRoomTypes) {
+				t.Fatalf("facet fasilitas %v & tipe %v, ingin %v & %v", amenities, roomTypes, tt.wantAmenities, tt.wantRoomTypes)
+			}
+			for code, want := range tt.wantAmenities {
+				if amenities[code] != want {
+					t.Errorf("facet fasilitas %s = %d, ingin %d", code, amenities[code], want)
+				}
+			}
+			for code, want := range tt.wantRoomTypes {
+				if roomTypes[code] != want {
+					t.Errorf("facet tipe %s = %d, ingin %d", code, roomTypes[code], want)
+				}
+			}
+			if facets.Price == nil || facets.Price.Min.Amount != tt.wantMinPrice || facets.Price.Max.Amount != tt.wantMaxPrice {
+				t.Errorf("facet harga %+v, ingin %d-%d", facets.Price, tt.wantMinPrice, tt.wantMaxPrice)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// runPeriodically menjalankan fn setiap interval sampai ctx dibatalkan
+func runPeriodically(ctx context.Context, name string, interval time.Duration, fn func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := fn(); err != nil {
+				log.Printf("Job %s gagal: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package services
+
+import "backend/internal/domain/models"
+
+// StayRestrictionService mendefinisikan kontrak pengelolaan batasan masa inap (Admin):
+// min/max lama inap, closed-to-arrival/departure, dan jendela pemesanan per tipe kamar atau kamar
+type StayRestrictionService interface {
+	GetRestrictions(roomTypeID, roomID, propertyID uint, pagination *models.Pagination) ([]models.StayRestriction, error)
+	GetRestrictionByID(restrictionID uint) (*models.StayRestriction, error)
+	CreateRestriction(restriction *models.StayRestriction) (*models.StayRestriction, error)
+	UpdateRestriction(restriction *models.StayRestriction) (*models.StayRestriction, error)
+	DeleteRestriction(restrictionID uint) error
+}
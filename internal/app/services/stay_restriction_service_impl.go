@@ -0,0 +1,93 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type stayRestrictionServiceImpl struct {
+	restrictionRepo repositories.StayRestrictionRepository
+	roomTypeRepo    repositories.RoomTypeRepository
+	roomRepo        repositories.RoomRepository
+}
+
+func NewStayRestrictionService(srRepo repositories.StayRestrictionRepository, rtRepo repositories.RoomTypeRepository, rRepo repositories.RoomRepository) StayRestrictionService {
+	return &stayRestrictionServiceImpl{restrictionRepo: srRepo, roomTypeRepo: rtRepo, roomRepo: rRepo}
+}
+
+// GetRestrictions: Mengambil semua batasan (opsional difilter per tipe kamar, kamar, dan/atau properti)
+func (s *stayRestrictionServiceImpl) GetRestrictions(roomTypeID, roomID, propertyID uint, pagination *models.Pagination) ([]models.StayRestriction, error) {
+	return s.restrictionRepo.FindAll(roomTypeID, roomID, propertyID, pagination)
+}
+
+// GetRestrictionByID: Mengambil detail batasan
+func (s *stayRestrictionServiceImpl) GetRestrictionByID(restrictionID uint) (*models.StayRestriction, error) {
+	restriction, err := s.restrictionRepo.FindByID(restrictionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrStayRestrictionNotFound
+		}
+		return nil, err
+	}
+	return restriction, nil
+}
+
+// CreateRestriction: Membuat batasan untuk tipe kamar atau kamar fisik. Properti diturunkan dari
+// targetnya; PropertyID yang sudah terisi (staf properti) harus sama dengan properti target.
+func (s *stayRestrictionServiceImpl) CreateRestriction(restriction *models.StayRestriction) (*models.StayRestriction, error) {
+	if err := restriction.Validate(); err != nil {
+		return nil, err
+	}
+
+	var propertyID uint
+	if restriction.RoomTypeID != nil {
+		roomType, err := s.roomTypeRepo.FindByID(*restriction.RoomTypeID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, models.ErrRoomTypeNotFound
+			}
+			return nil, err
+		}
+		propertyID = roomType.PropertyID
+	} else {
+		room, err := s.roomRepo.FindByID(*restriction.RoomID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, models.ErrRoomNotFound
+			}
+			return nil, err
+		}
+		propertyID = room.PropertyID
+	}
+	if restriction.PropertyID != 0 && restriction.PropertyID != propertyID {
+		return nil, models.ErrPropertyAccessDenied
+	}
+	restriction.PropertyID = propertyID
+
+	if err := s.restrictionRepo.Create(restriction); err != nil {
+		return nil, err
+	}
+	return restriction, nil
+}
+
+// UpdateRestriction: Mengubah rentang & nilai batasan (target tidak dapat dipindah)
+func (s *stayRestrictionServiceImpl) UpdateRestriction(restriction *models.StayRestriction) (*models.StayRestriction, error) {
+	if err := restriction.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.restrictionRepo.Update(restriction); err != nil {
+		return nil, err
+	}
+	return restriction, nil
+}
+
+// DeleteRestriction: Menghapus batasan (booking yang sudah ada tidak terpengaruh)
+func (s *stayRestrictionServiceImpl) DeleteRestriction(restrictionID uint) error {
+	if _, err := s.GetRestrictionByID(restrictionID); err != nil {
+		return err
+	}
+	return s.restrictionRepo.Delete(restrictionID)
+}
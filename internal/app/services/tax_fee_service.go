@@ -0,0 +1,12 @@
+package services
+
+import "backend/internal/domain/models"
+
+// TaxFeeService mendefinisikan kontrak untuk pengelolaan pajak & biaya (Admin)
+type TaxFeeService interface {
+	GetTaxFees(propertyID uint, pagination *models.Pagination) ([]models.TaxFee, error)
+	GetTaxFeeByID(taxFeeID uint) (*models.TaxFee, error)
+	CreateTaxFee(taxFee *models.TaxFee) (*models.TaxFee, error)
+	UpdateTaxFee(taxFee *models.TaxFee) (*models.TaxFee, error)
+	DeleteTaxFee(taxFeeID uint) error
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type taxFeeServiceImpl struct {
+	taxFeeRepo   repositories.TaxFeeRepository
+	propertyRepo repositories.PropertyRepository
+}
+
+func NewTaxFeeService(tfRepo repositories.TaxFeeRepository, pRepo repositories.PropertyRepository) TaxFeeService {
+	return &taxFeeServiceImpl{taxFeeRepo: tfRepo, propertyRepo: pRepo}
+}
+
+// validateTaxFee: Validasi konfigurasi pajak/biaya sebelum disimpan
+func validateTaxFee(taxFee *models.TaxFee) error {
+	taxFee.Code = strings.ToUpper(strings.TrimSpace(taxFee.Code))
+	if taxFee.Code == "" || taxFee.Name == "" || taxFee.RateBasisPoints < 0 || taxFee.Amount.IsNegative() {
+		return errors.New("data pajak/biaya tidak lengkap atau tidak valid")
+	}
+	if taxFee.Kind != models.ComponentTax && taxFee.Kind != models.ComponentFee {
+		return errors.New("jenis harus tax atau fee")
+	}
+	if taxFee.CalcType != models.CalcPercentage && taxFee.CalcType != models.CalcFlat {
+		return errors.New("cara hitung harus percentage atau flat")
+	}
+	if taxFee.Basis == "" {
+		taxFee.Basis = models.BasisPerStay
+	}
+	if taxFee.Basis != models.BasisPerNight && taxFee.Basis != models.BasisPerStay {
+		return errors.New("basis harus per_night atau per_stay")
+	}
+	if taxFee.CalcType == models.CalcPercentage && taxFee.RateBasisPoints > 10000 {
+		return errors.New("persentase maksimal 100")
+	}
+	return nil
+}
+
+// GetTaxFees: Mengambil semua konfigurasi pajak & biaya (propertyID 0 = semua properti)
+func (s *taxFeeServiceImpl) GetTaxFees(propertyID uint, pagination *models.Pagination) ([]models.TaxFee, error) {
+	return s.taxFeeRepo.FindAll(propertyID, pagination)
+}
+
+// GetTaxFeeByID: Mengambil detail pajak/biaya
+func (s *taxFeeServiceImpl) GetTaxFeeByID(taxFeeID uint) (*models.TaxFee, error) {
+	taxFee, err := s.taxFeeRepo.FindByID(taxFeeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrTaxFeeNotFound
+		}
+		return nil, err
+	}
+	return taxFee, nil
+}
+
+// CreateTaxFee: Membuat pajak/biaya baru (langsung berlaku untuk quote & booking berikutnya)
+func (s *taxFeeServiceImpl) CreateTaxFee(taxFee *models.TaxFee) (*models.TaxFee, error) {
+	if err := validateTaxFee(taxFee); err != nil {
+		return nil, err
+	}
+	if err := resolveProperty(s.propertyRepo, taxFee.PropertyID); err != nil {
+		return nil, err
+	}
+	if err := s.taxFeeRepo.Create(taxFee); err != nil {
+		return nil, err
+	}
+	return taxFee, nil
+}
+
+// UpdateTaxFee: Mengubah pajak/biaya (booking lama tetap memakai snapshot komponennya)
+func (s *taxFeeServiceImpl) UpdateTaxFee(taxFee *models.TaxFee) (*models.TaxFee, error) {
+	if err := validateTaxFee(taxFee); err != nil {
+		return nil, err
+	}
+	if err := s.taxFeeRepo.Update(taxFee); err != nil {
+		return nil, err
+	}
+	return taxFee, nil
+}
+
+// DeleteTaxFee: Menghapus pajak/biaya
+func (s *taxFeeServiceImpl) DeleteTaxFee(taxFeeID uint) error {
+	if _, err := s.GetTaxFeeByID(taxFeeID); err != nil {
+		return err
+	}
+	return s.taxFeeRepo.Delete(taxFeeID)
+}
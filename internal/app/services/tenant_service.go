@@ -0,0 +1,9 @@
+package services
+
+import "backend/internal/domain/models"
+
+// TenantService memetakan request ke tenant (perusahaan hotel) pemilik data
+type TenantService interface {
+	// ResolveTenant mencari tenant aktif dari kode header tenant, lalu hostname, lalu tenant default
+	ResolveTenant(code, host string) (*models.Tenant, error)
+}
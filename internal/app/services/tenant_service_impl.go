@@ -0,0 +1,49 @@
+package services
+
+import (
+	"backend/internal/config"
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type tenantServiceImpl struct {
+	tenantRepo repositories.TenantRepository
+	cfg        *config.Config
+}
+
+func NewTenantService(tRepo repositories.TenantRepository, cfg *config.Config) TenantService {
+	return &tenantServiceImpl{tenantRepo: tRepo, cfg: cfg}
+}
+
+func (s *tenantServiceImpl) ResolveTenant(code, host string) (*models.Tenant, error) {
+	// Header tenant eksplisit tidak pernah jatuh ke tenant lain bila kodenya salah
+	if code != "" {
+		return s.find(s.tenantRepo.FindByCode(code))
+	}
+
+	if host != "" {
+		tenant, err := s.tenantRepo.FindByDomain(host)
+		if err == nil {
+			return tenant, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	if s.cfg.DefaultTenant == "" {
+		return nil, models.ErrTenantNotFound
+	}
+	return s.find(s.tenantRepo.FindByCode(s.cfg.DefaultTenant))
+}
+
+// find menerjemahkan record not found menjadi ErrTenantNotFound
+func (s *tenantServiceImpl) find(tenant *models.Tenant, err error) (*models.Tenant, error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, models.ErrTenantNotFound
+	}
+	return tenant, err
+}
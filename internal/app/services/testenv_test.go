@@ -0,0 +1,195 @@
+package services_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"backend/internal/app/services"
+	"backend/internal/config"
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	domainrepos "backend/internal/domain/repositories"
+	"backend/internal/infra/database/mysql/mysqltest"
+	"backend/internal/infra/gorm/repositories"
+	"backend/internal/infra/payments"
+
+	"gorm.io/gorm"
+)
+
+// testNow adalah waktu awal fakeClock di semua test service
+var testNow = time.Date(2030, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+// fakeClock adalah Clock yang hanya maju lewat Advance
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// testEnv adalah satu tenant di database test beserta service yang terikat ke tenant tersebut
+type testEnv struct {
+	t        *testing.T
+	root     *gorm.DB // Tanpa tenant (seperti koneksi job latar belakang)
+	db       *gorm.DB // Terikat tenant
+	clock    *fakeClock
+	cfg      *config.Config
+	provider services.PaymentProvider
+	property *models.Property
+
+	bookingRepo  domainrepos.BookingRepository
+	roomTypeRepo domainrepos.RoomTypeRepository
+	transactor   domainrepos.Transactor
+
+	bookings  services.BookingService
+	payments  services.PaymentService
+	rooms     services.RoomService
+	roomTypes services.RoomTypeService
+}
+
+// newTestEnv menyiapkan database MySQL baru (lihat mysqltest) dengan satu tenant dan satu properti
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	root := mysqltest.Open(t)
+	db := repositories.WithTenant(root, mysqltest.CreateTenant(t, root, "test"))
+
+	money.SetBaseCurrency("IDR")
+	cfg := &config.Config{
+		JWTSecret:              "test-secret",
+		HoldTTLMinutes:         15,
+		PaymentDeadlineMinutes: 60,
+		QuoteTTLMinutes:        30,
+		CheckInHour:            14,
+		BaseCurrency:           "IDR",
+		PaymentProvider:        "mock",
+		PaymentWebhookSecret:   "test-webhook-secret",
+	}
+	clock := &fakeClock{now: testNow}
+	provider := payments.NewMockProvider(cfg.PaymentWebhookSecret, clock)
+
+	userRepo := repositories.NewGormRepository(db)
+	roomRepo := repositories.NewGormRoomRepository(db)
+	roomTypeRepo := repositories.NewGormRoomTypeRepository(db)
+	propertyRepo := repositories.NewGormPropertyRepository(db)
+	bookingRepo := repositories.NewGormBookingRepository(db)
+	ratePlanRepo := repositories.NewGormRatePlanRepository(db)
+	promoCodeRepo := repositories.NewGormPromoCodeRepository(db)
+	taxFeeRepo := repositories.NewGormTaxFeeRepository(db)
+	exchangeRateRepo := repositories.NewGormExchangeRateRepository(db)
+	paymentRepo := repositories.NewGormPaymentRepository(db)
+	amenityRepo := repositories.NewGormAmenityRepository(db)
+	stayRestrictionRepo := repositories.NewGormStayRestrictionRepository(db)
+	transactor := repositories.NewGormTransactor(db)
+	publisher := services.NewLogEventPublisher()
+
+	pricingService := services.NewPricingService(roomTypeRepo, ratePlanRepo, promoCodeRepo, taxFeeRepo, exchangeRateRepo, cfg, clock)
+	paymentService := services.NewPaymentService(bookingRepo, paymentRepo, transactor, provider, publisher, clock)
+
+	env := &testEnv{
+		t:            t,
+		root:         root,
+		db:           db,
+		clock:        clock,
+		cfg:          cfg,
+		provider:     provider,
+		bookingRepo:  bookingRepo,
+		roomTypeRepo: roomTypeRepo,
+		transactor:   transactor,
+		bookings:     services.NewBookingService(bookingRepo, roomRepo, repositories.NewGormReviewRepository(db), userRepo, transactor, pricingService, paymentService, cfg, clock),
+		payments:     paymentService,
+		rooms:        services.NewRoomService(roomRepo, repositories.NewGormRoomImageRepository(db), roomTypeRepo, propertyRepo, amenityRepo, bookingRepo, ratePlanRepo, stayRestrictionRepo, clock),
+		roomTypes:    services.NewRoomTypeService(roomTypeRepo, roomRepo, repositories.NewGormCancellationPolicyRepository(db), propertyRepo, clock),
+	}
+	env.property = &models.Property{Code: "JKT", Name: "MyHotel Jakarta", City: "Jakarta", IsActive: true}
+	mysqltest.Create(t, db, env.property)
+	return env
+}
+
+// createMember membuat member yang emailnya sudah diverifikasi
+func (e *testEnv) createMember(username string) *models.User {
+	e.t.Helper()
+
+	verifiedAt := testNow
+	user := &models.User{
+		Username:        username,
+		Password:        "x",
+		Email:           username + "@example.com",
+		FullName:        username,
+		Role:            models.RoleMember,
+		EmailVerifiedAt: &verifiedAt,
+	}
+	mysqltest.Create(e.t, e.db, user)
+	return user
+}
+
+// createStaff membuat user staf dengan role tertentu
+func (e *testEnv) createStaff(username, role string) *models.User {
+	e.t.Helper()
+
+	user := &models.User{Username: username, Password: "x", Email: username + "@example.com", FullName: username, Role: role}
+	mysqltest.Create(e.t, e.db, user)
+	return user
+}
+
+// createRoomType membuat tipe kamar dengan harga per malam (mata uang dasar) dan sejumlah kamar fisik
+func (e *testEnv) createRoomType(code string, price int64, rooms int) (*models.RoomType, []models.Room) {
+	e.t.Helper()
+
+	roomType := &models.RoomType{
+		PropertyID:   e.property.ID,
+		Code:         code,
+		Name:         code,
+		Price:        money.New(price, "IDR"),
+		MaxOccupancy: 2,
+	}
+	mysqltest.Create(e.t, e.db, roomType)
+
+	created := make([]models.Room, rooms)
+	for i := range created {
+		created[i] = models.Room{
+			PropertyID: e.property.ID,
+			RoomNumber: fmt.Sprintf("%s-%d", code, i+1),
+			RoomTypeID: roomType.ID,
+			Status:     "available",
+		}
+		mysqltest.Create(e.t, e.db, &created[i])
+	}
+	return roomType, created
+}
+
+// stay mengembalikan tanggal check-in (hari ke-offset setelah testNow) dan check-out setelah nights malam
+func stay(offset, nights int) (time.Time, time.Time) {
+	checkIn := time.Date(testNow.Year(), testNow.Month(), testNow.Day()+offset, 0, 0, 0, 0, time.UTC)
+	return checkIn, checkIn.AddDate(0, 0, nights)
+}
+
+// newBooking menyusun booking satu dewasa untuk tipe kamar dan tanggal tersebut
+func newBooking(userID, roomTypeID uint, checkIn, checkOut time.Time) *models.Booking {
+	booking := &models.Booking{UserID: userID, RoomTypeID: roomTypeID, CheckInDate: checkIn, CheckOutDate: checkOut}
+	booking.SetParty(models.Party{Adults: 1})
+	return booking
+}
+
+// bookingStatus membaca ulang status booking dari database
+func (e *testEnv) bookingStatus(bookingID uint) string {
+	e.t.Helper()
+
+	booking, err := e.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		e.t.Fatalf("gagal membaca booking %d: %v", bookingID, err)
+	}
+	return booking.BookingStatus
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"backend/internal/domain/repositories"
+	"context"
+	"time"
+)
+
+// TokenCleanupJob secara berkala menghapus refresh token dan daftar pencabutan access token
+// yang sudah kedaluwarsa, agar pengecekan jti di JWTMiddleware tetap ringan
+type TokenCleanupJob struct {
+	tokenRepo repositories.AuthTokenRepository
+	clock     Clock
+	interval  time.Duration
+}
+
+func NewTokenCleanupJob(tokenRepo repositories.AuthTokenRepository, clock Clock, interval time.Duration) *TokenCleanupJob {
+	return &TokenCleanupJob{tokenRepo: tokenRepo, clock: clock, interval: interval}
+}
+
+// RunOnce menghapus token yang sudah kedaluwarsa menurut clock
+func (j *TokenCleanupJob) RunOnce() error {
+	return j.tokenRepo.DeleteExpired(j.clock.Now())
+}
+
+// Run menjalankan RunOnce setiap interval sampai ctx dibatalkan
+func (j *TokenCleanupJob) Run(ctx context.Context) {
+	runPeriodically(ctx, "token-cleanup", j.interval, j.RunOnce)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Parameter TOTP standar (RFC 6238) yang didukung semua aplikasi authenticator
+const (
+	totpPeriod = 30 * time.Second
+	totpDigits = 6
+	totpSkew   = 1 // Toleransi selisih jam perangkat: 1 time-step sebelum/sesudah
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// totpStep adalah nomor time-step pada waktu t
+func totpStep(t time.Time) int64 {
+	return t.Unix() / int64(totpPeriod/time.Second)
+}
+
+// totpCode menghitung kode TOTP (HOTP dengan counter time-step, HMAC-SHA1)
+func totpCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226 bagian 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// matchTOTP mencari time-step di sekitar now yang menghasilkan code; false jika tidak ada
+func matchTOTP(secret []byte, code string, now time.Time) (int64, bool) {
+	current := totpStep(now)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// otpauthURI membuat URI pendaftaran (format Key URI Google Authenticator) untuk QR code
+func otpauthURI(issuer, account string, secret []byte) string {
+	query := url.Values{}
+	query.Set("secret", base32NoPadding.EncodeToString(secret))
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpPeriod/time.Second)))
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + query.Encode()
+}
+
+// isTOTPCode mengecek apakah input berbentuk kode TOTP (6 digit), selain itu dianggap recovery code
+func isTOTPCode(code string) bool {
+	if len(code) != totpDigits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRecoveryCode membuat recovery code acak berformat XXXXX-XXXXX (50 bit, alfabet base32)
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, 7)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	code := base32NoPadding.EncodeToString(buf)[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeMFACode membuang spasi & tanda hubung dan menyeragamkan huruf besar
+func normalizeMFACode(code string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
+
+// sealSecret mengenkripsi secret TOTP dengan AES-256-GCM (kunci diturunkan dari key) untuk disimpan
+func sealSecret(key string, secret []byte) (string, error) {
+	gcm, err := secretCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, secret, nil)), nil
+}
+
+// openSecret membuka secret TOTP hasil sealSecret
+func openSecret(key, sealed string) ([]byte, error) {
+	gcm, err := secretCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("secret TOTP rusak")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+func secretCipher(key string) (cipher.AEAD, error) {
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
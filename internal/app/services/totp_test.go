@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/domain/models"
+	"backend/internal/infra/database/mysql/mysqltest"
+	"backend/internal/infra/gorm/repositories"
+)
+
+// rfc6238Secret adalah seed SHA-1 pada RFC 6238 Appendix B ("12345678901234567890")
+var rfc6238Secret = []byte("12345678901234567890")
+
+// TestTOTPCodeRFC6238 memakai test vector SHA-1 RFC 6238 Appendix B. Vector di RFC berisi 8 digit;
+// kode 6 digit adalah 6 digit terakhirnya karena keduanya sisa bagi dari nilai truncation yang sama.
+func TestTOTPCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string // 8 digit sesuai RFC
+	}{
+		{unix: 59, want: "94287082"},
+		{unix: 1111111109, want: "07081804"},
+		{unix: 1111111111, want: "14050471"},
+		{unix: 1234567890, want: "89005924"},
+		{unix: 2000000000, want: "69279037"},
+		{unix: 20000000000, want: "65353130"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got := totpCode(rfc6238Secret, totpStep(time.Unix(tt.unix, 0)))
+			if want := tt.want[len(tt.want)-totpDigits:]; got != want {
+				t.Fatalf("kode pada T=%d = %s, ingin %s", tt.unix, got, want)
+			}
+		})
+	}
+}
+
+func TestMatchTOTPWindow(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := totpStep(now)
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		offset int64 // Time-step kode relatif terhadap step saat ini
+		ok     bool
+	}{
+		{name: "step saat ini", now: now, offset: 0, ok: true},
+		{name: "satu step sebelumnya", now: now, offset: -1, ok: true},
+		{name: "satu step sesudahnya", now: now, offset: 1, ok: true},
+		{name: "dua step sebelumnya", now: now, offset: -2},
+		{name: "dua step sesudahnya", now: now, offset: 2},
+		{name: "awal step menerima step sebelumnya", now: time.Unix(current*30, 0), offset: -1, ok: true},
+		{name: "akhir step menolak dua step sebelumnya", now: time.Unix(current*30+29, 0), offset: -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := totpCode(rfc6238Secret, current+tt.offset)
+			step, ok := matchTOTP(rfc6238Secret, code, tt.now)
+			if ok != tt.ok {
+				t.Fatalf("ingin cocok %v, dapat %v", tt.ok, ok)
+			}
+			if ok && step != current+tt.offset {
+				t.Fatalf("ingin step %d, dapat %d", current+tt.offset, step)
+			}
+		})
+	}
+}
+
+// fixedClock adalah Clock yang waktunya diatur langsung oleh test
+type fixedClock struct{ now time.Time }
+
+func (c *fixedClock) Now() time.Time { return c.now }
+
+// TestVerifyRejectsReusedStep memastikan setiap time-step hanya bisa dipakai sekali, termasuk
+// step lama yang masih di dalam jendela toleransi. Kasus berjalan berurutan pada user yang sama.
+func TestVerifyRejectsReusedStep(t *testing.T) {
+	root := mysqltest.Open(t)
+	db := repositories.WithTenant(root, mysqltest.CreateTenant(t, root, "test"))
+	user := &models.User{Username: "admin", Password: "x", Email: "admin@example.com", FullName: "Admin", Role: models.RoleAdmin}
+	mysqltest.Create(t, db, user)
+
+	clock := &fixedClock{now: time.Date(2030, time.March, 1, 9, 0, 0, 0, time.UTC)}
+	cfg := &config.Config{MFAEncryptionKey: "test-mfa-key", MFAIssuer: "MyHotel"}
+	service := NewMFAService(repositories.NewGormMFARepository(db), repositories.NewGormRepository(db), repositories.NewGormRoleRepository(db), repositories.NewGormAuthTokenRepository(db), cfg, clock)
+
+	enrollment, err := service.Enroll(user.ID)
+	if err != nil {
+		t.Fatalf("Enroll: %v", err)
+	}
+	secret, err := base32NoPadding.DecodeString(enrollment.Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := totpStep(clock.now)
+	// Confirm memakai step saat ini sehingga step tersebut tercatat sudah dipakai
+	if _, err := service.Confirm(user.ID, totpCode(secret, current)); err != nil {
+		t.Fatalf("Confirm: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		step    int64 // Relatif terhadap step saat Confirm
+		wantErr error
+	}{
+		{name: "step yang dipakai Confirm ditolak", step: 0, wantErr: models.ErrInvalidMFACode},
+		{name: "step berikutnya di jendela diterima", step: 1},
+		{name: "step yang sama dikirim ulang ditolak", step: 1, wantErr: models.ErrInvalidMFACode},
+		{name: "step lebih lama di jendela ditolak", advance: totpPeriod, step: 0, wantErr: models.ErrInvalidMFACode},
+		{name: "step baru setelah waktu berjalan diterima", advance: totpPeriod, step: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.now = clock.now.Add(tt.advance)
+			err := service.Verify(user.ID, totpCode(secret, current+tt.step))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ingin %v, dapat %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -16,18 +17,192 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	JWTSecret  string
-	JWTExpHours int
+
+	// Token Autentikasi
+	AccessTokenTTLMinutes int // Masa berlaku access token (JWT), dibuat singkat karena bisa dicabut lewat jti
+	RefreshTokenTTLHours  int // Masa berlaku refresh token (dirotasi setiap dipakai)
+
+	// Pemulihan Akun & Verifikasi Email
+	PasswordResetTTLMinutes   int    // Masa berlaku token reset password
+	EmailVerificationTTLHours int    // Masa berlaku token verifikasi email
+	AppBaseURL                string // URL frontend, dipakai untuk tautan di email (reset password, verifikasi)
+
+	// Two-Factor Authentication (TOTP)
+	MFARequiredRoles       []string // Role yang wajib memakai 2FA untuk mengakses area admin, mis. ["admin"]
+	MFAIssuer              string   // Nama penerbit yang tampil di aplikasi authenticator
+	MFAEncryptionKey       string   // Kunci enkripsi secret TOTP di database
+	MFAChallengeTTLMinutes int      // Masa berlaku MFA challenge token antara langkah password dan kode
+
+	// Email
+	MailDriver    string // "smtp", "file" (tulis ke MailOutboxDir), atau "memory"
+	MailFrom      string // Alamat pengirim
+	MailOutboxDir string // Folder email keluar untuk driver "file"
+	SMTPHost      string
+	SMTPPort      string
+	SMTPUsername  string
+	SMTPPassword  string
+
+	// Booking Hold
+	HoldTTLMinutes           int // Lama kamar ditahan saat tamu memulai checkout
+	HoldSweepIntervalSeconds int // Interval sweeper yang melepas hold kedaluwarsa
+
+	// Batas Pembayaran
+	PaymentDeadlineMinutes         int // Lama booking pending boleh belum dibayar
+	PaymentDeadlineIntervalSeconds int // Interval job pembatalan booking yang lewat batas bayar
+
+	// Quote Harga
+	QuoteTTLMinutes int // Masa berlaku quote token
+
+	// Jam check-in hotel (0-23), acuan perhitungan batas waktu kebijakan pembatalan
+	CheckInHour int
+
+	// Mata Uang
+	BaseCurrency string // Mata uang dasar hotel (ISO 4217), semua kolom uang disimpan dalam mata uang ini
+
+	// Payment Gateway
+	PaymentProvider          string // Nama provider, saat ini hanya "mock"
+	PaymentWebhookSecret     string // Secret HMAC untuk verifikasi webhook provider
+	PaymentSimulationEnabled bool   // Mendaftarkan endpoint simulasi webhook provider mock, default mati
+
+	// Multi-tenant
+	TenantHeader  string // Header berisi kode tenant, diutamakan di atas hostname
+	DefaultTenant string // Kode tenant untuk request tanpa header/hostname terdaftar, kosong = ditolak
 }
 
-func LoadConfig() *Config{
+func LoadConfig() *Config {
 	err := godotenv.Load()
 	if err != nil {
 		log.Println("Perhatian: file .env tidak ditemukan, menggunakan environment variables sistem.")
 	}
 
-	expHours, err := strconv.Atoi(os.Getenv("JWT_EXPIRATION_HOURS"))
-	if err != nil {
-		expHours = 24
+	accessTTL, err := strconv.Atoi(os.Getenv("JWT_ACCESS_TTL_MINUTES"))
+	if err != nil || accessTTL <= 0 {
+		accessTTL = 15
+	}
+
+	refreshTTL, err := strconv.Atoi(os.Getenv("JWT_REFRESH_TTL_HOURS"))
+	if err != nil || refreshTTL <= 0 {
+		refreshTTL = 30 * 24
+	}
+
+	passwordResetTTL, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_TTL_MINUTES"))
+	if err != nil || passwordResetTTL <= 0 {
+		passwordResetTTL = 60
+	}
+
+	emailVerificationTTL, err := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_TTL_HOURS"))
+	if err != nil || emailVerificationTTL <= 0 {
+		emailVerificationTTL = 48
+	}
+
+	appBaseURL := os.Getenv("APP_BASE_URL")
+	if appBaseURL == "" {
+		appBaseURL = "http://localhost:3000"
+	}
+
+	// Kosong = 2FA opsional untuk semua role
+	var mfaRequiredRoles []string
+	for _, role := range strings.Split(os.Getenv("MFA_REQUIRED_ROLES"), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			mfaRequiredRoles = append(mfaRequiredRoles, role)
+		}
+	}
+
+	mfaIssuer := os.Getenv("MFA_ISSUER")
+	if mfaIssuer == "" {
+		mfaIssuer = "MyHotel"
+	}
+
+	// Tanpa kunci khusus, secret TOTP dienkripsi dengan turunan JWT secret
+	mfaEncryptionKey := os.Getenv("MFA_ENCRYPTION_KEY")
+	if mfaEncryptionKey == "" {
+		mfaEncryptionKey = os.Getenv("JWT_SECRET_KEY") + ":mfa"
+	}
+
+	mfaChallengeTTL, err := strconv.Atoi(os.Getenv("MFA_CHALLENGE_TTL_MINUTES"))
+	if err != nil || mfaChallengeTTL <= 0 {
+		mfaChallengeTTL = 5
+	}
+
+	// Tanpa konfigurasi, email ditulis ke folder lokal agar bisa dibuka saat development
+	mailDriver := os.Getenv("MAIL_DRIVER")
+	if mailDriver == "" {
+		mailDriver = "file"
+	}
+
+	mailFrom := os.Getenv("MAIL_FROM")
+	if mailFrom == "" {
+		mailFrom = "no-reply@myhotel.local"
+	}
+
+	mailOutboxDir := os.Getenv("MAIL_OUTBOX_DIR")
+	if mailOutboxDir == "" {
+		mailOutboxDir = "storage/outbox"
+	}
+
+	smtpPort := os.Getenv("SMTP_PORT")
+	if smtpPort == "" {
+		smtpPort = "587"
+	}
+
+	holdTTL, err := strconv.Atoi(os.Getenv("BOOKING_HOLD_TTL_MINUTES"))
+	if err != nil || holdTTL <= 0 {
+		holdTTL = 15
+	}
+
+	holdSweepInterval, err := strconv.Atoi(os.Getenv("BOOKING_HOLD_SWEEP_INTERVAL_SECONDS"))
+	if err != nil || holdSweepInterval <= 0 {
+		holdSweepInterval = 60
+	}
+
+	paymentDeadline, err := strconv.Atoi(os.Getenv("BOOKING_PAYMENT_DEADLINE_MINUTES"))
+	if err != nil || paymentDeadline <= 0 {
+		paymentDeadline = 24 * 60
+	}
+
+	paymentDeadlineInterval, err := strconv.Atoi(os.Getenv("BOOKING_PAYMENT_DEADLINE_INTERVAL_SECONDS"))
+	if err != nil || paymentDeadlineInterval <= 0 {
+		paymentDeadlineInterval = 300
+	}
+
+	quoteTTL, err := strconv.Atoi(os.Getenv("QUOTE_TTL_MINUTES"))
+	if err != nil || quoteTTL <= 0 {
+		quoteTTL = 15
+	}
+
+	checkInHour, err := strconv.Atoi(os.Getenv("CHECK_IN_HOUR"))
+	if err != nil || checkInHour < 0 || checkInHour > 23 {
+		checkInHour = 14
+	}
+
+	baseCurrency := os.Getenv("BASE_CURRENCY")
+	if baseCurrency == "" {
+		baseCurrency = "IDR"
+	}
+
+	paymentProvider := os.Getenv("PAYMENT_PROVIDER")
+	if paymentProvider == "" {
+		paymentProvider = "mock"
+	}
+
+	// Tanpa secret khusus, provider lokal memakai turunan JWT secret
+	paymentWebhookSecret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if paymentWebhookSecret == "" {
+		paymentWebhookSecret = os.Getenv("JWT_SECRET_KEY") + ":payment-webhook"
+	}
+
+	// Endpoint simulasi webhook hanya untuk pengembangan lokal, harus diaktifkan secara eksplisit
+	paymentSimulationEnabled, _ := strconv.ParseBool(os.Getenv("PAYMENT_SIMULATION_ENABLED"))
+
+	tenantHeader := os.Getenv("TENANT_HEADER")
+	if tenantHeader == "" {
+		tenantHeader = "X-Tenant"
+	}
+
+	// Deployment satu hotel tetap berjalan tanpa konfigurasi; set DEFAULT_TENANT= (kosong) untuk menonaktifkan
+	defaultTenant, ok := os.LookupEnv("DEFAULT_TENANT")
+	if !ok {
+		defaultTenant = "main"
 	}
 
 	return &Config{
@@ -38,6 +213,54 @@ func LoadConfig() *Config{
 		DBPassword: os.Getenv("DB_PASSWORD"),
 		DBName:     os.Getenv("DB_NAME"),
 		JWTSecret:  os.Getenv("JWT_SECRET_KEY"),
-		JWTExpHours: expHours,
+
+		AccessTokenTTLMinutes: accessTTL,
+		RefreshTokenTTLHours:  refreshTTL,
+
+		PasswordResetTTLMinutes:   passwordResetTTL,
+		EmailVerificationTTLHours: emailVerificationTTL,
+		AppBaseURL:                strings.TrimRight(appBaseURL, "/"),
+
+		MFARequiredRoles:       mfaRequiredRoles,
+		MFAIssuer:              mfaIssuer,
+		MFAEncryptionKey:       mfaEncryptionKey,
+		MFAChallengeTTLMinutes: mfaChallengeTTL,
+
+		MailDriver:    mailDriver,
+		MailFrom:      mailFrom,
+		MailOutboxDir: mailOutboxDir,
+		SMTPHost:      os.Getenv("SMTP_HOST"),
+		SMTPPort:      smtpPort,
+		SMTPUsername:  os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:  os.Getenv("SMTP_PASSWORD"),
+
+		HoldTTLMinutes:           holdTTL,
+		HoldSweepIntervalSeconds: holdSweepInterval,
+
+		PaymentDeadlineMinutes:         paymentDeadline,
+		PaymentDeadlineIntervalSeconds: paymentDeadlineInterval,
+
+		QuoteTTLMinutes: quoteTTL,
+
+		CheckInHour: checkInHour,
+
+		BaseCurrency: baseCurrency,
+
+		PaymentProvider:          paymentProvider,
+		PaymentWebhookSecret:     paymentWebhookSecret,
+		PaymentSimulationEnabled: paymentSimulationEnabled,
+
+		TenantHeader:  tenantHeader,
+		DefaultTenant: defaultTenant,
 	}
-}
\ No newline at end of file
+}
+
+// RequiresMFA mengecek apakah role wajib memakai 2FA (MFA_REQUIRED_ROLES)
+func (c *Config) RequiresMFA(role string) bool {
+	for _, required := range c.MFARequiredRoles {
+		if required == role {
+			return true
+		}
+	}
+	return false
+}
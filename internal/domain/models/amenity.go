@@ -0,0 +1,165 @@
+package models
+
+import (
+	"backend/internal/domain/money"
+	"errors"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// --- Custom Errors Fasilitas ---
+var (
+	ErrAmenityNotFound   = errors.New("fasilitas tidak ditemukan")
+	ErrInvalidPriceRange = errors.New("rentang harga tidak valid")
+)
+
+// Amenity adalah katalog fasilitas/fitur kamar (mis. WIFI, BALCONY, SEA_VIEW, BATHTUB, ACCESSIBLE, SMOKING)
+type Amenity struct {
+	gorm.Model
+	TenantID  uint   `gorm:"not null;uniqueIndex:idx_amenities_tenant_code" json:"-"`
+	Code      string `gorm:"type:varchar(30);not null;uniqueIndex:idx_amenities_tenant_code"` // Dipakai sebagai nilai filter pencarian
+	Name      string `gorm:"type:varchar(100);not null"`
+	Category  string `gorm:"type:varchar(30)"` // Opsional, pengelompokan di sidebar filter (mis. view, bathroom)
+	SortOrder int    `gorm:"default:0"`
+}
+
+// RoomAmenity adalah tabel penghubung kamar fisik dan fasilitas (many-to-many)
+type RoomAmenity struct {
+	RoomID    uint `gorm:"primaryKey"`
+	AmenityID uint `gorm:"primaryKey;index"`
+}
+
+// RoomFilter menyaring pencarian kamar & tipe kamar. Nilai nol berarti tanpa filter.
+type RoomFilter struct {
+	PropertyFilter
+	RoomTypeID uint
+	Amenities  []string    // Kode fasilitas, hasil harus memiliki semuanya
+	MinPrice   money.Money // Harga dasar tipe kamar per malam (mata uang dasar)
+	MaxPrice   money.Money
+	Occupancy  int // Kapasitas minimal tipe kamar
+}
+
+// Validate memastikan rentang harga masuk akal dan dalam mata uang dasar
+func (f RoomFilter) Validate() error {
+	if f.MinPrice.IsNegative() || f.MaxPrice.IsNegative() ||
+		!f.MinPrice.InBaseCurrency() || !f.MaxPrice.InBaseCurrency() ||
+		(f.MaxPrice.IsPositive() && f.MinPrice.Amount > f.MaxPrice.Amount) {
+		return ErrInvalidPriceRange
+	}
+	if f.Occupancy < 0 {
+		return ErrInvalidGuestCount
+	}
+	return nil
+}
+
+// PriceMatches mengecek harga terhadap rentang filter
+func (f RoomFilter) PriceMatches(price money.Money) bool {
+	if f.MinPrice.IsPositive() && price.Amount < f.MinPrice.Amount {
+		return false
+	}
+	return !f.MaxPrice.IsPositive() || price.Amount <= f.MaxPrice.Amount
+}
+
+// HasAmenities mengecek apakah semua fasilitas filter ada di daftar fasilitas
+func (f RoomFilter) HasAmenities(amenities []Amenity) bool {
+	owned := make(map[string]bool, len(amenities))
+	for _, amenity := range amenities {
+		owned[amenity.Code] = true
+	}
+	for _, code := range f.Amenities {
+		if !owned[code] {
+			return false
+		}
+	}
+	return true
+}
+
+// FacetCount adalah jumlah hasil untuk satu nilai filter
+type FacetCount struct {
+	ID    uint   `json:"id"`
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// PriceFacet adalah rentang harga hasil pencarian
+type PriceFacet struct {
+	Min money.Money `json:"min"`
+	Max money.Money `json:"max"`
+}
+
+// SearchFacets adalah jumlah hasil per nilai filter untuk sidebar filter frontend.
+// Setiap facet dihitung dengan semua filter lain diterapkan, kecuali filter dimensinya sendiri
+// untuk tipe kamar & harga agar pilihan lain tetap terlihat.
+type SearchFacets struct {
+	Amenities []FacetCount `json:"amenities"`
+	RoomTypes []FacetCount `json:"room_types"`
+	Price     *PriceFacet  `json:"price"` // nil jika tidak ada hasil
+}
+
+// SearchItem adalah satu hasil pencarian yang dihitung ke dalam facet
+type SearchItem struct {
+	RoomType  RoomType
+	Amenities []Amenity
+}
+
+// BuildFacets menghitung facet dari item yang lolos filter properti & okupansi.
+// Filter tipe kamar, harga, dan fasilitas diterapkan di sini.
+func BuildFacets(items []SearchItem, filter RoomFilter) SearchFacets {
+	var facets SearchFacets
+	amenityCounts := make(map[uint]*FacetCount)
+	typeCounts := make(map[uint]*FacetCount)
+
+	for _, item := range items {
+		typeMatches := filter.RoomTypeID == 0 || item.RoomType.ID == filter.RoomTypeID
+		priceMatches := filter.PriceMatches(item.RoomType.Price)
+		amenityMatches := filter.HasAmenities(item.Amenities)
+
+		if priceMatches && amenityMatches {
+			count, ok := typeCounts[item.RoomType.ID]
+			if !ok {
+				count = &FacetCount{ID: item.RoomType.ID, Code: item.RoomType.Code, Name: item.RoomType.Name}
+				typeCounts[item.RoomType.ID] = count
+			}
+			count.Count++
+		}
+		if typeMatches && amenityMatches {
+			price := item.RoomType.Price
+			if facets.Price == nil {
+				facets.Price = &PriceFacet{Min: price, Max: price}
+			}
+			facets.Price.Min = facets.Price.Min.Min(price)
+			facets.Price.Max = facets.Price.Max.Max(price)
+		}
+		if typeMatches && priceMatches && amenityMatches {
+			for _, amenity := range item.Amenities {
+				count, ok := amenityCounts[amenity.ID]
+				if !ok {
+					count = &FacetCount{ID: amenity.ID, Code: amenity.Code, Name: amenity.Name}
+					amenityCounts[amenity.ID] = count
+				}
+				count.Count++
+			}
+		}
+	}
+
+	facets.RoomTypes = sortedFacets(typeCounts)
+	facets.Amenities = sortedFacets(amenityCounts)
+	return facets
+}
+
+// sortedFacets mengurutkan facet berdasarkan jumlah terbanyak, lalu nama
+func sortedFacets(counts map[uint]*FacetCount) []FacetCount {
+	facets := make([]FacetCount, 0, len(counts))
+	for _, count := range counts {
+		facets = append(facets, *count)
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Name < facets[j].Name
+	})
+	return facets
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// --- Custom Errors Token ---
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token tidak valid atau sudah kedaluwarsa")
+	ErrRefreshTokenReused  = errors.New("refresh token sudah pernah dipakai, seluruh sesi terkait dicabut")
+	ErrTokenRevoked        = errors.New("token sudah dicabut")
+
+	ErrInvalidAccountToken  = errors.New("token tidak valid, sudah dipakai, atau sudah kedaluwarsa")
+	ErrEmailNotVerified     = errors.New("email belum diverifikasi, silakan verifikasi email terlebih dahulu")
+	ErrEmailAlreadyVerified = errors.New("email sudah diverifikasi")
+)
+
+// --- Tujuan Token Akun ---
+const (
+	AccountTokenPasswordReset     = "password_reset"
+	AccountTokenEmailVerification = "email_verification"
+	AccountTokenMFAChallenge      = "mfa_challenge"
+)
+
+// RefreshToken adalah refresh token yang diterbitkan bersama access token. Hanya hash SHA-256-nya
+// yang disimpan. Setiap refresh merotasi token dalam satu FamilyID (satu sesi login); token lama
+// yang dipakai ulang berarti bocor, sehingga seluruh family dicabut.
+type RefreshToken struct {
+	ID        uint       `gorm:"primarykey"`
+	TenantID  uint       `gorm:"not null;index" json:"-"`
+	UserID    uint       `gorm:"not null;index"`
+	FamilyID  string     `gorm:"type:varchar(64);not null;index"`
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex"`
+	AccessJTI string     `gorm:"type:varchar(64);not null;index"` // jti access token yang diterbitkan bersamanya
+	MFA       bool       `gorm:"not null;default:false"`          // Sesi login diselesaikan dengan 2FA, diteruskan saat rotasi
+	ExpiresAt time.Time  `gorm:"not null;index"`
+	RevokedAt *time.Time // Terisi saat dirotasi, logout, atau family dicabut
+	CreatedAt time.Time
+}
+
+// IsActive mengecek apakah token belum dicabut dan belum kedaluwarsa
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// RevokedToken adalah daftar pencabutan access token berdasarkan jti.
+// Baris boleh dihapus setelah ExpiresAt karena token tersebut sudah tidak berlaku.
+type RevokedToken struct {
+	JTI       string    `gorm:"type:varchar(64);primaryKey"`
+	TenantID  uint      `gorm:"not null;index" json:"-"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+// AccountToken adalah token sekali pakai yang dikirim lewat email (reset password, verifikasi email).
+// Seperti refresh token, hanya hash SHA-256-nya yang disimpan.
+type AccountToken struct {
+	ID        uint       `gorm:"primarykey"`
+	TenantID  uint       `gorm:"not null;index" json:"-"`
+	UserID    uint       `gorm:"not null;index"`
+	Purpose   string     `gorm:"type:varchar(30);not null"` // AccountToken*
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"not null;index"`
+	UsedAt    *time.Time // Terisi saat dipakai atau digantikan token baru
+	Attempts  int        `gorm:"not null;default:0"` // Percobaan gagal (MFA challenge)
+	CreatedAt time.Time
+}
+
+// IsUsable mengecek apakah token belum dipakai dan belum kedaluwarsa
+func (t *AccountToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// TokenPair adalah hasil login/refresh yang dikirim ke klien
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// HashToken mengembalikan hash SHA-256 (hex) token acak yang disimpan di database
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
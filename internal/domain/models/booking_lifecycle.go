@@ -0,0 +1,101 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidTransition dikembalikan jika perubahan status tidak diizinkan oleh state machine
+var ErrInvalidTransition = errors.New("perubahan status pemesanan tidak diizinkan")
+
+// bookingTransitions adalah state machine siklus hidup pemesanan:
+//
+//	hold -> pending -> confirmed -> checked_in -> checked_out -> completed
+//	hold -> expired (oleh sweeper)
+//	hold/pending/confirmed -> cancelled
+//	confirmed -> no_show
+//
+// StatusPending dipakai bersama dengan status pembayaran karena nilainya sama.
+var bookingTransitions = map[string][]string{
+	StatusHold:       {StatusPending, StatusExpired, StatusCancelled},
+	StatusPending:    {StatusConfirmed, StatusCancelled},
+	StatusConfirmed:  {StatusCheckedIn, StatusCancelled, StatusNoShow},
+	StatusCheckedIn:  {StatusCheckedOut},
+	StatusCheckedOut: {StatusCompleted},
+}
+
+// ActiveBookingStatuses adalah status yang masih menempati kamar (dipakai untuk cek ketersediaan)
+// Booking hold yang HoldExpiresAt-nya sudah lewat tidak lagi dianggap menempati kamar.
+var ActiveBookingStatuses = []string{StatusHold, StatusPending, StatusConfirmed, StatusCheckedIn}
+
+// BookingTransition mencatat setiap perubahan status pemesanan beserta pelakunya
+type BookingTransition struct {
+	ID          uint      `gorm:"primarykey"`
+	BookingID   uint      `gorm:"not null;index"`
+	FromStatus  string    `gorm:"type:varchar(20);not null"`
+	ToStatus    string    `gorm:"type:varchar(20);not null"`
+	PerformedBy uint      `gorm:"not null"` // UserID admin/front desk/member yang melakukan perubahan
+	Note        string    `gorm:"type:varchar(255)"`
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+// CanTransition mengecek apakah perubahan status from -> to sah
+func CanTransition(from, to string) bool {
+	for _, next := range bookingTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo memvalidasi lalu menerapkan perubahan status pada booking,
+// dan mengembalikan catatan transisi yang harus disimpan.
+func (b *Booking) TransitionTo(to string, performedBy uint, note string, at time.Time) (*BookingTransition, error) {
+	if !CanTransition(b.BookingStatus, to) {
+		return nil, ErrInvalidTransition
+	}
+
+	transition := &BookingTransition{
+		BookingID:   b.ID,
+		FromStatus:  b.BookingStatus,
+		ToStatus:    to,
+		PerformedBy: performedBy,
+		Note:        note,
+		CreatedAt:   at,
+	}
+	b.BookingStatus = to
+	return transition, nil
+}
+
+// ReleasesInventory menandakan status akhir yang membebaskan malam-malam kamar
+func ReleasesInventory(status string) bool {
+	return status == StatusCancelled || status == StatusNoShow || status == StatusExpired
+}
+
+// IsHoldExpired mengecek apakah booking hold sudah melewati batas waktunya
+func (b *Booking) IsHoldExpired(now time.Time) bool {
+	return b.BookingStatus == StatusHold && b.HoldExpiresAt != nil && !now.Before(*b.HoldExpiresAt)
+}
+
+// CanBeReviewed: ulasan hanya boleh dibuat setelah tamu check-out
+func (b *Booking) CanBeReviewed() bool {
+	return b.BookingStatus == StatusCheckedOut || b.BookingStatus == StatusCompleted
+}
+
+// IsPaymentOverdue mengecek apakah booking pending belum dibayar (termasuk yang pembayarannya
+// gagal) melewati batas pembayaran
+func (b *Booking) IsPaymentOverdue(now time.Time) bool {
+	return b.BookingStatus == StatusPending && b.IsUnpaid() &&
+		b.PaymentDueAt != nil && !now.Before(*b.PaymentDueAt)
+}
+
+// IsUnpaid mengecek apakah booking belum menerima dana sama sekali (lihat UnpaidPaymentStatuses)
+func (b *Booking) IsUnpaid() bool {
+	for _, status := range UnpaidPaymentStatuses {
+		if b.PaymentStatus == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"backend/internal/domain/money"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// --- Custom Errors Perubahan Booking ---
+var (
+	ErrNoBookingChanges     = errors.New("tidak ada perubahan pada pemesanan")
+	ErrBookingNotModifiable = errors.New("hanya pemesanan pending/confirmed yang dapat diubah")
+	ErrCheckInDatePassed    = errors.New("tanggal check-in tidak boleh sebelum hari ini")
+	ErrInvalidGuestCount    = errors.New("jumlah tamu minimal 1 orang")
+)
+
+// BookingModification mencatat setiap perubahan tanggal, tipe kamar, atau rombongan tamu sebuah booking
+// beserta selisih harganya. Nominal dalam mata uang tagihan tamu (Booking.Currency).
+type BookingModification struct {
+	ID             uint        `gorm:"primarykey"`
+	BookingID      uint        `gorm:"not null;index"`
+	PerformedBy    uint        `gorm:"not null"`
+	FromRoomTypeID uint        `gorm:"not null"`
+	ToRoomTypeID   uint        `gorm:"not null"`
+	FromRoomID     *uint       `gorm:"index"` // Kamar fisik sebelum/sesudah perubahan, nil jika belum ditetapkan
+	ToRoomID       *uint       `gorm:"index"`
+	FromCheckIn    time.Time   `gorm:"type:date;not null"`
+	FromCheckOut   time.Time   `gorm:"type:date;not null"`
+	ToCheckIn      time.Time   `gorm:"type:date;not null"`
+	ToCheckOut     time.Time   `gorm:"type:date;not null"`
+	FromGuests     int         `gorm:"not null"`
+	ToGuests       int         `gorm:"not null"`
+	FromAdults     int         `gorm:"default:0"`
+	ToAdults       int         `gorm:"default:0"`
+	FromChildAges  string      `gorm:"type:varchar(100)"` // Usia anak dipisah koma, sama seperti Booking.ChildAges
+	ToChildAges    string      `gorm:"type:varchar(100)"`
+	Currency       string      `gorm:"type:varchar(3);not null"`
+	OldTotal       money.Money `gorm:"type:bigint;not null"`
+	NewTotal       money.Money `gorm:"type:bigint;not null"`
+	Difference     money.Money `gorm:"type:bigint;not null"`  // Positif = tambahan tagihan, negatif = pengurangan
+	Refunded       money.Money `gorm:"type:bigint;default:0"` // Refund kelebihan bayar yang langsung diproses
+	CreatedAt      time.Time   `gorm:"not null"`
+}
+
+// AfterFind melabeli nominal dengan mata uang tagihan (kolom uang hanya menyimpan minor unit)
+func (m *BookingModification) AfterFind(tx *gorm.DB) error {
+	m.OldTotal.Currency = m.Currency
+	m.NewTotal.Currency = m.Currency
+	m.Difference.Currency = m.Currency
+	m.Refunded.Currency = m.Currency
+	return nil
+}
+
+// SetToParty mencatat rombongan tamu setelah perubahan
+func (m *BookingModification) SetToParty(party Party) {
+	m.ToAdults = party.Adults
+	m.ToChildAges = party.childAgesString()
+	m.ToGuests = party.Total()
+}
+
+// CanBeModified: tanggal/kamar hanya boleh diubah sebelum tamu check-in
+func (b *Booking) CanBeModified() bool {
+	return b.BookingStatus == StatusPending || b.BookingStatus == StatusConfirmed
+}
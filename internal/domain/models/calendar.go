@@ -0,0 +1,116 @@
+package models
+
+import (
+	"backend/internal/domain/money"
+	"errors"
+	"time"
+)
+
+// MaxCalendarDays adalah rentang terpanjang satu permintaan kalender (sekitar tiga bulan)
+const MaxCalendarDays = 93
+
+var ErrInvalidCalendarRange = errors.New("rentang kalender tidak valid: from harus sebelum to dan maksimal 93 hari")
+
+// DayRestrictions adalah batasan penjualan pada satu tanggal. Batasan masa inap (StayRule) berlaku
+// untuk tamu yang check-in (atau check-out, untuk closed-to-departure) pada tanggal tersebut.
+type DayRestrictions struct {
+	Blackout       bool   `json:"blackout"` // Tanggal blackout rate plan, tidak dapat dijual
+	BlackoutReason string `json:"blackout_reason,omitempty"`
+	StayRule
+}
+
+// CalendarDay adalah ketersediaan & harga satu malam
+type CalendarDay struct {
+	Date         string          `json:"date"`
+	Available    bool            `json:"available"`
+	Price        *money.Money    `json:"price"`     // Harga rate plan malam itu, nil pada tanggal blackout
+	Remaining    int             `json:"remaining"` // Sisa kamar tipe yang sama pada malam itu
+	Restrictions DayRestrictions `json:"restrictions"`
+}
+
+// RoomCalendar adalah kalender ketersediaan kamar fisik untuk rentang from..to (to eksklusif)
+type RoomCalendar struct {
+	RoomID     uint          `json:"room_id"`
+	RoomTypeID uint          `json:"room_type_id"`
+	From       string        `json:"from"`
+	To         string        `json:"to"`
+	Days       []CalendarDay `json:"days"`
+}
+
+// CalendarInput adalah data yang dimuat sekali untuk seluruh rentang kalender
+type CalendarInput struct {
+	Room        *Room
+	RoomType    *RoomType
+	Plan        *RatePlan // nil = tanpa rate plan, harga dari RoomType.Price
+	Sellable    int       // Kamar fisik tipe ini yang bisa dijual
+	Overlapping []Booking // Booking aktif tipe ini yang beririsan dengan rentang kalender
+	Rules       StayRules // Batasan masa inap tipe kamar & kamar ini yang beririsan dengan rentang kalender
+}
+
+// BuildRoomCalendar menghitung ketersediaan setiap malam from..to di memori. Kamar tersedia jika
+// tidak maintenance, belum ditempati booking lain, tipe kamarnya masih tersisa (booking yang belum
+// mendapat kamar fisik tetap mengurangi inventori tipe), dan tanggalnya bukan blackout.
+func BuildRoomCalendar(input CalendarInput, from, to time.Time) (*RoomCalendar, error) {
+	calendar := &RoomCalendar{
+		RoomID:     input.Room.ID,
+		RoomTypeID: input.RoomType.ID,
+		From:       from.Format("2006-01-02"),
+		To:         to.Format("2006-01-02"),
+	}
+
+	stay := Booking{CheckInDate: from, CheckOutDate: to}
+	for _, night := range stay.Nights() {
+		day := CalendarDay{Date: night.Format("2006-01-02")}
+		day.Restrictions.StayRule = input.Rules.On(night)
+
+		if remaining := input.Sellable - bookedOn(input.Overlapping, night); remaining > 0 {
+			day.Remaining = remaining
+		}
+
+		price := input.RoomType.Price
+		if input.Plan != nil {
+			if blackout := input.Plan.BlackoutOn(night); blackout != nil {
+				day.Restrictions.Blackout = true
+				day.Restrictions.BlackoutReason = blackout.Reason
+			} else {
+				var err error
+				if price, err = input.Plan.PriceFor(night, input.RoomType.Price); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if !day.Restrictions.Blackout {
+			day.Price = &price
+		}
+
+		day.Available = input.Room.Status != "maintenance" && day.Remaining > 0 &&
+			!day.Restrictions.Blackout && !roomTakenOn(input.Overlapping, input.Room.ID, night)
+		calendar.Days = append(calendar.Days, day)
+	}
+	return calendar, nil
+}
+
+// bookedOn menghitung booking yang menginap pada malam tersebut
+func bookedOn(bookings []Booking, night time.Time) int {
+	// Dibandingkan per tanggal kalender karena tanggal dari DB & input bisa berbeda zona waktu
+	date := night.Format("2006-01-02")
+	booked := 0
+	for _, booking := range bookings {
+		if booking.CheckInDate.Format("2006-01-02") <= date && date < booking.CheckOutDate.Format("2006-01-02") {
+			booked++
+		}
+	}
+	return booked
+}
+
+// roomTakenOn mengecek apakah kamar fisik sudah ditempati booking pada malam tersebut
+func roomTakenOn(bookings []Booking, roomID uint, night time.Time) bool {
+	date := night.Format("2006-01-02")
+	for _, booking := range bookings {
+		if booking.RoomID != nil && *booking.RoomID == roomID &&
+			booking.CheckInDate.Format("2006-01-02") <= date && date < booking.CheckOutDate.Format("2006-01-02") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"backend/internal/domain/money"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// --- Custom Errors Kebijakan Pembatalan ---
+var (
+	ErrCancellationPolicyNotFound = errors.New("kebijakan pembatalan tidak ditemukan")
+	ErrInvalidCancellationPolicy  = errors.New("kebijakan pembatalan minimal punya 1 aturan dengan jam >= 0 dan penalti 0-100%")
+)
+
+// CancellationPolicy menentukan penalti pembatalan berdasarkan jarak waktu ke check-in.
+// Policy dipasang di rate plan atau kamar (policy rate plan diprioritaskan); tanpa policy pembatalan gratis.
+//
+// Contoh "gratis sampai 48 jam sebelum check-in, 50% setelahnya": [{48, 0}, {0, 50}].
+// Contoh non-refundable: [{0, 100}].
+type CancellationPolicy struct {
+	gorm.Model
+	TenantID    uint               `gorm:"not null;uniqueIndex:idx_cancellation_policies_tenant_code" json:"-"`
+	Code        string             `gorm:"type:varchar(50);not null;uniqueIndex:idx_cancellation_policies_tenant_code"`
+	Name        string             `gorm:"type:varchar(100);not null"`
+	Description string             `gorm:"type:varchar(255)"`
+	Rules       []CancellationRule `gorm:"foreignKey:PolicyID"`
+}
+
+// CancellationRule: pembatalan paling lambat HoursBefore jam sebelum check-in dikenai
+// PenaltyPercent dari total tagihan. Pembatalan setelah waktu check-in selalu 100%.
+type CancellationRule struct {
+	ID             uint    `gorm:"primarykey"`
+	PolicyID       uint    `gorm:"not null;index"`
+	HoursBefore    int     `gorm:"not null"`
+	PenaltyPercent float64 `gorm:"type:decimal(5,2);not null"`
+}
+
+// CancellationBreakdown adalah rincian penalti & refund saat booking dibatalkan
+type CancellationBreakdown struct {
+	PolicyID       *uint       `json:"policy_id"`
+	Terms          string      `json:"terms"` // Snapshot aturan "jam:penalti%", kosong = pembatalan gratis
+	HoursBefore    int         `json:"hours_before_check_in"`
+	PenaltyPercent float64     `json:"penalty_percent"`
+	Total          money.Money `json:"total"`   // Tagihan tamu
+	Paid           money.Money `json:"paid"`    // Pembayaran bersih yang sudah masuk
+	Penalty        money.Money `json:"penalty"` // Penalti pembatalan (dari total tagihan)
+	Refund         money.Money `json:"refund"`  // Dikembalikan ke tamu = dibayar - penalti (minimal 0)
+}
+
+// Validate memeriksa aturan lalu mengurutkannya dari jam terbesar
+func (p *CancellationPolicy) Validate() error {
+	p.Code = strings.ToUpper(strings.TrimSpace(p.Code))
+	if p.Code == "" || p.Name == "" {
+		return errors.New("kode dan nama kebijakan pembatalan wajib diisi")
+	}
+	if len(p.Rules) == 0 {
+		return ErrInvalidCancellationPolicy
+	}
+	for _, rule := range p.Rules {
+		if rule.HoursBefore < 0 || rule.PenaltyPercent < 0 || rule.PenaltyPercent > 100 {
+			return ErrInvalidCancellationPolicy
+		}
+	}
+	sort.Slice(p.Rules, func(i, j int) bool { return p.Rules[i].HoursBefore > p.Rules[j].HoursBefore })
+	return nil
+}
+
+// Terms menyandikan aturan menjadi snapshot ringkas, mis. "48:0,0:50"
+func (p *CancellationPolicy) Terms() string {
+	parts := make([]string, 0, len(p.Rules))
+	for _, rule := range p.Rules {
+		parts = append(parts, fmt.Sprintf("%d:%s", rule.HoursBefore, strconv.FormatFloat(rule.PenaltyPercent, 'f', -1, 64)))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseCancellationTerms membaca snapshot aturan dari Terms
+func ParseCancellationTerms(terms string) ([]CancellationRule, error) {
+	var rules []CancellationRule
+	for _, part := range strings.Split(terms, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		hours, percent, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, ErrInvalidCancellationPolicy
+		}
+		h, err := strconv.Atoi(strings.TrimSpace(hours))
+		if err != nil {
+			return nil, ErrInvalidCancellationPolicy
+		}
+		p, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+		if err != nil {
+			return nil, ErrInvalidCancellationPolicy
+		}
+		rules = append(rules, CancellationRule{HoursBefore: h, PenaltyPercent: p})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].HoursBefore > rules[j].HoursBefore })
+	return rules, nil
+}
+
+// penaltyPercentFor mencari aturan pertama (jam terbesar) yang masih terpenuhi
+func penaltyPercentFor(rules []CancellationRule, hoursBefore float64) float64 {
+	if len(rules) == 0 {
+		return 0 // Tanpa policy: pembatalan gratis
+	}
+	for _, rule := range rules {
+		if hoursBefore >= float64(rule.HoursBefore) {
+			return rule.PenaltyPercent
+		}
+	}
+	return 100
+}
+
+// CancellationBreakdown menghitung penalti & refund jika booking dibatalkan pada now.
+// checkInAt adalah waktu check-in (tanggal check-in + jam check-in hotel).
+func (b *Booking) CancellationBreakdown(checkInAt, now time.Time) (*CancellationBreakdown, error) {
+	rules, err := ParseCancellationTerms(b.CancellationTerms)
+	if err != nil {
+		return nil, err
+	}
+	hoursBefore := checkInAt.Sub(now).Hours()
+	percent := penaltyPercentFor(rules, hoursBefore)
+
+	summary := b.PaymentSummary()
+	zero := money.New(0, b.GuestTotal.Currency)
+	breakdown := &CancellationBreakdown{
+		PolicyID:       b.CancellationPolicyID,
+		Terms:          b.CancellationTerms,
+		HoursBefore:    int(math.Floor(hoursBefore)),
+		PenaltyPercent: percent,
+		Total:          b.GuestTotal,
+		Paid:           summary.Paid.Sub(summary.Refunded),
+		Penalty:        b.GuestTotal.Percent(percent),
+	}
+	breakdown.Refund = breakdown.Paid.Sub(breakdown.Penalty).Max(zero)
+	return breakdown, nil
+}
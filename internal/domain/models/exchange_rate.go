@@ -0,0 +1,29 @@
+package models
+
+import (
+	"backend/internal/domain/money"
+	"errors"
+	"time"
+)
+
+var ErrExchangeRateNotFound = errors.New("kurs mata uang tidak tersedia")
+
+// ExchangeRate adalah kurs offline yang dikelola admin: Rate unit mata uang ini untuk 1 unit
+// mata uang dasar (mis. BASE_CURRENCY=IDR, Currency=USD, Rate=0.0000625).
+type ExchangeRate struct {
+	ID        uint    `gorm:"primarykey"`
+	TenantID  uint    `gorm:"not null;uniqueIndex:idx_exchange_rates_tenant_currency" json:"-"`
+	Currency  string  `gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rates_tenant_currency"`
+	Rate      float64 `gorm:"type:decimal(24,12);not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// GuestPrice adalah nominal yang ditampilkan & ditagihkan ke tamu dalam mata uangnya,
+// beserta snapshot kurs yang dipakai untuk konversi dari mata uang dasar.
+type GuestPrice struct {
+	Currency   string      `json:"currency"`
+	Rate       float64     `json:"rate"`
+	Subtotal   money.Money `json:"subtotal"`
+	GrandTotal money.Money `json:"grand_total"`
+}
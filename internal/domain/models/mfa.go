@@ -0,0 +1,77 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// --- Custom Errors 2FA ---
+var (
+	ErrMFANotEnabled         = errors.New("autentikasi dua faktor belum diaktifkan")
+	ErrMFAAlreadyEnabled     = errors.New("autentikasi dua faktor sudah aktif")
+	ErrMFANotEnrolled        = errors.New("pendaftaran autentikasi dua faktor belum dimulai")
+	ErrInvalidMFACode        = errors.New("kode autentikasi tidak valid")
+	ErrMFARequired           = errors.New("autentikasi dua faktor wajib untuk role ini dan tidak dapat dinonaktifkan")
+	ErrMFAEnrollmentRequired = errors.New("aktifkan autentikasi dua faktor lalu login ulang untuk mengakses halaman ini")
+)
+
+// UserMFA adalah pendaftaran TOTP (RFC 6238) milik satu user. Secret disimpan terenkripsi dan
+// hanya ditampilkan sekali saat pendaftaran; 2FA baru aktif setelah kode pertama diverifikasi.
+type UserMFA struct {
+	ID           uint       `gorm:"primarykey" json:"-"`
+	TenantID     uint       `gorm:"not null;index" json:"-"`
+	UserID       uint       `gorm:"not null;uniqueIndex"`
+	Secret       string     `gorm:"type:varchar(255);not null" json:"-"` // Secret TOTP terenkripsi (AES-GCM)
+	ConfirmedAt  *time.Time // nil = pendaftaran belum diverifikasi
+	LastUsedStep int64      `json:"-"` // Time-step kode TOTP terakhir yang diterima (mencegah replay)
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// IsEnabled mengecek apakah pendaftaran 2FA sudah diverifikasi
+func (m *UserMFA) IsEnabled() bool {
+	return m != nil && m.ConfirmedAt != nil
+}
+
+// MFARecoveryCode adalah kode cadangan sekali pakai bila perangkat authenticator hilang.
+// Seperti token lain, hanya hash SHA-256-nya yang disimpan.
+type MFARecoveryCode struct {
+	ID        uint   `gorm:"primarykey"`
+	TenantID  uint   `gorm:"not null;index" json:"-"`
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"type:char(64);not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MFAEnrollment adalah hasil pendaftaran 2FA untuk dipindai/diketik ke aplikasi authenticator
+type MFAEnrollment struct {
+	Secret     string `json:"secret"`      // Base32, untuk input manual
+	OTPAuthURI string `json:"otpauth_uri"` // otpauth://totp/..., untuk QR code
+}
+
+// MFAStatus adalah ringkasan 2FA user
+type MFAStatus struct {
+	Enabled                bool       `json:"enabled"`
+	ConfirmedAt            *time.Time `json:"confirmed_at"`
+	Required               bool       `json:"required"` // Role user wajib 2FA (MFA_REQUIRED_ROLES)
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// MFAChallenge diterbitkan Login untuk user dengan 2FA aktif, lalu ditukar dengan TokenPair
+// bersama kode TOTP atau recovery code
+type MFAChallenge struct {
+	Token     string    `json:"mfa_token"`
+	ExpiresAt time.Time `json:"mfa_expires_at"`
+}
+
+// LoginResult adalah hasil langkah password: Tokens terisi bila login selesai,
+// Challenge terisi bila masih perlu kode 2FA
+type LoginResult struct {
+	User      *User
+	Tokens    *TokenPair
+	Challenge *MFAChallenge
+	// MFAEnrollmentRequired: role wajib 2FA tetapi user belum mendaftar; token hanya bisa dipakai
+	// di luar area admin (termasuk untuk mendaftar 2FA)
+	MFAEnrollmentRequired bool
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"backend/internal/domain/money"
 	"errors"
 	"time"
 
@@ -12,32 +13,50 @@ import (
 
 type User struct {
 	gorm.Model
-	Username string `gorm:"type:varchar(50);unique;not null"`
-	Password string `gorm:"type:varchar(255);not null"`
-	Email    string `gorm:"type:varchar(100);unique;not null"`
+	TenantID uint   `gorm:"not null;uniqueIndex:idx_users_tenant_username;uniqueIndex:idx_users_tenant_email" json:"-"` // Username & email unik per tenant
+	Username string `gorm:"type:varchar(50);not null;uniqueIndex:idx_users_tenant_username"`
+	Password string `gorm:"type:varchar(255);not null" json:"-"`
+	Email    string `gorm:"type:varchar(100);not null;uniqueIndex:idx_users_tenant_email"`
 	FullName string `gorm:"type:varchar(100);not null"`
-	Role     string `gorm:"type:enum('admin', 'member');default:'member'"`
+	Role     string `gorm:"type:varchar(50);default:'member';index"` // Nama Role: admin, member, atau role staf
+
+	// Waktu email diverifikasi lewat tautan verifikasi; nil = belum (member belum boleh booking)
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+
+	// Properti tempat staf bertugas; nil = staf grup (akses semua properti) atau member
+	PropertyID *uint     `gorm:"index"`
+	Property   *Property `gorm:"foreignKey:PropertyID"`
 
 	// Relasi: User punya banyak Booking
 	Bookings []Booking `gorm:"foreignKey:UserID"`
 }
 
+// Room adalah unit kamar fisik. Harga, kapasitas, dan deskripsi mengikuti RoomType-nya.
 type Room struct {
 	gorm.Model
-	RoomNumber   string  `gorm:"type:varchar(10);unique;not null"`
-	Type         string  `gorm:"type:varchar(50);not null"`
-	Price        float64 `gorm:"type:decimal(10,2);not null"`
-	Description  string  `gorm:"type:text"`
-	Status       string  `gorm:"type:enum('available', 'booked', 'maintenance');default:'available'"`
-	MaxOccupancy int     `gorm:"not null"`
-
-	// Relasi: Room punya banyak Image dan Booking
-	Images   []RoomImage `gorm:"foreignKey:RoomID"`
-	Bookings []Booking   `gorm:"foreignKey:RoomID"`
+	TenantID   uint   `gorm:"not null;index" json:"-"`
+	PropertyID uint   `gorm:"not null;uniqueIndex:idx_rooms_property_number"` // Selalu sama dengan properti tipe kamarnya
+	RoomNumber string `gorm:"type:varchar(10);not null;uniqueIndex:idx_rooms_property_number"`
+	RoomTypeID uint   `gorm:"not null;index"`
+	Status     string `gorm:"type:enum('available', 'booked', 'maintenance');default:'available'"`
+
+	// Lokasi opsional di dalam properti
+	BuildingID *uint `gorm:"index"`
+	Floor      *int
+
+	Property *Property `gorm:"foreignKey:PropertyID"`
+	Building *Building `gorm:"foreignKey:BuildingID"`
+	RoomType *RoomType `gorm:"foreignKey:RoomTypeID"`
+
+	// Relasi: Room punya banyak Image, Booking, dan fasilitas
+	Images    []RoomImage `gorm:"foreignKey:RoomID"`
+	Bookings  []Booking   `gorm:"foreignKey:RoomID"`
+	Amenities []Amenity   `gorm:"many2many:room_amenities"`
 }
 
 type RoomImage struct {
 	gorm.Model
+	TenantID  uint   `gorm:"not null;index" json:"-"`
 	RoomID    uint   `gorm:"not null"` // Foreign Key
 	ImageURL  string `gorm:"type:varchar(255);not null"`
 	IsPrimary bool   `gorm:"default:false"`
@@ -45,21 +64,86 @@ type RoomImage struct {
 
 type Booking struct {
 	gorm.Model
-	UserID        uint      `gorm:"not null"` // Foreign Key ke User
-	RoomID        uint      `gorm:"not null"` // Foreign Key ke Room
-	CheckInDate   time.Time `gorm:"type:date;not null"`
-	CheckOutDate  time.Time `gorm:"type:date;not null"`
-	TotalPrice    float64   `gorm:"type:decimal(10,2);not null"`
-	PaymentMethod string    `gorm:"type:varchar(50)"`
-	PaymentStatus string    `gorm:"type:enum('pending', 'paid', 'failed');default:'pending'"`
-	BookingStatus string    `gorm:"type:enum('confirmed', 'cancelled', 'completed');default:'confirmed'"`
-
-	// Relasi: Booking punya 1 Review
-	Review Review `gorm:"foreignKey:BookingID"`
+	TenantID      uint        `gorm:"not null;index" json:"-"`
+	UserID        uint        `gorm:"not null"`       // Foreign Key ke User
+	PropertyID    uint        `gorm:"not null;index"` // Properti tipe kamar, disimpan agar query admin/laporan tidak perlu join
+	RoomTypeID    uint        `gorm:"not null;index"` // Tipe kamar yang dipesan (inventori)
+	RoomID        *uint       `gorm:"index"`          // Kamar fisik, ditetapkan saat check-in / lewat room board
+	CheckInDate   time.Time   `gorm:"type:date;not null"`
+	CheckOutDate  time.Time   `gorm:"type:date;not null"`
+	Guests        int         `gorm:"not null;default:1"` // Total tamu = Adults + Children
+	Adults        int         `gorm:"not null;default:0"`
+	Children      int         `gorm:"not null;default:0"`
+	ChildAges     string      `gorm:"type:varchar(100)"`     // Usia setiap anak dipisah koma, mis. "4,9"
+	Subtotal      money.Money `gorm:"type:bigint;default:0"` // Jumlah harga & biaya tamu tambahan per malam sebelum diskon/pajak/biaya
+	TotalPrice    money.Money `gorm:"type:bigint;not null"`  // Grand total yang harus dibayar
+	PaymentMethod string      `gorm:"type:varchar(50)"`
+	PaymentStatus string      `gorm:"type:enum('pending', 'partially_paid', 'paid', 'failed', 'refunded');default:'pending'"` // Diturunkan dari ledger Payments
+	BookingStatus string      `gorm:"type:enum('hold', 'pending', 'confirmed', 'checked_in', 'checked_out', 'completed', 'cancelled', 'no_show', 'expired');default:'pending'"`
+	HoldExpiresAt *time.Time  `gorm:"index"` // Hanya terisi untuk booking berstatus hold
+	PaymentDueAt  *time.Time  `gorm:"index"` // Batas pembayaran, lewat dari ini booking pending dibatalkan otomatis
+
+	// Mata uang tamu & snapshot kurs saat booking dibuat (lihat exchange_rate.go).
+	// TotalPrice tetap dalam mata uang dasar; GuestTotal adalah nominal yang ditagihkan ke tamu.
+	Currency     string      `gorm:"type:varchar(3)"`
+	ExchangeRate float64     `gorm:"type:decimal(24,12);default:1"`
+	GuestTotal   money.Money `gorm:"type:bigint;default:0"`
+
+	// Promo yang dipakai (lihat promo_code.go)
+	PromoCodeID    *uint
+	DiscountAmount money.Money `gorm:"type:bigint;default:0"`
+
+	// Snapshot kebijakan pembatalan saat booking dibuat (lihat cancellation_policy.go),
+	// perubahan policy di kemudian hari tidak mengubah booking lama
+	CancellationPolicyID *uint
+	CancellationTerms    string `gorm:"type:varchar(255)"`
+
+	// Relasi: Booking milik 1 User, 1 RoomType, dan (setelah assignment) 1 Room
+	User     *User     `gorm:"foreignKey:UserID"`
+	RoomType *RoomType `gorm:"foreignKey:RoomTypeID"`
+	Room     *Room     `gorm:"foreignKey:RoomID"`
+
+	// Relasi: Booking punya 1 Review, rincian harga per malam, komponen pajak/biaya/diskon,
+	// riwayat perubahan tanggal/kamar, dan riwayat perubahan status
+	Review          Review                  `gorm:"foreignKey:BookingID"`
+	NightPrices     []BookingNightPrice     `gorm:"foreignKey:BookingID"`
+	PriceComponents []BookingPriceComponent `gorm:"foreignKey:BookingID"`
+	Payments        []Payment               `gorm:"foreignKey:BookingID"`
+	Modifications   []BookingModification   `gorm:"foreignKey:BookingID"`
+	Transitions     []BookingTransition     `gorm:"foreignKey:BookingID"`
+}
+
+// AfterFind melabeli GuestTotal dengan mata uang tamu (kolom uang hanya menyimpan minor unit)
+func (b *Booking) AfterFind(tx *gorm.DB) error {
+	if b.Currency != "" {
+		b.GuestTotal.Currency = b.Currency
+	}
+	return nil
+}
+
+// Nights mengembalikan setiap malam menginap (check-in inklusif, check-out eksklusif)
+func (b *Booking) Nights() []time.Time {
+	var nights []time.Time
+	for d := b.CheckInDate; d.Before(b.CheckOutDate); d = d.AddDate(0, 0, 1) {
+		nights = append(nights, d)
+	}
+	return nights
+}
+
+// RoomNight adalah penempatan kamar fisik per malam. Unique index (room_id, date)
+// menjamin satu kamar fisik hanya ditempati satu booking per malam. Hanya diisi
+// untuk booking yang sudah mendapat kamar (lihat RoomType untuk inventori per tipe).
+type RoomNight struct {
+	ID        uint      `gorm:"primarykey"`
+	RoomID    uint      `gorm:"not null;uniqueIndex:idx_room_night"`
+	Date      time.Time `gorm:"type:date;not null;uniqueIndex:idx_room_night"`
+	BookingID uint      `gorm:"not null;index"`
+	CreatedAt time.Time
 }
 
 type Review struct {
 	gorm.Model
+	TenantID  uint   `gorm:"not null;index" json:"-"`
 	BookingID uint   `gorm:"unique;not null"` // Foreign Key ke Booking (Unique)
 	UserID    uint   `gorm:"not null"`        // Untuk kemudahan query
 	Rating    int    `gorm:"type:int;not null;check:rating >= 1 AND rating <= 5"`
@@ -76,34 +160,56 @@ type Pagination struct {
 
 // --- JWT Claims ---
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Role   string `json:"role"`
+	UserID     uint   `json:"user_id"`
+	Role       string `json:"role"`
+	PropertyID uint   `json:"property_id,omitempty"` // Properti staf, 0 = semua properti
+	TenantID   uint   `json:"tenant_id"`             // Tenant tempat user terdaftar; token tidak berlaku di tenant lain
+	MFA        bool   `json:"mfa,omitempty"`         // Login diselesaikan dengan kode 2FA
 	jwt.RegisteredClaims
 }
 
-// --- Konstanta Role ---
+// --- Konstanta Role (role lain dibuat admin, lihat Role) ---
 const (
-	RoleAdmin  = "admin"
-	RoleMember = "member"
+	RoleAdmin        = "admin"
+	RoleMember       = "member"
+	RoleFrontDesk    = "front_desk"
+	RoleHousekeeping = "housekeeping"
+	RoleAccounting   = "accounting"
 )
 
 // --- Status Pembayaran ---
 const (
-	StatusPending = "pending"
-	StatusPaid    = "paid"
-	StatusFailed  = "failed"
+	StatusPending       = "pending"
+	StatusPartiallyPaid = "partially_paid"
+	StatusPaid          = "paid"
+	StatusFailed        = "failed"
+	StatusRefunded      = "refunded"
 )
 
+// UnpaidPaymentStatuses adalah status pembayaran booking yang belum menerima dana sama sekali.
+// Percobaan pembayaran yang gagal tetap dihitung belum dibayar (batas pembayaran tetap berlaku).
+var UnpaidPaymentStatuses = []string{StatusPending, StatusFailed}
+
 // --- Status Pemesanan ---
+// Alur status dan transisi yang sah didefinisikan di booking_lifecycle.go
 const (
-	StatusConfirmed = "confirmed"
-	StatusCancelled = "cancelled"
-	StatusCompleted = "completed"
+	StatusHold       = "hold"
+	StatusExpired    = "expired"
+	StatusConfirmed  = "confirmed"
+	StatusCheckedIn  = "checked_in"
+	StatusCheckedOut = "checked_out"
+	StatusCancelled  = "cancelled"
+	StatusCompleted  = "completed"
+	StatusNoShow     = "no_show"
 )
 
 // --- Custom Errors ---
 var (
 	ErrRecordNotFound     = gorm.ErrRecordNotFound
 	ErrInvalidCredentials = errors.New("username atau password salah")
+	ErrRoomNotFound       = errors.New("kamar tidak ditemukan")
+	ErrRoomAlreadyBooked  = errors.New("kamar sudah dibooking pada periode tersebut")
+	ErrHoldExpired        = errors.New("waktu penahanan kamar sudah habis")
+	ErrBookingForbidden   = errors.New("anda tidak memiliki izin mengubah pemesanan ini")
 	// Tambahkan error lain sesuai kebutuhan (misalnya: errors.New("kamar sudah dibooking"))
 )
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// --- Custom Errors Rombongan Tamu ---
+var (
+	ErrInvalidAdults   = errors.New("jumlah tamu dewasa minimal 1 orang")
+	ErrInvalidChildAge = errors.New("usia anak harus antara 0 dan 17 tahun")
+	ErrTooManyAdults   = errors.New("jumlah tamu dewasa melebihi kapasitas kamar")
+)
+
+// MaxChildAge adalah usia tertinggi yang masih dihitung sebagai anak
+const MaxChildAge = 17
+
+// Party adalah rombongan tamu sebuah pemesanan: jumlah dewasa dan usia setiap anak
+type Party struct {
+	Adults    int   `json:"adults"`
+	ChildAges []int `json:"child_ages"`
+}
+
+// Children mengembalikan jumlah anak dalam rombongan
+func (p Party) Children() int {
+	return len(p.ChildAges)
+}
+
+// Total mengembalikan jumlah seluruh tamu (dewasa + anak)
+func (p Party) Total() int {
+	return p.Adults + len(p.ChildAges)
+}
+
+// IsZero bernilai true jika rombongan belum diisi (dipakai sebagai "tanpa filter" pada pencarian)
+func (p Party) IsZero() bool {
+	return p.Adults == 0 && len(p.ChildAges) == 0
+}
+
+// Validate memastikan ada minimal satu dewasa dan usia anak dalam rentang 0..MaxChildAge
+func (p Party) Validate() error {
+	if p.Adults < 1 {
+		return ErrInvalidAdults
+	}
+	for _, age := range p.ChildAges {
+		if age < 0 || age > MaxChildAge {
+			return ErrInvalidChildAge
+		}
+	}
+	return nil
+}
+
+// Equal membandingkan dua rombongan, termasuk urutan usia anak
+func (p Party) Equal(other Party) bool {
+	return p.Adults == other.Adults && p.childAgesString() == other.childAgesString()
+}
+
+// childAgesString menyimpan usia anak sebagai daftar dipisah koma, mis. "4,9"
+func (p Party) childAgesString() string {
+	ages := make([]string, len(p.ChildAges))
+	for i, age := range p.ChildAges {
+		ages[i] = strconv.Itoa(age)
+	}
+	return strings.Join(ages, ",")
+}
+
+// parseChildAges membaca kolom usia anak; nilai yang tidak valid diabaikan
+func parseChildAges(value string) []int {
+	ages := []int{}
+	for _, part := range strings.Split(value, ",") {
+		age, err := strconv.Atoi(strings.TrimSpace(part))
+		if err == nil {
+			ages = append(ages, age)
+		}
+	}
+	return ages
+}
+
+// Party mengembalikan rombongan tamu booking. Booking lama tanpa rincian dewasa/anak
+// dianggap seluruh tamunya dewasa.
+func (b *Booking) Party() Party {
+	if b.Adults == 0 {
+		return Party{Adults: b.Guests, ChildAges: []int{}}
+	}
+	return Party{Adults: b.Adults, ChildAges: parseChildAges(b.ChildAges)}
+}
+
+// SetParty menyimpan rombongan tamu ke booking; Guests berisi total tamu
+func (b *Booking) SetParty(party Party) {
+	b.Adults = party.Adults
+	b.Children = party.Children()
+	b.ChildAges = party.childAgesString()
+	b.Guests = party.Total()
+}
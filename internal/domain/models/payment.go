@@ -0,0 +1,156 @@
+package models
+
+import (
+	"backend/internal/domain/money"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// --- Custom Errors Pembayaran ---
+var (
+	ErrInvalidWebhookSignature = errors.New("signature webhook pembayaran tidak valid")
+	ErrPaymentNotFound         = errors.New("pembayaran tidak ditemukan")
+	ErrPaymentNotAllowed       = errors.New("pemesanan ini tidak dapat dibayar")
+	ErrPaymentAmountMismatch   = errors.New("nominal pembayaran tidak sesuai dengan tagihan")
+	ErrRefundNotAllowed        = errors.New("pemesanan ini tidak memiliki pembayaran yang dapat direfund")
+	ErrInvalidPaymentAmount    = errors.New("nominal pembayaran harus lebih dari 0 dan tidak melebihi sisa tagihan")
+	ErrPaymentCurrencyMismatch = errors.New("mata uang pembayaran harus sama dengan mata uang tagihan booking")
+	ErrInvalidRefundAmount     = errors.New("nominal refund harus lebih dari 0 dan tidak melebihi total yang sudah dibayar")
+)
+
+// --- Jenis & Status Transaksi Pembayaran ---
+const (
+	PaymentKindPayment = "payment"
+	PaymentKindRefund  = "refund"
+
+	PaymentPending   = "pending"
+	PaymentSucceeded = "succeeded"
+	PaymentFailed    = "failed"
+)
+
+// --- Jenis Event Webhook Pembayaran ---
+const (
+	PaymentEventSucceeded = "payment.succeeded"
+	PaymentEventFailed    = "payment.failed"
+	RefundEventSucceeded  = "refund.succeeded"
+)
+
+// Payment adalah satu transaksi pada ledger pembayaran booking: pembayaran (deposit, pelunasan)
+// atau refund. Nominal dalam mata uang tagihan tamu (Booking.Currency).
+type Payment struct {
+	ID                uint        `gorm:"primarykey"`
+	TenantID          uint        `gorm:"not null;index" json:"-"` // Sama dengan tenant booking
+	BookingID         uint        `gorm:"not null;index"`
+	Kind              string      `gorm:"type:enum('payment', 'refund');default:'payment'"`
+	Amount            money.Money `gorm:"type:bigint;not null"`
+	Currency          string      `gorm:"type:varchar(3);not null"`
+	Method            string      `gorm:"type:varchar(50);not null"` // mock, cash, bank_transfer, dll
+	Provider          string      `gorm:"type:varchar(30)"`          // Kosong untuk pembayaran manual di front desk
+	ProviderReference string      `gorm:"type:varchar(100);index"`   // ID intent/refund di provider
+	Status            string      `gorm:"type:enum('pending', 'succeeded', 'failed');default:'pending'"`
+	RefundOfID        *uint       // Pembayaran asal yang direfund (khusus Kind refund)
+	Note              string      `gorm:"type:varchar(255)"`
+	RecordedBy        uint        // 0 = sistem/provider
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// AfterFind melabeli Amount dengan mata uang transaksi (kolom uang hanya menyimpan minor unit)
+func (p *Payment) AfterFind(tx *gorm.DB) error {
+	p.Amount.Currency = p.Currency
+	return nil
+}
+
+// PaymentSummary adalah ringkasan ledger pembayaran sebuah booking
+type PaymentSummary struct {
+	Total       money.Money `json:"total"`       // Tagihan tamu (GuestTotal)
+	Paid        money.Money `json:"paid"`        // Pembayaran berhasil
+	Refunded    money.Money `json:"refunded"`    // Refund berhasil
+	Outstanding money.Money `json:"outstanding"` // Sisa tagihan
+	Payments    []Payment   `json:"payments"`
+}
+
+// PaymentSummary menghitung total dibayar, direfund, dan sisa tagihan dari Payments
+func (b *Booking) PaymentSummary() PaymentSummary {
+	summary := PaymentSummary{
+		Total:    b.GuestTotal,
+		Paid:     money.New(0, b.GuestTotal.Currency),
+		Refunded: money.New(0, b.GuestTotal.Currency),
+		Payments: b.Payments,
+	}
+	for _, payment := range b.Payments {
+		if payment.Status != PaymentSucceeded {
+			continue
+		}
+		if payment.Kind == PaymentKindRefund {
+			summary.Refunded = summary.Refunded.Add(payment.Amount)
+		} else {
+			summary.Paid = summary.Paid.Add(payment.Amount)
+		}
+	}
+	summary.Outstanding = b.GuestTotal.Sub(summary.Paid).Add(summary.Refunded).Max(money.New(0, b.GuestTotal.Currency))
+	return summary
+}
+
+// RefundableAmount menghitung sisa nominal sebuah pembayaran yang belum direfund
+func (b *Booking) RefundableAmount(payment Payment) money.Money {
+	refundable := payment.Amount
+	for _, refund := range b.Payments {
+		if refund.Kind == PaymentKindRefund && refund.Status != PaymentFailed &&
+			refund.RefundOfID != nil && *refund.RefundOfID == payment.ID {
+			refundable = refundable.Sub(refund.Amount)
+		}
+	}
+	return refundable
+}
+
+// RefreshPaymentStatus menurunkan PaymentStatus booking dari ledger Payments
+func (b *Booking) RefreshPaymentStatus() {
+	summary := b.PaymentSummary()
+	net := summary.Paid.Sub(summary.Refunded)
+	switch {
+	case summary.Paid.IsPositive() && !net.IsPositive():
+		b.PaymentStatus = StatusRefunded
+	case summary.Paid.IsPositive() && !summary.Outstanding.IsPositive():
+		b.PaymentStatus = StatusPaid
+	case net.IsPositive():
+		b.PaymentStatus = StatusPartiallyPaid
+	case hasFailedPayment(b.Payments):
+		b.PaymentStatus = StatusFailed
+	default:
+		b.PaymentStatus = StatusPending
+	}
+}
+
+// AcceptsPayment menandakan booking masih boleh menerima pembayaran
+// (termasuk pelunasan saat check-in/check-out di front desk)
+func (b *Booking) AcceptsPayment() bool {
+	switch b.BookingStatus {
+	case StatusPending, StatusConfirmed, StatusCheckedIn, StatusCheckedOut:
+		return true
+	}
+	return false
+}
+
+func hasFailedPayment(payments []Payment) bool {
+	for _, payment := range payments {
+		if payment.Kind == PaymentKindPayment && payment.Status == PaymentFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentWebhookEvent mencatat event webhook yang sudah diproses agar pengiriman ulang
+// (retry) dari provider tidak diproses dua kali.
+type PaymentWebhookEvent struct {
+	ID          uint      `gorm:"primarykey"`
+	TenantID    uint      `gorm:"not null;index" json:"-"`
+	Provider    string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_provider_event"`
+	EventID     string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_provider_event"`
+	Type        string    `gorm:"type:varchar(50);not null"`
+	BookingID   uint      `gorm:"index"`
+	ProcessedAt time.Time `gorm:"not null"`
+}
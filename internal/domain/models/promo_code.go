@@ -0,0 +1,116 @@
+package models
+
+import (
+	"backend/internal/domain/money"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// --- Custom Errors Promo ---
+var (
+	ErrPromoNotFound     = errors.New("kode promo tidak ditemukan")
+	ErrPromoInactive     = errors.New("kode promo tidak aktif atau di luar masa berlaku")
+	ErrPromoMinNights    = errors.New("jumlah malam tidak memenuhi syarat minimal kode promo")
+	ErrPromoRoomType     = errors.New("kode promo tidak berlaku untuk tipe kamar ini")
+	ErrPromoUsageLimit   = errors.New("kuota kode promo sudah habis")
+	ErrPromoPerUserLimit = errors.New("batas penggunaan kode promo untuk akun anda sudah tercapai")
+)
+
+// --- Tipe Diskon ---
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+type PromoCode struct {
+	gorm.Model
+	TenantID            uint        `gorm:"not null;uniqueIndex:idx_promo_codes_tenant_code" json:"-"`
+	Code                string      `gorm:"type:varchar(50);not null;uniqueIndex:idx_promo_codes_tenant_code"`
+	Description         string      `gorm:"type:varchar(255)"`
+	DiscountType        string      `gorm:"type:enum('percentage', 'fixed');not null"`
+	DiscountBasisPoints int64       `gorm:"not null;default:0"`             // Tipe percentage, 1250 = 12,5%
+	DiscountAmount      money.Money `gorm:"type:bigint;not null;default:0"` // Tipe fixed, mata uang dasar
+	ValidFrom           *time.Time  // Nil = berlaku sejak dibuat
+	ValidUntil          *time.Time  // Nil = tanpa batas akhir
+	MinNights           int         `gorm:"default:0"`
+	PerUserLimit        int         `gorm:"default:0"`         // 0 = tanpa batas
+	UsageLimit          int         `gorm:"default:0"`         // Kuota global, 0 = tanpa batas
+	UsedCount           int         `gorm:"default:0"`         // Dinaikkan setiap redemption
+	RoomTypes           string      `gorm:"type:varchar(255)"` // Daftar RoomType.Code dipisah koma, kosong = semua tipe
+	IsActive            bool        `gorm:"default:true"`
+}
+
+// PromoRedemption mencatat pemakaian kode promo oleh sebuah booking
+type PromoRedemption struct {
+	ID             uint        `gorm:"primarykey"`
+	PromoCodeID    uint        `gorm:"not null;index"`
+	UserID         uint        `gorm:"not null;index"`
+	BookingID      uint        `gorm:"not null;unique"`
+	DiscountAmount money.Money `gorm:"type:bigint;not null"`
+	CreatedAt      time.Time   `gorm:"not null"`
+}
+
+// PromoCodeStats adalah ringkasan pemakaian sebuah kode promo untuk admin
+type PromoCodeStats struct {
+	PromoCodeID   uint        `json:"promo_code_id"`
+	Code          string      `json:"code"`
+	Redemptions   int64       `json:"redemptions"`
+	UniqueUsers   int64       `json:"unique_users"`
+	TotalDiscount money.Money `json:"total_discount"`
+	UsageLimit    int         `json:"usage_limit"`
+	Remaining     int         `json:"remaining"` // -1 = tanpa batas
+}
+
+// NormalizePromoCode menyeragamkan penulisan kode promo (huruf besar, tanpa spasi)
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate mengecek aturan promo yang tidak bergantung pada riwayat pemakaian user
+func (p *PromoCode) Validate(now time.Time, nights int, roomType string) error {
+	if !p.IsActive ||
+		(p.ValidFrom != nil && now.Before(*p.ValidFrom)) ||
+		(p.ValidUntil != nil && now.After(*p.ValidUntil)) {
+		return ErrPromoInactive
+	}
+	if err := p.AppliesTo(nights, roomType); err != nil {
+		return err
+	}
+	if p.UsageLimit > 0 && p.UsedCount >= p.UsageLimit {
+		return ErrPromoUsageLimit
+	}
+	return nil
+}
+
+// AppliesTo mengecek syarat masa inap promo (minimal malam & tipe kamar). Dipakai juga saat
+// booking diubah, karena promo yang sudah diredeem tidak perlu dicek ulang masa berlaku & kuotanya.
+func (p *PromoCode) AppliesTo(nights int, roomType string) error {
+	if nights < p.MinNights {
+		return ErrPromoMinNights
+	}
+	if p.RoomTypes != "" {
+		allowed := false
+		for _, t := range strings.Split(p.RoomTypes, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), roomType) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return ErrPromoRoomType
+		}
+	}
+	return nil
+}
+
+// DiscountFor menghitung potongan untuk subtotal; tidak pernah melebihi subtotal
+func (p *PromoCode) DiscountFor(subtotal money.Money) money.Money {
+	discount := p.DiscountAmount
+	if p.DiscountType == DiscountPercentage {
+		discount = subtotal.BasisPoints(p.DiscountBasisPoints)
+	}
+	return discount.Min(subtotal)
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// --- Custom Errors Properti ---
+var (
+	ErrPropertyNotFound     = errors.New("properti tidak ditemukan")
+	ErrPropertyInUse        = errors.New("properti masih memiliki tipe kamar")
+	ErrBuildingNotFound     = errors.New("gedung tidak ditemukan di properti ini")
+	ErrBuildingInUse        = errors.New("gedung masih memiliki kamar")
+	ErrPropertyAccessDenied = errors.New("anda tidak memiliki akses ke properti ini")
+	ErrPropertyMismatch     = errors.New("tipe kamar berada di properti yang berbeda")
+	ErrInvalidFloor         = errors.New("lantai di luar rentang gedung")
+	ErrStaffNotFound        = errors.New("user tidak ditemukan")
+	ErrUserNotStaff         = errors.New("hanya akun staf yang dapat ditugaskan ke properti")
+)
+
+// Property adalah satu hotel dalam grup. Tipe kamar, kamar fisik, pajak & biaya, booking,
+// dan staf (User.PropertyID) dimiliki oleh sebuah properti; rate plan ikut tipe kamarnya.
+type Property struct {
+	gorm.Model
+	TenantID    uint   `gorm:"not null;uniqueIndex:idx_properties_tenant_code" json:"-"`
+	Code        string `gorm:"type:varchar(50);not null;uniqueIndex:idx_properties_tenant_code"`
+	Name        string `gorm:"type:varchar(100);not null"`
+	City        string `gorm:"type:varchar(100);index"`
+	Address     string `gorm:"type:text"`
+	Description string `gorm:"type:text"`
+	IsActive    bool   `gorm:"default:true"` // Properti nonaktif tidak tampil di endpoint publik
+
+	// Relasi: Property punya banyak gedung (opsional)
+	Buildings []Building `gorm:"foreignKey:PropertyID"`
+}
+
+// Building adalah gedung/sayap opsional di sebuah properti; kamar boleh tanpa gedung
+type Building struct {
+	gorm.Model
+	PropertyID uint   `gorm:"not null;uniqueIndex:idx_buildings_property_code"`
+	Code       string `gorm:"type:varchar(50);not null;uniqueIndex:idx_buildings_property_code"`
+	Name       string `gorm:"type:varchar(100);not null"`
+	Floors     int    `gorm:"default:0"` // Jumlah lantai, 0 = tidak dibatasi
+}
+
+// HasFloor mengecek apakah lantai berada dalam rentang gedung (1..Floors)
+func (b *Building) HasFloor(floor int) bool {
+	return floor >= 1 && (b.Floors == 0 || floor <= b.Floors)
+}
+
+// PropertyFilter membatasi query pada satu properti dan/atau kota.
+// PropertyID 0 dan City kosong = semua properti.
+type PropertyFilter struct {
+	PropertyID uint
+	City       string
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"backend/internal/domain/money"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// --- Custom Errors Quote ---
+var (
+	ErrInvalidQuote  = errors.New("quote token tidak valid atau sudah kedaluwarsa")
+	ErrQuoteMismatch = errors.New("quote token tidak sesuai dengan kamar/tanggal pemesanan")
+	ErrOverOccupancy = errors.New("jumlah tamu melebihi kapasitas kamar")
+)
+
+// PriceLine adalah satu komponen harga (pajak, biaya, atau diskon)
+type PriceLine struct {
+	Code      string      `json:"code"`
+	Name      string      `json:"name"`
+	Amount    money.Money `json:"amount"`
+	Inclusive bool        `json:"inclusive,omitempty"` // Sudah termasuk harga, tidak menambah total
+}
+
+// Quote adalah rincian harga sebuah masa inap sebelum dipesan.
+// Dihitung dengan jalur yang sama dengan CreateBooking.
+type Quote struct {
+	PropertyID   uint                `json:"property_id"`
+	RoomTypeID   uint                `json:"room_type_id"`
+	CheckInDate  string              `json:"check_in_date"`
+	CheckOutDate string              `json:"check_out_date"`
+	Guests       int                 `json:"guests"` // Total tamu
+	Adults       int                 `json:"adults"`
+	ChildAges    []int               `json:"child_ages"`
+	PromoCode    string              `json:"promo_code,omitempty"`
+	Nights       []BookingNightPrice `json:"nights"`
+	Surcharges   []PriceLine         `json:"surcharges"` // Biaya dewasa tambahan & anak untuk seluruh malam, sudah termasuk di Subtotal
+	Subtotal     money.Money         `json:"subtotal"`
+	Taxes        []PriceLine         `json:"taxes"`
+	Fees         []PriceLine         `json:"fees"`
+	Discounts    []PriceLine         `json:"discounts"`
+	GrandTotal   money.Money         `json:"grand_total"`
+
+	// Nominal dalam mata uang tamu, nil jika tamu memakai mata uang dasar
+	Guest *GuestPrice `json:"guest,omitempty"`
+
+	// Terisi hanya pada response endpoint quote
+	Token     string     `json:"token,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Party mengembalikan rombongan tamu quote. Quote token lama tanpa rincian dianggap seluruhnya dewasa.
+func (q *Quote) Party() Party {
+	if q.Adults == 0 {
+		return Party{Adults: q.Guests, ChildAges: []int{}}
+	}
+	return Party{Adults: q.Adults, ChildAges: q.ChildAges}
+}
+
+// Recalculate menghitung ulang GrandTotal dari subtotal dan seluruh komponen harga
+func (q *Quote) Recalculate() {
+	q.GrandTotal = q.Subtotal.Sub(q.TotalDiscount()).Add(exclusiveTotal(q.Taxes)).Add(exclusiveTotal(q.Fees))
+}
+
+// exclusiveTotal menjumlahkan komponen yang belum termasuk di harga kamar
+func exclusiveTotal(lines []PriceLine) money.Money {
+	var total money.Money
+	for _, line := range lines {
+		if !line.Inclusive {
+			total = total.Add(line.Amount)
+		}
+	}
+	return total
+}
+
+// TotalDiscount menjumlahkan seluruh diskon pada quote
+func (q *Quote) TotalDiscount() money.Money {
+	var total money.Money
+	for _, line := range q.Discounts {
+		total = total.Add(line.Amount)
+	}
+	return total
+}
+
+// Components mengubah pajak, biaya, dan diskon quote menjadi snapshot untuk disimpan di booking
+func (q *Quote) Components(at time.Time) []BookingPriceComponent {
+	var components []BookingPriceComponent
+	add := func(kind string, lines []PriceLine) {
+		for _, line := range lines {
+			components = append(components, BookingPriceComponent{
+				Kind:      kind,
+				Code:      line.Code,
+				Name:      line.Name,
+				Amount:    line.Amount,
+				Inclusive: line.Inclusive,
+				CreatedAt: at,
+			})
+		}
+	}
+	add(ComponentDiscount, q.Discounts)
+	add(ComponentTax, q.Taxes)
+	add(ComponentFee, q.Fees)
+	return components
+}
+
+// QuoteClaims adalah payload quote token yang ditandatangani (HS256)
+type QuoteClaims struct {
+	Quote Quote `json:"quote"`
+	jwt.RegisteredClaims
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"backend/internal/domain/money"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// --- Custom Errors Tarif ---
+var (
+	ErrRatePlanNotFound = errors.New("rate plan tidak ditemukan")
+	ErrBlackoutDate     = errors.New("kamar tidak dapat dipesan pada tanggal blackout")
+	ErrInvalidStay      = errors.New("durasi pemesanan minimal 1 malam")
+)
+
+// RatePlan menentukan harga per malam sebuah tipe kamar. Urutan prioritas harga per malam:
+// harga tanggal khusus (RateDatePrice) > harga musim (RateSeason) > RoomType.Price,
+// lalu uplift akhir pekan diterapkan pada harga musim/dasar.
+type RatePlan struct {
+	gorm.Model
+	TenantID             uint    `gorm:"not null;index" json:"-"`
+	RoomTypeID           uint    `gorm:"not null;index"`
+	Name                 string  `gorm:"type:varchar(100);not null"`
+	IsActive             bool    `gorm:"default:true"`
+	Priority             int     `gorm:"default:0"`                      // Plan aktif dengan prioritas tertinggi yang dipakai
+	WeekendDays          string  `gorm:"type:varchar(20);default:'5,6'"` // time.Weekday malam akhir pekan, default Jumat & Sabtu
+	WeekendUpliftPercent float64 `gorm:"type:decimal(5,2);default:0"`
+	CancellationPolicyID *uint   // Opsional: menimpa kebijakan pembatalan tipe kamar
+
+	RoomType           *RoomType           `gorm:"foreignKey:RoomTypeID"`
+	CancellationPolicy *CancellationPolicy `gorm:"foreignKey:CancellationPolicyID"`
+
+	Seasons    []RateSeason    `gorm:"foreignKey:RatePlanID"`
+	DatePrices []RateDatePrice `gorm:"foreignKey:RatePlanID"`
+	Blackouts  []RateBlackout  `gorm:"foreignKey:RatePlanID"`
+}
+
+// RateSeason adalah harga per malam untuk rentang tanggal (StartDate s/d EndDate inklusif)
+type RateSeason struct {
+	ID         uint        `gorm:"primarykey"`
+	RatePlanID uint        `gorm:"not null;index"`
+	Name       string      `gorm:"type:varchar(100);not null"`
+	StartDate  time.Time   `gorm:"type:date;not null"`
+	EndDate    time.Time   `gorm:"type:date;not null"`
+	Price      money.Money `gorm:"type:bigint;not null"`
+}
+
+// RateDatePrice adalah harga khusus untuk satu tanggal
+type RateDatePrice struct {
+	ID         uint        `gorm:"primarykey"`
+	RatePlanID uint        `gorm:"not null;uniqueIndex:idx_rate_plan_date"`
+	Date       time.Time   `gorm:"type:date;not null;uniqueIndex:idx_rate_plan_date"`
+	Price      money.Money `gorm:"type:bigint;not null"`
+}
+
+// RateBlackout adalah rentang tanggal (inklusif) yang tidak dapat dijual
+type RateBlackout struct {
+	ID         uint      `gorm:"primarykey"`
+	RatePlanID uint      `gorm:"not null;index"`
+	StartDate  time.Time `gorm:"type:date;not null"`
+	EndDate    time.Time `gorm:"type:date;not null"`
+	Reason     string    `gorm:"type:varchar(255)"`
+}
+
+// BookingNightPrice adalah snapshot harga per malam saat booking dibuat,
+// sehingga perubahan tarif di kemudian hari tidak mengubah total booking lama.
+type BookingNightPrice struct {
+	ID         uint        `gorm:"primarykey"`
+	BookingID  uint        `gorm:"not null;index"`
+	Date       time.Time   `gorm:"type:date;not null"`
+	Price      money.Money `gorm:"type:bigint;not null"`  // Harga kamar malam itu (rate plan)
+	Surcharge  money.Money `gorm:"type:bigint;default:0"` // Biaya dewasa tambahan & anak malam itu
+	RatePlanID *uint
+}
+
+// inDateRange mengecek start <= date <= end berdasarkan tanggal kalender
+func inDateRange(date, start, end time.Time) bool {
+	d := date.Format("2006-01-02")
+	return d >= start.Format("2006-01-02") && d <= end.Format("2006-01-02")
+}
+
+// isWeekend mengecek apakah malam tersebut termasuk WeekendDays plan
+func (p *RatePlan) isWeekend(night time.Time) bool {
+	for _, day := range strings.Split(p.WeekendDays, ",") {
+		weekday, err := strconv.Atoi(strings.TrimSpace(day))
+		if err == nil && time.Weekday(weekday) == night.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// BlackoutOn mengembalikan blackout yang mencakup malam tersebut, nil jika tidak ada
+func (p *RatePlan) BlackoutOn(night time.Time) *RateBlackout {
+	for i := range p.Blackouts {
+		if inDateRange(night, p.Blackouts[i].StartDate, p.Blackouts[i].EndDate) {
+			return &p.Blackouts[i]
+		}
+	}
+	return nil
+}
+
+// PriceFor menghitung harga satu malam menurut plan ini.
+// basePrice adalah RoomType.Price yang dipakai bila tidak ada harga tanggal/musim.
+func (p *RatePlan) PriceFor(night time.Time, basePrice money.Money) (money.Money, error) {
+	if p.BlackoutOn(night) != nil {
+		return money.Money{}, ErrBlackoutDate
+	}
+
+	// Harga tanggal khusus menggantikan semua aturan lain (termasuk uplift akhir pekan)
+	for _, datePrice := range p.DatePrices {
+		if datePrice.Date.Format("2006-01-02") == night.Format("2006-01-02") {
+			return datePrice.Price, nil
+		}
+	}
+
+	price := basePrice
+	for _, season := range p.Seasons {
+		if inDateRange(night, season.StartDate, season.EndDate) {
+			price = season.Price
+			break
+		}
+	}
+
+	if p.WeekendUpliftPercent != 0 && p.isWeekend(night) {
+		price = price.Add(price.Percent(p.WeekendUpliftPercent))
+	}
+	return price, nil
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// --- Custom Errors Role & Izin ---
+var (
+	ErrRoleNotFound        = errors.New("role tidak ditemukan")
+	ErrRoleNameTaken       = errors.New("nama role sudah dipakai")
+	ErrInvalidRoleName     = errors.New("nama role hanya boleh huruf kecil, angka, dan garis bawah (2-50 karakter)")
+	ErrInvalidPermission   = errors.New("izin tidak dikenal")
+	ErrSystemRole          = errors.New("role bawaan sistem tidak dapat diubah atau dihapus")
+	ErrRoleInUse           = errors.New("role masih dipakai user")
+	ErrCannotChangeOwnRole = errors.New("tidak dapat mengubah role akun sendiri")
+	ErrPermissionDenied    = errors.New("anda tidak memiliki izin untuk aksi ini")
+	ErrPermissionNotHeld   = errors.New("tidak dapat memberikan izin yang tidak anda miliki")
+)
+
+// --- Konstanta Izin (format <resource>.<aksi>) ---
+const (
+	PermPropertyManage           = "property.manage"
+	PermStaffManage              = "staff.manage" // Penugasan properti, role user, reset 2FA
+	PermRoleManage               = "role.manage"
+	PermRoomEdit                 = "room.edit"
+	PermRoomStatus               = "room.status"
+	PermAmenityManage            = "amenity.manage"
+	PermRoomTypeEdit             = "room_type.edit"
+	PermBookingView              = "booking.view"
+	PermBookingManage            = "booking.manage"
+	PermBookingCheckIn           = "booking.checkin"
+	PermPaymentView              = "payment.view"
+	PermPaymentRecord            = "payment.record"
+	PermPaymentRefund            = "payment.refund"
+	PermRateManage               = "rate.manage"
+	PermPromoManage              = "promo.manage"
+	PermTaxFeeManage             = "tax_fee.manage"
+	PermExchangeRateManage       = "exchange_rate.manage"
+	PermCancellationPolicyManage = "cancellation_policy.manage"
+	PermReviewModerate           = "review.moderate"
+)
+
+// PermissionInfo adalah satu izin beserta penjelasannya untuk ditampilkan di panel admin
+type PermissionInfo struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// Permissions adalah katalog semua izin yang dikenal sistem
+var Permissions = []PermissionInfo{
+	{PermPropertyManage, "Mengelola properti & gedung"},
+	{PermStaffManage, "Menugaskan staf ke properti, mengubah role user, dan mereset 2FA"},
+	{PermRoleManage, "Membuat dan mengubah role beserta izinnya"},
+	{PermRoomEdit, "Membuat, mengubah, dan menghapus kamar fisik beserta foto & fasilitasnya"},
+	{PermRoomStatus, "Mengubah status kamar (available/maintenance), mis. housekeeping"},
+	{PermAmenityManage, "Mengelola katalog fasilitas"},
+	{PermRoomTypeEdit, "Mengelola tipe kamar"},
+	{PermBookingView, "Melihat pemesanan, riwayat status, dan room board"},
+	{PermBookingManage, "Mengonfirmasi, menyelesaikan, membatalkan, dan menandai no-show pemesanan"},
+	{PermBookingCheckIn, "Menempatkan kamar, check-in, dan check-out tamu"},
+	{PermPaymentView, "Melihat ledger pembayaran"},
+	{PermPaymentRecord, "Mencatat pembayaran manual dan menagih (capture) pembayaran"},
+	{PermPaymentRefund, "Melakukan refund pembayaran"},
+	{PermRateManage, "Mengelola rate plan dan batasan masa inap"},
+	{PermPromoManage, "Mengelola kode promo"},
+	{PermTaxFeeManage, "Mengelola pajak & biaya"},
+	{PermExchangeRateManage, "Mengelola kurs mata uang"},
+	{PermCancellationPolicyManage, "Mengelola kebijakan pembatalan"},
+	{PermReviewModerate, "Menghapus ulasan"},
+}
+
+// IsValidPermission mengecek apakah kode izin ada di katalog
+func IsValidPermission(code string) bool {
+	for _, permission := range Permissions {
+		if permission.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z0-9_]{2,50}$`)
+
+// Role adalah kumpulan izin yang diberikan ke user lewat User.Role (berisi Name).
+// Role admin selalu memiliki semua izin dan member tidak memiliki izin admin;
+// keduanya role sistem yang tidak bisa diubah.
+type Role struct {
+	gorm.Model
+	TenantID    uint     `gorm:"not null;uniqueIndex:idx_roles_tenant_name" json:"-"`
+	Name        string   `gorm:"type:varchar(50);not null;uniqueIndex:idx_roles_tenant_name"` // Tidak bisa diganti setelah dibuat
+	Description string   `gorm:"type:varchar(255)"`
+	IsSystem    bool     `gorm:"default:false"`
+	Permissions []string `gorm:"-" json:"permissions"` // Disimpan di role_permissions
+}
+
+// RolePermission adalah tabel penghubung role dan kode izin
+type RolePermission struct {
+	RoleID     uint   `gorm:"primaryKey"`
+	Permission string `gorm:"type:varchar(50);primaryKey"`
+}
+
+// Validate memastikan nama role dan semua izinnya valid, lalu merapikan daftar izin (unik & terurut)
+func (r *Role) Validate() error {
+	if !roleNamePattern.MatchString(r.Name) {
+		return ErrInvalidRoleName
+	}
+	seen := make(map[string]bool, len(r.Permissions))
+	permissions := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		if !IsValidPermission(permission) {
+			return ErrInvalidPermission
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+	sort.Strings(permissions)
+	r.Permissions = permissions
+	return nil
+}
+
+// HasPermission mengecek izin role; admin selalu memiliki semua izin
+func (r *Role) HasPermission(permission string) bool {
+	if r.Name == RoleAdmin {
+		return true
+	}
+	for _, granted := range r.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRoles adalah role bawaan yang dibuat untuk setiap tenant. Role staf (front desk,
+// housekeeping, accounting) hanya titik awal dan boleh diubah admin.
+func DefaultRoles() []Role {
+	return []Role{
+		{Name: RoleAdmin, Description: "Administrator, memiliki semua izin", IsSystem: true},
+		{Name: RoleMember, Description: "Tamu terdaftar, tanpa akses admin", IsSystem: true},
+		{Name: RoleFrontDesk, Description: "Resepsionis: pemesanan, check-in/out, dan pencatatan pembayaran", Permissions: []string{
+			PermBookingView, PermBookingManage, PermBookingCheckIn, PermPaymentView, PermPaymentRecord,
+		}},
+		{Name: RoleHousekeeping, Description: "Housekeeping: room board dan status kamar", Permissions: []string{
+			PermBookingView, PermRoomStatus,
+		}},
+		{Name: RoleAccounting, Description: "Keuangan: pembayaran, refund, pajak & biaya, dan kurs", Permissions: []string{
+			PermBookingView, PermPaymentView, PermPaymentRecord, PermPaymentRefund, PermTaxFeeManage, PermExchangeRateManage,
+		}},
+	}
+}
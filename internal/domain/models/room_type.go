@@ -0,0 +1,116 @@
+package models
+
+import (
+	"backend/internal/domain/money"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// --- Custom Errors Tipe Kamar ---
+var (
+	ErrRoomTypeNotFound     = errors.New("tipe kamar tidak ditemukan")
+	ErrRoomTypeInUse        = errors.New("tipe kamar masih memiliki kamar fisik")
+	ErrNoRoomsAvailable     = errors.New("tidak ada kamar tersisa untuk tipe ini pada periode tersebut")
+	ErrRoomTypeMismatch     = errors.New("kamar tidak sesuai dengan tipe kamar pemesanan")
+	ErrRoomUnderMaintenance = errors.New("kamar sedang dalam perbaikan")
+	ErrRoomNotAssignable    = errors.New("kamar hanya dapat ditetapkan untuk pemesanan pending/confirmed/checked_in")
+)
+
+// RoomType adalah produk yang dijual ke tamu (harga, kapasitas, deskripsi).
+// Tamu memesan tipe kamar; inventorinya dihitung dari kamar fisik (Room) bertipe sama,
+// dan kamar fisik baru ditetapkan saat check-in atau melalui room board admin.
+type RoomType struct {
+	gorm.Model
+	TenantID     uint        `gorm:"not null;index" json:"-"`
+	PropertyID   uint        `gorm:"not null;uniqueIndex:idx_room_types_property_code"`
+	Code         string      `gorm:"type:varchar(50);not null;uniqueIndex:idx_room_types_property_code"` // Dipakai juga oleh PromoCode.RoomTypes
+	Name         string      `gorm:"type:varchar(100);not null"`
+	Description  string      `gorm:"type:text"`
+	Price        money.Money `gorm:"type:bigint;not null"` // Harga dasar per malam, minor unit mata uang dasar
+	MaxOccupancy int         `gorm:"not null"`
+
+	// Aturan rombongan & biaya per orang. Harga dasar sudah termasuk BaseOccupancy dewasa.
+	MaxAdults       int         `gorm:"default:0"`             // 0 = hanya dibatasi MaxOccupancy
+	BaseOccupancy   int         `gorm:"default:0"`             // Dewasa yang termasuk harga dasar, 0 = semua dewasa
+	ExtraAdultPrice money.Money `gorm:"type:bigint;default:0"` // Per dewasa di atas BaseOccupancy per malam
+	ChildPrice      money.Money `gorm:"type:bigint;default:0"` // Per anak berbayar per malam
+	FreeChildMaxAge int         `gorm:"default:2"`             // Anak sampai usia ini gratis
+
+	Property *Property `gorm:"foreignKey:PropertyID"`
+
+	// Kebijakan pembatalan default tipe kamar (dapat ditimpa oleh rate plan)
+	CancellationPolicyID *uint
+	CancellationPolicy   *CancellationPolicy `gorm:"foreignKey:CancellationPolicyID"`
+
+	// Harga dalam mata uang tamu (?currency=), hanya terisi di response
+	DisplayPrice *money.Money `gorm:"-"`
+	// Sisa kamar untuk periode yang dicari, hanya terisi di hasil pencarian ketersediaan
+	Remaining *int `gorm:"-"`
+	// Fasilitas yang dimiliki semua kamar fisik yang bisa dijual, hanya terisi di hasil pencarian ketersediaan
+	Amenities []Amenity `gorm:"-"`
+
+	// Relasi: RoomType punya banyak kamar fisik
+	Rooms []Room `gorm:"foreignKey:RoomTypeID"`
+}
+
+// CheckOccupancy memastikan rombongan muat di tipe kamar: total tamu <= MaxOccupancy
+// dan dewasa <= MaxAdults (jika diatur)
+func (rt *RoomType) CheckOccupancy(party Party) error {
+	if party.Total() > rt.MaxOccupancy {
+		return ErrOverOccupancy
+	}
+	if rt.MaxAdults > 0 && party.Adults > rt.MaxAdults {
+		return ErrTooManyAdults
+	}
+	return nil
+}
+
+// ExtraAdults mengembalikan jumlah dewasa yang dikenai biaya tambahan
+func (rt *RoomType) ExtraAdults(party Party) int {
+	if rt.BaseOccupancy == 0 || party.Adults <= rt.BaseOccupancy {
+		return 0
+	}
+	return party.Adults - rt.BaseOccupancy
+}
+
+// PaidChildren mengembalikan jumlah anak yang usianya di atas FreeChildMaxAge
+func (rt *RoomType) PaidChildren(party Party) int {
+	paid := 0
+	for _, age := range party.ChildAges {
+		if age > rt.FreeChildMaxAge {
+			paid++
+		}
+	}
+	return paid
+}
+
+// RemainingRooms menghitung sisa kamar sebuah tipe untuk masa inap checkIn..checkOut:
+// jumlah kamar yang bisa dijual dikurangi malam tersibuk dari booking yang beririsan.
+func RemainingRooms(sellable int, overlapping []Booking, checkIn, checkOut time.Time) int {
+	busiest := 0
+	stay := Booking{CheckInDate: checkIn, CheckOutDate: checkOut}
+	for _, night := range stay.Nights() {
+		if booked := bookedOn(overlapping, night); booked > busiest {
+			busiest = booked
+		}
+	}
+	if remaining := sellable - busiest; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// RoomBoardEntry adalah satu kamar fisik pada room board beserta booking yang menempatinya
+type RoomBoardEntry struct {
+	Room    Room     `json:"room"`
+	Booking *Booking `json:"booking"` // nil jika kamar kosong pada tanggal tersebut
+}
+
+// RoomBoard adalah papan penempatan kamar admin untuk satu tanggal
+type RoomBoard struct {
+	Date       string           `json:"date"`
+	Rooms      []RoomBoardEntry `json:"rooms"`
+	Unassigned []Booking        `json:"unassigned"` // Booking aktif yang belum mendapat kamar fisik
+}
@@ -0,0 +1,241 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// --- Custom Errors Batasan Masa Inap ---
+var (
+	ErrStayRestrictionNotFound = errors.New("batasan masa inap tidak ditemukan")
+	ErrInvalidStayRestriction  = errors.New("data batasan masa inap tidak lengkap atau tidak valid")
+	ErrStayRestricted          = errors.New("masa inap melanggar batasan penjualan")
+)
+
+// --- Kode Pelanggaran Batasan (dikirim ke klien sebagai data.code) ---
+const (
+	RestrictionMinStay           = "MIN_STAY"
+	RestrictionMaxStay           = "MAX_STAY"
+	RestrictionClosedToArrival   = "CLOSED_TO_ARRIVAL"
+	RestrictionClosedToDeparture = "CLOSED_TO_DEPARTURE"
+	RestrictionMinAdvance        = "MIN_ADVANCE"
+	RestrictionMaxAdvance        = "MAX_ADVANCE"
+)
+
+// StayRestriction adalah aturan penjualan untuk rentang tanggal (StartDate s/d EndDate inklusif)
+// pada sebuah tipe kamar atau satu kamar fisik. Nilai nol berarti tanpa batasan.
+// Lama inap & jendela pemesanan dinilai dari tanggal check-in, closed-to-departure dari tanggal check-out.
+type StayRestriction struct {
+	gorm.Model
+	TenantID          uint      `gorm:"not null;index" json:"-"`
+	PropertyID        uint      `gorm:"not null;index"` // Diturunkan dari tipe kamar/kamar, untuk akses staf properti
+	RoomTypeID        *uint     `gorm:"index"`          // Diisi salah satu: RoomTypeID atau RoomID
+	RoomID            *uint     `gorm:"index"`
+	StartDate         time.Time `gorm:"type:date;not null"`
+	EndDate           time.Time `gorm:"type:date;not null"`
+	MinStay           int       `gorm:"default:0"` // Minimal malam (min LOS)
+	MaxStay           int       `gorm:"default:0"` // Maksimal malam (max LOS)
+	ClosedToArrival   bool      `gorm:"default:false"`
+	ClosedToDeparture bool      `gorm:"default:false"`
+	MinAdvanceDays    int       `gorm:"default:0"` // Pemesanan paling lambat N hari sebelum check-in
+	MaxAdvanceDays    int       `gorm:"default:0"` // Pemesanan paling cepat N hari sebelum check-in
+	Note              string    `gorm:"type:varchar(255)"`
+
+	Room *Room `gorm:"foreignKey:RoomID" json:"-"`
+}
+
+// Validate memastikan target, rentang tanggal, dan nilai batasan masuk akal
+func (r *StayRestriction) Validate() error {
+	if (r.RoomTypeID == nil) == (r.RoomID == nil) || r.EndDate.Before(r.StartDate) ||
+		r.MinStay < 0 || r.MaxStay < 0 || (r.MaxStay > 0 && r.MinStay > r.MaxStay) ||
+		r.MinAdvanceDays < 0 || r.MaxAdvanceDays < 0 || (r.MaxAdvanceDays > 0 && r.MinAdvanceDays > r.MaxAdvanceDays) {
+		return ErrInvalidStayRestriction
+	}
+	if r.MinStay == 0 && r.MaxStay == 0 && !r.ClosedToArrival && !r.ClosedToDeparture &&
+		r.MinAdvanceDays == 0 && r.MaxAdvanceDays == 0 {
+		return ErrInvalidStayRestriction
+	}
+	return nil
+}
+
+// TypeID mengembalikan tipe kamar yang terkena batasan (batasan kamar memakai tipe kamarnya saat ini)
+func (r *StayRestriction) TypeID() uint {
+	if r.RoomTypeID != nil {
+		return *r.RoomTypeID
+	}
+	if r.Room != nil {
+		return r.Room.RoomTypeID
+	}
+	return 0
+}
+
+// StayRule adalah gabungan batasan yang berlaku pada satu tanggal (yang paling ketat menang)
+type StayRule struct {
+	MinStay           int  `json:"min_stay,omitempty"`
+	MaxStay           int  `json:"max_stay,omitempty"`
+	ClosedToArrival   bool `json:"closed_to_arrival"`
+	ClosedToDeparture bool `json:"closed_to_departure"`
+	MinAdvanceDays    int  `json:"min_advance_days,omitempty"`
+	MaxAdvanceDays    int  `json:"max_advance_days,omitempty"`
+}
+
+// RestrictionViolation adalah pelanggaran batasan pada sebuah masa inap
+type RestrictionViolation struct {
+	Code  string `json:"code"`
+	Date  string `json:"date"`            // Tanggal yang batasannya dilanggar
+	Limit int    `json:"limit,omitempty"` // Nilai batasan (malam/hari)
+}
+
+func (v *RestrictionViolation) Error() string {
+	switch v.Code {
+	case RestrictionMinStay:
+		return fmt.Sprintf("check-in %s minimal menginap %d malam", v.Date, v.Limit)
+	case RestrictionMaxStay:
+		return fmt.Sprintf("check-in %s maksimal menginap %d malam", v.Date, v.Limit)
+	case RestrictionClosedToArrival:
+		return fmt.Sprintf("tidak menerima check-in pada %s", v.Date)
+	case RestrictionClosedToDeparture:
+		return fmt.Sprintf("tidak menerima check-out pada %s", v.Date)
+	case RestrictionMinAdvance:
+		return fmt.Sprintf("check-in %s harus dipesan minimal %d hari sebelumnya", v.Date, v.Limit)
+	case RestrictionMaxAdvance:
+		return fmt.Sprintf("check-in %s baru dapat dipesan %d hari sebelumnya", v.Date, v.Limit)
+	}
+	return ErrStayRestricted.Error()
+}
+
+// Unwrap agar errors.Is(err, ErrStayRestricted) berlaku untuk semua pelanggaran
+func (v *RestrictionViolation) Unwrap() error {
+	return ErrStayRestricted
+}
+
+// StayRules adalah kumpulan batasan yang dimuat untuk satu tipe kamar (level tipe & level kamar)
+type StayRules []StayRestriction
+
+// GroupStayRules mengelompokkan batasan per tipe kamar
+func GroupStayRules(restrictions []StayRestriction) map[uint]StayRules {
+	grouped := make(map[uint]StayRules)
+	for _, restriction := range restrictions {
+		typeID := restriction.TypeID()
+		grouped[typeID] = append(grouped[typeID], restriction)
+	}
+	return grouped
+}
+
+// ForRoomType mengembalikan batasan level tipe kamar saja
+func (rules StayRules) ForRoomType() StayRules {
+	var result StayRules
+	for _, rule := range rules {
+		if rule.RoomID == nil {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+// ForRoom mengembalikan batasan tipe kamar ditambah batasan milik kamar fisik tersebut
+func (rules StayRules) ForRoom(roomID uint) StayRules {
+	var result StayRules
+	for _, rule := range rules {
+		if rule.RoomID == nil || *rule.RoomID == roomID {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+// On menggabungkan semua batasan yang mencakup tanggal tersebut
+func (rules StayRules) On(date time.Time) StayRule {
+	var merged StayRule
+	for _, rule := range rules {
+		if !inDateRange(date, rule.StartDate, rule.EndDate) {
+			continue
+		}
+		merged.MinStay = max(merged.MinStay, rule.MinStay)
+		merged.MaxStay = minPositive(merged.MaxStay, rule.MaxStay)
+		merged.ClosedToArrival = merged.ClosedToArrival || rule.ClosedToArrival
+		merged.ClosedToDeparture = merged.ClosedToDeparture || rule.ClosedToDeparture
+		merged.MinAdvanceDays = max(merged.MinAdvanceDays, rule.MinAdvanceDays)
+		merged.MaxAdvanceDays = minPositive(merged.MaxAdvanceDays, rule.MaxAdvanceDays)
+	}
+	return merged
+}
+
+// minPositive mengembalikan nilai terkecil dengan 0 berarti tanpa batas
+func minPositive(a, b int) int {
+	if a == 0 {
+		return b
+	}
+	if b == 0 {
+		return a
+	}
+	return min(a, b)
+}
+
+// Check memeriksa masa inap checkIn..checkOut yang dipesan pada now terhadap batasan.
+// Mengembalikan *RestrictionViolation pertama yang dilanggar, atau nil.
+func (rules StayRules) Check(checkIn, checkOut, now time.Time) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	arrivalDate := checkIn.Format("2006-01-02")
+	arrival := rules.On(checkIn)
+	nights := len((&Booking{CheckInDate: checkIn, CheckOutDate: checkOut}).Nights())
+
+	if arrival.ClosedToArrival {
+		return &RestrictionViolation{Code: RestrictionClosedToArrival, Date: arrivalDate}
+	}
+	if arrival.MinStay > 0 && nights < arrival.MinStay {
+		return &RestrictionViolation{Code: RestrictionMinStay, Date: arrivalDate, Limit: arrival.MinStay}
+	}
+	if arrival.MaxStay > 0 && nights > arrival.MaxStay {
+		return &RestrictionViolation{Code: RestrictionMaxStay, Date: arrivalDate, Limit: arrival.MaxStay}
+	}
+
+	// Jarak hari kalender antara tanggal pemesanan dan check-in
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	arrivalDay := time.Date(checkIn.Year(), checkIn.Month(), checkIn.Day(), 0, 0, 0, 0, time.UTC)
+	leadDays := int(arrivalDay.Sub(today).Hours() / 24)
+	if arrival.MinAdvanceDays > 0 && leadDays < arrival.MinAdvanceDays {
+		return &RestrictionViolation{Code: RestrictionMinAdvance, Date: arrivalDate, Limit: arrival.MinAdvanceDays}
+	}
+	if arrival.MaxAdvanceDays > 0 && leadDays > arrival.MaxAdvanceDays {
+		return &RestrictionViolation{Code: RestrictionMaxAdvance, Date: arrivalDate, Limit: arrival.MaxAdvanceDays}
+	}
+
+	if rules.On(checkOut).ClosedToDeparture {
+		return &RestrictionViolation{Code: RestrictionClosedToDeparture, Date: checkOut.Format("2006-01-02")}
+	}
+	return nil
+}
+
+// CheckRooms memeriksa batasan level kamar dan mengembalikan jumlah kamar fisik yang tidak dapat
+// dijual untuk masa inap ini, beserta pelanggaran pertama yang ditemukan
+func (rules StayRules) CheckRooms(checkIn, checkOut, now time.Time) (int, error) {
+	var roomIDs []uint
+	roomRules := make(map[uint]StayRules)
+	for _, rule := range rules {
+		if rule.RoomID == nil {
+			continue
+		}
+		if _, ok := roomRules[*rule.RoomID]; !ok {
+			roomIDs = append(roomIDs, *rule.RoomID)
+		}
+		roomRules[*rule.RoomID] = append(roomRules[*rule.RoomID], rule)
+	}
+
+	restricted := 0
+	var first error
+	for _, roomID := range roomIDs {
+		if err := roomRules[roomID].Check(checkIn, checkOut, now); err != nil {
+			restricted++
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return restricted, first
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"backend/internal/domain/money"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrTaxFeeNotFound = errors.New("pajak/biaya tidak ditemukan")
+
+// --- Jenis Komponen Harga ---
+const (
+	ComponentTax      = "tax"
+	ComponentFee      = "fee"
+	ComponentDiscount = "discount"
+)
+
+// --- Cara Hitung Pajak/Biaya ---
+const (
+	CalcPercentage = "percentage"
+	CalcFlat       = "flat"
+
+	BasisPerNight = "per_night"
+	BasisPerStay  = "per_stay"
+)
+
+// TaxFee adalah konfigurasi pajak (mis. PPN) atau biaya (mis. service charge).
+// Persentase dihitung dari subtotal setelah diskon; flat dikalikan jumlah malam bila per_night.
+// Inclusive berarti sudah termasuk di harga kamar: hanya ditampilkan, tidak menambah total.
+type TaxFee struct {
+	gorm.Model
+	TenantID        uint        `gorm:"not null;index" json:"-"`
+	PropertyID      uint        `gorm:"not null;uniqueIndex:idx_tax_fees_property_code"` // Pajak & biaya berlaku per properti
+	Code            string      `gorm:"type:varchar(30);not null;uniqueIndex:idx_tax_fees_property_code"`
+	Name            string      `gorm:"type:varchar(100);not null"`
+	Kind            string      `gorm:"type:enum('tax', 'fee');not null"`
+	CalcType        string      `gorm:"type:enum('percentage', 'flat');not null"`
+	RateBasisPoints int64       `gorm:"not null;default:0"`             // Percentage, 1100 = 11%
+	Amount          money.Money `gorm:"type:bigint;not null;default:0"` // Flat, mata uang dasar
+	Basis           string      `gorm:"type:enum('per_night', 'per_stay');default:'per_stay'"`
+	Inclusive       bool        `gorm:"default:false"`
+	IsActive        bool        `gorm:"default:true"`
+	SortOrder       int         `gorm:"default:0"`
+}
+
+// BookingPriceComponent adalah snapshot pajak, biaya, dan diskon sebuah booking untuk invoice & laporan
+type BookingPriceComponent struct {
+	ID        uint        `gorm:"primarykey"`
+	BookingID uint        `gorm:"not null;index"`
+	Kind      string      `gorm:"type:enum('tax', 'fee', 'discount');not null"`
+	Code      string      `gorm:"type:varchar(50);not null"`
+	Name      string      `gorm:"type:varchar(100);not null"`
+	Amount    money.Money `gorm:"type:bigint;not null"`
+	Inclusive bool        `gorm:"default:false"`
+	CreatedAt time.Time   `gorm:"not null"`
+}
+
+// AmountFor menghitung nilai pajak/biaya untuk base (subtotal setelah diskon) dan jumlah malam
+func (t *TaxFee) AmountFor(base money.Money, nights int) money.Money {
+	switch {
+	case t.CalcType == CalcPercentage && t.Inclusive:
+		// Porsi pajak yang sudah terkandung di dalam base
+		return base.IncludedBasisPoints(t.RateBasisPoints)
+	case t.CalcType == CalcPercentage:
+		return base.BasisPoints(t.RateBasisPoints)
+	case t.Basis == BasisPerNight:
+		return t.Amount.Mul(nights)
+	default:
+		return t.Amount
+	}
+}
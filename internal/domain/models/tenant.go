@@ -0,0 +1,24 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// --- Custom Errors Tenant ---
+var (
+	ErrTenantNotFound = errors.New("tenant tidak ditemukan")
+	ErrTenantMismatch = errors.New("token tidak berlaku untuk tenant ini")
+)
+
+// Tenant adalah satu perusahaan hotel yang berbagi deployment dengan perusahaan lain.
+// Seluruh data (properti, user, booking, tarif, dst.) terisolasi per tenant lewat kolom tenant_id;
+// request dipetakan ke tenant dari header tenant atau hostname.
+type Tenant struct {
+	gorm.Model
+	Code     string  `gorm:"type:varchar(50);unique;not null"` // Dipakai di header tenant, mis. X-Tenant: acme
+	Name     string  `gorm:"type:varchar(100);not null"`
+	Domain   *string `gorm:"type:varchar(255);unique"` // Hostname tenant, mis. booking.acme-hotels.com
+	IsActive bool    `gorm:"default:true"`
+}
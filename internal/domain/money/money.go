@@ -0,0 +1,343 @@
+// Package money menyediakan tipe nilai uang yang eksak: jumlah disimpan sebagai
+// bilangan bulat dalam satuan terkecil (minor unit, mis. sen) beserta kode mata uangnya,
+// sehingga penjumlahan harga, pajak, diskon, dan pembayaran tidak mengalami drift float.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidAmount    = errors.New("format nominal uang tidak valid")
+	ErrCurrencyMismatch = errors.New("mata uang tidak sama")
+)
+
+// baseCurrency adalah mata uang dasar hotel. Kolom uang di database tidak menyimpan
+// kode mata uang, sehingga nilai hasil Scan selalu memakai mata uang dasar.
+var baseCurrency = "IDR"
+
+// SetBaseCurrency mengatur mata uang dasar (dipanggil sekali saat startup dari config)
+func SetBaseCurrency(code string) {
+	if code = NormalizeCurrency(code); code != "" {
+		baseCurrency = code
+	}
+}
+
+// BaseCurrency mengembalikan mata uang dasar hotel
+func BaseCurrency() string {
+	return baseCurrency
+}
+
+// NormalizeCurrency merapikan kode mata uang ISO 4217 (mis. " usd " -> "USD")
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// zeroDecimalCurrencies adalah mata uang tanpa satuan pecahan (ISO 4217 exponent 0)
+var zeroDecimalCurrencies = map[string]bool{"JPY": true, "KRW": true, "VND": true}
+
+// Exponent mengembalikan jumlah digit desimal minor unit sebuah mata uang
+func Exponent(currency string) int {
+	if zeroDecimalCurrencies[NormalizeCurrency(currency)] {
+		return 0
+	}
+	return 2
+}
+
+// Money adalah nominal uang dalam minor unit. Zero value (Amount 0, Currency "")
+// dianggap nol dalam mata uang apa pun.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// New membuat Money dari minor unit
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: NormalizeCurrency(currency)}
+}
+
+// Zero mengembalikan nol dalam mata uang dasar
+func Zero() Money {
+	return Money{Currency: baseCurrency}
+}
+
+// FromMajor mengonversi nilai konfigurasi (mis. tarif flat dalam rupiah) ke Money,
+// dibulatkan ke minor unit terdekat.
+func FromMajor(value float64, currency string) Money {
+	if currency == "" {
+		currency = baseCurrency
+	}
+	scale := math.Pow10(Exponent(currency))
+	return New(int64(math.Round(value*scale)), currency)
+}
+
+// Parse membaca nominal desimal (mis. "500000", "1250.50") tanpa melewati float
+func Parse(value, currency string) (Money, error) {
+	if currency == "" {
+		currency = baseCurrency
+	}
+	amount, err := parseDecimal(value, Exponent(currency))
+	if err != nil {
+		return Money{}, err
+	}
+	return New(amount, currency), nil
+}
+
+// ParseBasisPoints membaca persen desimal (mis. "11" atau "12.5") menjadi basis poin
+// (1/100 persen, 1250 = 12,5%) tanpa melewati float
+func ParseBasisPoints(value string) (int64, error) {
+	return parseDecimal(value, 2)
+}
+
+// parseDecimal membaca teks desimal menjadi bilangan bulat berskala 10^exp; lebih dari exp
+// digit desimal ditolak agar tidak ada pembulatan diam-diam
+func parseDecimal(value string, exp int) (int64, error) {
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	whole, frac, hasFrac := strings.Cut(value, ".")
+	if whole == "" || (hasFrac && frac == "") || len(frac) > exp {
+		return 0, ErrInvalidAmount
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	amount, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil || strings.ContainsAny(whole+frac, "+-") {
+		return 0, ErrInvalidAmount
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// currencyWith menentukan mata uang hasil operasi dua nilai; nol tanpa mata uang mengikuti pasangannya
+func (m Money) currencyWith(other Money) string {
+	switch {
+	case m.Currency == "":
+		return other.Currency
+	case other.Currency == "" || other.Currency == m.Currency:
+		return m.Currency
+	}
+	// Mencampur mata uang adalah bug pemanggil; konversi harus eksplisit lewat kurs
+	panic(fmt.Errorf("%w: %s dan %s", ErrCurrencyMismatch, m.Currency, other.Currency))
+}
+
+// Add menjumlahkan dua nilai dengan mata uang yang sama
+func (m Money) Add(other Money) Money {
+	return Money{Amount: m.Amount + other.Amount, Currency: m.currencyWith(other)}
+}
+
+// Sub mengurangkan dua nilai dengan mata uang yang sama
+func (m Money) Sub(other Money) Money {
+	return Money{Amount: m.Amount - other.Amount, Currency: m.currencyWith(other)}
+}
+
+// Mul mengalikan nominal dengan bilangan bulat (mis. jumlah malam)
+func (m Money) Mul(n int) Money {
+	return Money{Amount: m.Amount * int64(n), Currency: m.Currency}
+}
+
+// Percent menghitung pct persen dari nominal (pct berpresisi 2 desimal, mis. 11 atau 12.5),
+// dibulatkan half-up ke minor unit.
+func (m Money) Percent(pct float64) Money {
+	return m.BasisPoints(int64(math.Round(pct * 100)))
+}
+
+// BasisPoints menghitung bp basis poin (1250 = 12,5%) dari nominal, dibulatkan half-up ke minor unit.
+func (m Money) BasisPoints(bp int64) Money {
+	return Money{Amount: divRound(m.Amount*bp, 10000), Currency: m.Currency}
+}
+
+// IncludedPercent menghitung porsi pct persen yang sudah terkandung di nominal
+// (mis. PPN 11% di dalam harga 111.000 = 11.000).
+func (m Money) IncludedPercent(pct float64) Money {
+	return m.IncludedBasisPoints(int64(math.Round(pct * 100)))
+}
+
+// IncludedBasisPoints adalah IncludedPercent dengan persen dalam basis poin
+func (m Money) IncludedBasisPoints(bp int64) Money {
+	net := divRound(m.Amount*10000, 10000+bp)
+	return Money{Amount: m.Amount - net, Currency: m.Currency}
+}
+
+// divRound membagi a dengan b (b > 0) dan membulatkan half away from zero
+func divRound(a, b int64) int64 {
+	if a < 0 {
+		return -((-a + b/2) / b)
+	}
+	return (a + b/2) / b
+}
+
+// Convert mengonversi nominal ke mata uang lain dengan kurs rate (unit mata uang tujuan per
+// 1 unit mata uang asal), memperhitungkan perbedaan jumlah desimal kedua mata uang.
+// Hasil dibulatkan half away from zero ke minor unit mata uang tujuan.
+func (m Money) Convert(to string, rate float64) Money {
+	from := m.Currency
+	if from == "" {
+		from = baseCurrency
+	}
+	to = NormalizeCurrency(to)
+
+	value := new(big.Rat).SetInt64(m.Amount)
+	value.Mul(value, new(big.Rat).SetFloat64(rate))
+	shift := Exponent(to) - Exponent(from)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+	if shift >= 0 {
+		value.Mul(value, scale)
+	} else {
+		value.Quo(value, scale)
+	}
+
+	// Pembulatan: (|num| * 2 + den) / (2 * den), lalu kembalikan tandanya
+	num := new(big.Int).Abs(value.Num())
+	den := value.Denom()
+	rounded := new(big.Int).Quo(new(big.Int).Add(new(big.Int).Mul(num, big.NewInt(2)), den), new(big.Int).Mul(den, big.NewInt(2)))
+	if value.Sign() < 0 {
+		rounded.Neg(rounded)
+	}
+	return Money{Amount: rounded.Int64(), Currency: to}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Min mengembalikan nilai terkecil dari dua nominal
+func (m Money) Min(other Money) Money {
+	if other.Amount < m.Amount {
+		return Money{Amount: other.Amount, Currency: m.currencyWith(other)}
+	}
+	return Money{Amount: m.Amount, Currency: m.currencyWith(other)}
+}
+
+// Max mengembalikan nilai terbesar dari dua nominal
+func (m Money) Max(other Money) Money {
+	if other.Amount > m.Amount {
+		return Money{Amount: other.Amount, Currency: m.currencyWith(other)}
+	}
+	return Money{Amount: m.Amount, Currency: m.currencyWith(other)}
+}
+
+// InBaseCurrency mengecek apakah nominal dalam mata uang dasar (satu-satunya mata uang kolom database)
+func (m Money) InBaseCurrency() bool {
+	return m.Currency == "" || m.Currency == baseCurrency
+}
+
+func (m Money) IsZero() bool     { return m.Amount == 0 }
+func (m Money) IsPositive() bool { return m.Amount > 0 }
+func (m Money) IsNegative() bool { return m.Amount < 0 }
+
+// String menampilkan nominal desimal, mis. "500000.00"
+func (m Money) String() string {
+	exp := Exponent(m.Currency)
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if exp == 0 {
+		return sign + strconv.FormatInt(amount, 10)
+	}
+	scale := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/scale, exp, amount%scale)
+}
+
+// jsonMoney adalah bentuk JSON Money: nominal sebagai string desimal agar presisi tidak hilang
+type jsonMoney struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON menghasilkan {"amount":"500000.00","currency":"IDR"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	currency := m.Currency
+	if currency == "" {
+		currency = baseCurrency
+	}
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{Amount: Money{Amount: m.Amount, Currency: currency}.String(), Currency: currency})
+}
+
+// UnmarshalJSON menerima angka (500000.50), string ("500000.50"),
+// atau objek {"amount": ..., "currency": "USD"}. Tanpa currency dianggap mata uang dasar.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	raw := json.RawMessage(data)
+	currency := ""
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		var obj jsonMoney
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return ErrInvalidAmount
+		}
+		raw, currency = obj.Amount, obj.Currency
+	}
+
+	value := strings.Trim(strings.TrimSpace(string(raw)), `"`)
+	if value == "null" || value == "" {
+		*m = Money{}
+		return nil
+	}
+	parsed, err := Parse(value, currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value menyimpan minor unit sebagai BIGINT (mata uang kolom selalu mata uang dasar)
+func (m Money) Value() (driver.Value, error) {
+	return m.Amount, nil
+}
+
+// Scan membaca BIGINT minor unit. Hasil agregasi (SUM) MySQL dikembalikan sebagai DECIMAL/[]byte.
+func (m *Money) Scan(src interface{}) error {
+	var amount int64
+	switch v := src.(type) {
+	case nil:
+		amount = 0
+	case int64:
+		amount = v
+	case []byte:
+		parsed, err := parseMinor(string(v))
+		if err != nil {
+			return err
+		}
+		amount = parsed
+	case string:
+		parsed, err := parseMinor(v)
+		if err != nil {
+			return err
+		}
+		amount = parsed
+	default:
+		return fmt.Errorf("money: tipe kolom %T tidak didukung", src)
+	}
+	*m = Money{Amount: amount, Currency: baseCurrency}
+	return nil
+}
+
+// parseMinor membaca minor unit dari teks database (mis. "125000" atau "125000.0000" hasil SUM)
+func parseMinor(value string) (int64, error) {
+	whole, _, _ := strings.Cut(strings.TrimSpace(value), ".")
+	return strconv.ParseInt(whole, 10, 64)
+}
+
+// GormDataType menentukan tipe kolom default untuk AutoMigrate
+func (Money) GormDataType() string {
+	return "bigint"
+}
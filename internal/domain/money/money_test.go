@@ -0,0 +1,328 @@
+package money_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"backend/internal/domain/money"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		currency string
+		want     money.Money
+		wantErr  bool
+	}{
+		{name: "bilangan bulat", value: "500000", currency: "IDR", want: money.New(50000000, "IDR")},
+		{name: "dua desimal", value: "1250.50", currency: "IDR", want: money.New(125050, "IDR")},
+		{name: "satu desimal dilengkapi nol", value: "1250.5", currency: "usd", want: money.New(125050, "USD")},
+		{name: "spasi dirapikan", value: " 12.30 ", currency: "IDR", want: money.New(1230, "IDR")},
+		{name: "negatif", value: "-0.50", currency: "IDR", want: money.New(-50, "IDR")},
+		{name: "tanpa mata uang memakai mata uang dasar", value: "1.5", want: money.New(150, "IDR")},
+		{name: "mata uang tanpa desimal", value: "1500", currency: "JPY", want: money.New(1500, "JPY")},
+		{name: "nilai maksimum int64", value: "92233720368547758.07", currency: "IDR", want: money.New(9223372036854775807, "IDR")},
+		{name: "melebihi presisi minor unit", value: "1.234", currency: "IDR", wantErr: true},
+		{name: "desimal pada mata uang tanpa desimal", value: "1.5", currency: "JPY", wantErr: true},
+		{name: "titik tanpa pecahan", value: "1.", currency: "IDR", wantErr: true},
+		{name: "pecahan tanpa bilangan bulat", value: ".5", currency: "IDR", wantErr: true},
+		{name: "tanda plus", value: "+5", currency: "IDR", wantErr: true},
+		{name: "tanda minus ganda", value: "--5", currency: "IDR", wantErr: true},
+		{name: "tanda di pecahan", value: "1.-5", currency: "IDR", wantErr: true},
+		{name: "overflow", value: "92233720368547758.08", currency: "IDR", wantErr: true},
+		{name: "notasi eksponen", value: "1e3", currency: "IDR", wantErr: true},
+		{name: "kosong", value: "", currency: "IDR", wantErr: true},
+		{name: "bukan angka", value: "abc", currency: "IDR", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := money.Parse(tt.value, tt.currency)
+			if tt.wantErr {
+				if !errors.Is(err, money.ErrInvalidAmount) {
+					t.Fatalf("Parse(%q) ingin ErrInvalidAmount, dapat %v (%+v)", tt.value, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Fatalf("Parse(%q) = %+v, ingin %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBasisPoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{name: "bilangan bulat", value: "11", want: 1100},
+		{name: "satu desimal", value: "12.5", want: 1250},
+		{name: "dua desimal", value: "0.01", want: 1},
+		{name: "seratus persen", value: "100", want: 10000},
+		{name: "nol", value: "0", want: 0},
+		{name: "lebih dari dua desimal", value: "12.345", wantErr: true},
+		{name: "notasi eksponen", value: "1e2", wantErr: true},
+		{name: "kosong", value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := money.ParseBasisPoints(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, money.ErrInvalidAmount) {
+					t.Fatalf("ParseBasisPoints(%q) ingin ErrInvalidAmount, dapat %v (%d)", tt.value, err, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("ParseBasisPoints(%q) = %d, %v, ingin %d", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBasisPoints(t *testing.T) {
+	tests := []struct {
+		name         string
+		amount       int64
+		basisPoints  int64
+		want         int64
+		wantIncluded int64
+	}{
+		{name: "pajak 11%", amount: 11100000, basisPoints: 1100, want: 1221000, wantIncluded: 1100000},
+		{name: "12,5% dibulatkan", amount: 1001, basisPoints: 1250, want: 125, wantIncluded: 111},
+		{name: "nol basis poin", amount: 12345, basisPoints: 0, want: 0, wantIncluded: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := money.New(tt.amount, "IDR")
+			if got := m.BasisPoints(tt.basisPoints); got.Amount != tt.want {
+				t.Fatalf("%d bp dari %d = %d, ingin %d", tt.basisPoints, tt.amount, got.Amount, tt.want)
+			}
+			if got := m.IncludedBasisPoints(tt.basisPoints); got.Amount != tt.wantIncluded {
+				t.Fatalf("porsi %d bp di dalam %d = %d, ingin %d", tt.basisPoints, tt.amount, got.Amount, tt.wantIncluded)
+			}
+		})
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		pct    float64
+		want   int64
+	}{
+		{name: "pajak 11%", amount: 50000000, pct: 11, want: 5500000},
+		{name: "persen berdesimal", amount: 1000, pct: 12.5, want: 125},
+		{name: "setengah dibulatkan ke atas", amount: 5, pct: 10, want: 1},
+		{name: "di bawah setengah dibulatkan ke bawah", amount: 4, pct: 10, want: 0},
+		{name: "negatif setengah menjauhi nol", amount: -5, pct: 10, want: -1},
+		{name: "negatif 1,5 menjadi -2", amount: -3, pct: 50, want: -2},
+		{name: "nol persen", amount: 12345, pct: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := money.New(tt.amount, "IDR").Percent(tt.pct)
+			if got.Amount != tt.want || got.Currency != "IDR" {
+				t.Fatalf("%d x %v%% = %+v, ingin %d IDR", tt.amount, tt.pct, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncludedPercent(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		pct    float64
+		want   int64
+	}{
+		{name: "PPN 11% di dalam 111.000", amount: 11100000, pct: 11, want: 1100000},
+		{name: "dibulatkan ke minor unit", amount: 100, pct: 11, want: 10},
+		{name: "setengah dibulatkan", amount: 3, pct: 100, want: 1},
+		{name: "negatif", amount: -11100000, pct: 11, want: -1100000},
+		{name: "nol persen", amount: 12345, pct: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := money.New(tt.amount, "IDR").IncludedPercent(tt.pct)
+			if got.Amount != tt.want {
+				t.Fatalf("porsi %v%% dari %d = %d, ingin %d", tt.pct, tt.amount, got.Amount, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name string
+		from money.Money
+		to   string
+		rate float64
+		want money.Money
+	}{
+		{name: "IDR ke JPY menggeser dua desimal", from: money.New(1000000, "IDR"), to: "JPY", rate: 0.0095, want: money.New(95, "JPY")},
+		{name: "JPY ke IDR menggeser dua desimal", from: money.New(100, "JPY"), to: "IDR", rate: 105.26, want: money.New(1052600, "IDR")},
+		{name: "USD ke IDR", from: money.New(1999, "USD"), to: "idr", rate: 15500, want: money.New(30984500, "IDR")},
+		{name: "setengah dibulatkan menjauhi nol", from: money.New(150, "IDR"), to: "JPY", rate: 1, want: money.New(2, "JPY")},
+		{name: "negatif setengah dibulatkan menjauhi nol", from: money.New(-150, "IDR"), to: "JPY", rate: 1, want: money.New(-2, "JPY")},
+		{name: "negatif di bawah setengah", from: money.New(-149, "IDR"), to: "JPY", rate: 1, want: money.New(-1, "JPY")},
+		{name: "tanpa mata uang dianggap mata uang dasar", from: money.Money{Amount: 1000000}, to: "JPY", rate: 0.0095, want: money.New(95, "JPY")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.from.Convert(tt.to, tt.rate); got != tt.want {
+				t.Fatalf("Convert = %+v, ingin %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArithmeticCurrency(t *testing.T) {
+	idr := money.New(500, "IDR")
+	if got := (money.Money{}).Add(idr); got != idr {
+		t.Fatalf("nol tanpa mata uang + %+v = %+v", idr, got)
+	}
+	if got := idr.Sub(money.Money{Amount: 200}); got != money.New(300, "IDR") {
+		t.Fatalf("%+v - 200 = %+v, ingin 300 IDR", idr, got)
+	}
+	if got := idr.Min(money.New(100, "IDR")); got != money.New(100, "IDR") {
+		t.Fatalf("Min = %+v, ingin 100 IDR", got)
+	}
+
+	mixed := []struct {
+		name string
+		op   func()
+	}{
+		{name: "Add", op: func() { idr.Add(money.New(1, "USD")) }},
+		{name: "Sub", op: func() { idr.Sub(money.New(1, "USD")) }},
+		{name: "Min", op: func() { idr.Min(money.New(1, "USD")) }},
+		{name: "Max", op: func() { idr.Max(money.New(1, "USD")) }},
+	}
+	for _, tt := range mixed {
+		t.Run(tt.name+" beda mata uang panic", func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, money.ErrCurrencyMismatch) {
+					t.Fatalf("ingin panic ErrCurrencyMismatch, dapat %v", err)
+				}
+			}()
+			tt.op()
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		value money.Money
+		want  string
+	}{
+		{value: money.New(50000000, "IDR"), want: "500000.00"},
+		{value: money.New(5, "USD"), want: "0.05"},
+		{value: money.New(-50, "IDR"), want: "-0.50"},
+		{value: money.New(1500, "JPY"), want: "1500"},
+	}
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.want {
+			t.Errorf("String(%+v) = %q, ingin %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    int64
+		wantErr bool
+	}{
+		{name: "BIGINT", src: int64(125000), want: 125000},
+		{name: "NULL", src: nil, want: 0},
+		{name: "DECIMAL hasil SUM", src: []byte("125000.0000"), want: 125000},
+		{name: "DECIMAL negatif", src: []byte("-42.0000"), want: -42},
+		{name: "teks", src: "98765", want: 98765},
+		{name: "teks bukan angka", src: []byte("abc"), wantErr: true},
+		{name: "tipe tidak didukung", src: 1.5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got money.Money
+			err := got.Scan(tt.src)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Scan(%v) ingin error, dapat %+v", tt.src, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan(%v): %v", tt.src, err)
+			}
+			if got != money.New(tt.want, money.BaseCurrency()) {
+				t.Fatalf("Scan(%v) = %+v, ingin %d %s", tt.src, got, tt.want, money.BaseCurrency())
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    money.Money
+		wantErr bool
+	}{
+		{name: "angka", data: `500000.50`, want: money.New(50000050, "IDR")},
+		{name: "string", data: `"500000.50"`, want: money.New(50000050, "IDR")},
+		{name: "objek dengan amount string", data: `{"amount":"12.34","currency":"usd"}`, want: money.New(1234, "USD")},
+		{name: "objek dengan amount angka", data: `{"amount":12.34,"currency":"USD"}`, want: money.New(1234, "USD")},
+		{name: "objek tanpa currency", data: `{"amount":"10"}`, want: money.New(1000, "IDR")},
+		{name: "objek mata uang tanpa desimal", data: `{"amount":"1500","currency":"JPY"}`, want: money.New(1500, "JPY")},
+		{name: "null", data: `null`, want: money.Money{}},
+		{name: "string kosong", data: `""`, want: money.Money{}},
+		{name: "presisi berlebih", data: `"1.234"`, wantErr: true},
+		{name: "desimal pada JPY", data: `{"amount":"1.5","currency":"JPY"}`, wantErr: true},
+		{name: "objek rusak", data: `{"amount":`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got money.Money
+			err := got.UnmarshalJSON([]byte(tt.data))
+			if tt.wantErr {
+				if !errors.Is(err, money.ErrInvalidAmount) {
+					t.Fatalf("UnmarshalJSON(%s) ingin ErrInvalidAmount, dapat %v (%+v)", tt.data, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalJSON(%s): %v", tt.data, err)
+			}
+			if got != tt.want {
+				t.Fatalf("UnmarshalJSON(%s) = %+v, ingin %+v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestJSONRoundTrip memastikan hasil MarshalJSON dapat dibaca ulang tanpa kehilangan presisi
+func TestJSONRoundTrip(t *testing.T) {
+	for _, value := range []money.Money{money.New(9223372036854775807, "IDR"), money.New(-1, "USD"), money.New(1500, "JPY")} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got money.Money
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if got != value {
+			t.Fatalf("round trip %s = %+v, ingin %+v", data, got, value)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"time"
 )
 
 type RoomRepository interface {
@@ -9,11 +11,67 @@ type RoomRepository interface {
 	Update(room *models.Room) error
 	Delete(id uint) error
 	FindByID(id uint) (*models.Room, error)
+	// LockByID mengambil kamar dengan SELECT ... FOR UPDATE (harus dipanggil di dalam transaksi)
+	LockByID(id uint) (*models.Room, error)
 
 	// Show & Search
-	FindAll(pagination *models.Pagination) ([]models.Room, error)
-	// Fungsi untuk Filter Ketersediaan Real-time
-	FindAvailable(checkInDate, checkOutDate string, pagination *models.Pagination) ([]models.Room, error)
+	FindAll(filter models.RoomFilter, pagination *models.Pagination) ([]models.Room, error)
+	// Facets menghitung jumlah kamar per fasilitas, tipe kamar, dan rentang harga untuk filter
+	Facets(filter models.RoomFilter) (models.SearchFacets, error)
+	// SetAmenities mengganti seluruh fasilitas kamar
+	SetAmenities(roomID uint, amenityIDs []uint) error
+
+	// Inventori per tipe kamar
+	CountByRoomType(roomTypeID uint) (int64, error)
+	CountByBuilding(buildingID uint) (int64, error)
+	CountSellable(roomTypeID uint) (int64, error) // Kamar selain maintenance
+	// FindFreeForStay mencari kamar fisik bertipe roomTypeID yang kosong sepanjang masa inap
+	FindFreeForStay(roomTypeID uint, checkInDate, checkOutDate string, excludeBookingID uint) ([]models.Room, error)
+}
+
+type RoomTypeRepository interface {
+	Create(roomType *models.RoomType) error
+	Update(roomType *models.RoomType) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.RoomType, error) // Preload CancellationPolicy
+	// LockByID mengambil tipe kamar dengan SELECT ... FOR UPDATE (harus dipanggil di dalam transaksi)
+	LockByID(id uint) (*models.RoomType, error)
+	// FindAll & FindAvailable hanya mengembalikan tipe kamar milik properti aktif, Property terisi
+	FindAll(filter models.PropertyFilter, pagination *models.Pagination) ([]models.RoomType, error)
+	// FindAvailable mengembalikan tipe kamar yang masih tersisa pada periode tersebut, Remaining & Amenities
+	// terisi, dapat menampung party (party kosong = tanpa filter kapasitas), beserta facet seluruh hasil.
+	// now adalah waktu clock service: hold yang kedaluwarsa pada now tidak lagi mengurangi sisa kamar.
+	FindAvailable(checkInDate, checkOutDate string, filter models.RoomFilter, party models.Party, pagination *models.Pagination, now time.Time) ([]models.RoomType, models.SearchFacets, error)
+	CountByProperty(propertyID uint) (int64, error)
+}
+
+type AmenityRepository interface {
+	Create(amenity *models.Amenity) error
+	Update(amenity *models.Amenity) error
+	Delete(id uint) error // Sekaligus melepas fasilitas dari semua kamar
+	FindByID(id uint) (*models.Amenity, error)
+	FindByIDs(ids []uint) ([]models.Amenity, error)
+	FindAll(pagination *models.Pagination) ([]models.Amenity, error)
+}
+
+type PropertyRepository interface {
+	Create(property *models.Property) error
+	Update(property *models.Property) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.Property, error)                                                     // Preload Buildings
+	FindAll(city string, activeOnly bool, pagination *models.Pagination) ([]models.Property, error) // city kosong = semua kota
+
+	// Gedung
+	CreateBuilding(building *models.Building) error
+	UpdateBuilding(building *models.Building) error
+	DeleteBuilding(propertyID, buildingID uint) error
+	FindBuilding(propertyID, buildingID uint) (*models.Building, error)
+}
+
+// TenantRepository tidak ter-scope tenant; dipakai untuk memetakan request ke tenant
+type TenantRepository interface {
+	FindByCode(code string) (*models.Tenant, error)
+	FindByDomain(domain string) (*models.Tenant, error)
 }
 
 type UserRepository interface {
@@ -22,23 +80,99 @@ type UserRepository interface {
 	Delete(id uint) error
 	FindByID(id uint) (*models.User, error)
 	FindByUsername(username string) (*models.User, error)
+	FindByEmail(email string) (*models.User, error)
 	// Tambahan untuk Admin
 	FindAllMembers(pagination *models.Pagination) ([]models.User, error)
 }
 
+type AuthTokenRepository interface {
+	CreateRefreshToken(token *models.RefreshToken) error
+	FindRefreshTokenByHash(hash string) (*models.RefreshToken, error)
+	FindRefreshTokenByAccessJTI(jti string) (*models.RefreshToken, error) // Sesi dari access token yang sedang dipakai
+	// RevokeRefreshToken mencabut token dengan update bersyarat; false jika token sudah dicabut
+	// lebih dulu (dipakai ulang atau dirotasi paralel)
+	RevokeRefreshToken(id uint, at time.Time) (bool, error)
+	// RevokeFamily mencabut semua refresh token satu sesi login dan mengembalikan jti access token-nya
+	RevokeFamily(familyID string, at time.Time) ([]string, error)
+	RevokeAccessTokens(tokens []models.RevokedToken) error // jti yang sudah ada diabaikan
+	IsAccessTokenRevoked(jti string) (bool, error)
+	// RevokeUserSessions mencabut semua refresh token milik user dan mengembalikan jti access token-nya
+	RevokeUserSessions(userID uint, at time.Time) ([]string, error)
+
+	// Token akun sekali pakai (reset password, verifikasi email)
+	CreateAccountToken(token *models.AccountToken) error
+	FindAccountToken(purpose, hash string) (*models.AccountToken, error)
+	// UseAccountToken menandai token terpakai dengan update bersyarat; false jika sudah dipakai lebih dulu
+	UseAccountToken(id uint, at time.Time) (bool, error)
+	// InvalidateAccountTokens menandai token user dengan tujuan tersebut yang belum terpakai sebagai terpakai
+	InvalidateAccountTokens(userID uint, purpose string, at time.Time) error
+	// FailAccountToken mencatat percobaan gagal; token ditandai terpakai setelah maxAttempts kali gagal
+	FailAccountToken(id uint, maxAttempts int, at time.Time) error
+
+	DeleteExpired(before time.Time) error // Membersihkan refresh token, daftar pencabutan & token akun yang sudah kedaluwarsa
+}
+
+type RoleRepository interface {
+	Create(role *models.Role) error // Menyimpan role beserta izinnya
+	Update(role *models.Role) error // Mengganti deskripsi & seluruh izin role
+	Delete(id uint) error
+	FindByID(id uint) (*models.Role, error)
+	FindByName(name string) (*models.Role, error)
+	FindAll() ([]models.Role, error)
+	CountUsers(name string) (int64, error) // Jumlah user yang memakai role
+}
+
+type MFARepository interface {
+	FindByUserID(userID uint) (*models.UserMFA, error)
+	Save(mfa *models.UserMFA) error
+	// Enable menandai pendaftaran terverifikasi dan mengganti seluruh recovery code dalam satu transaksi
+	Enable(mfa *models.UserMFA, codes []models.MFARecoveryCode) error
+	ReplaceRecoveryCodes(userID uint, codes []models.MFARecoveryCode) error
+	// MarkStepUsed menyimpan time-step TOTP dengan update bersyarat; false jika step tersebut
+	// (atau yang lebih baru) sudah pernah dipakai
+	MarkStepUsed(userID uint, step int64) (bool, error)
+	// UseRecoveryCode menandai recovery code terpakai; false jika tidak ada atau sudah dipakai
+	UseRecoveryCode(userID uint, hash string, at time.Time) (bool, error)
+	CountRecoveryCodes(userID uint) (int64, error) // Recovery code yang belum terpakai
+	Delete(userID uint) error                      // Menghapus pendaftaran beserta recovery code
+}
+
 type BookingRepository interface {
 	Create(booking *models.Booking) error
 	Update(booking *models.Booking) error
 	Delete(id uint) error
 	FindByID(id uint) (*models.Booking, error)
+	// LockByID mengambil booking dengan SELECT ... FOR UPDATE (harus dipanggil di dalam transaksi)
+	LockByID(id uint) (*models.Booking, error)
 
 	// Fungsi Member dan Admin
 	FindByUserID(userID uint, pagination *models.Pagination) ([]models.Booking, error)
-	FindAll(pagination *models.Pagination) ([]models.Booking, error) // Untuk Admin melihat semua
+	FindAll(propertyID uint, pagination *models.Pagination) ([]models.Booking, error) // Untuk Admin melihat semua, propertyID 0 = semua properti
 
 	// Fungsi Logika Bisnis
-	UpdateStatus(id uint, newStatus string) error                             // Mengubah booking/payment status oleh Admin
-	CheckOverlap(roomID uint, checkInDate, checkOutDate string) (bool, error) // Pencegahan Double Booking
+	UpdateStatus(id uint, newStatus string) error                                                                   // Mengubah booking/payment status oleh Admin
+	CheckOverlap(roomID uint, checkInDate, checkOutDate string, excludeBookingID uint, now time.Time) (bool, error) // Pencegahan Double Booking, excludeBookingID 0 = tanpa pengecualian
+
+	// Inventori per tipe kamar (lihat models/room_type.go). Di sini dan di CheckOverlap, now adalah waktu
+	// clock service: hold yang kedaluwarsa pada now diabaikan walaupun belum dibersihkan sweeper.
+	FindOverlappingByRoomType(roomTypeID uint, checkInDate, checkOutDate string, excludeBookingID uint, now time.Time) ([]models.Booking, error)
+	FindActiveOn(propertyID uint, date string, now time.Time) ([]models.Booking, error) // Booking aktif yang menginap pada malam tersebut (room board)
+
+	// Siklus hidup status (lihat models/booking_lifecycle.go)
+	TransitionStatus(id uint, fromStatus, toStatus string) error // Update bersyarat, ErrInvalidTransition jika status sudah berubah
+	CreateTransition(transition *models.BookingTransition) error
+	FindTransitions(bookingID uint) ([]models.BookingTransition, error)
+	FindExpiredHolds(now time.Time, roomTypeID uint, limit int) ([]models.Booking, error) // roomTypeID 0 = semua tipe kamar
+	FindOverduePending(now time.Time, limit int) ([]models.Booking, error)                // Pending & belum dibayar (pending/failed) melewati PaymentDueAt
+
+	// Penempatan kamar fisik per malam (tabel room_nights), hanya untuk booking yang sudah mendapat kamar
+	ReserveNights(booking *models.Booking) error // Mengembalikan ErrRoomAlreadyBooked jika ada malam yang sudah terisi
+	ReleaseNights(bookingID uint) error
+
+	// Perubahan booking (lihat models/booking_modification.go)
+	ReplacePriceDetails(booking *models.Booking) error // Mengganti NightPrices & PriceComponents dengan milik booking
+	CreateModification(modification *models.BookingModification) error
+	FindModifications(bookingID uint) ([]models.BookingModification, error)
 }
 
 type RoomImageRepository interface {
@@ -60,3 +194,108 @@ type ReviewRepository interface {
 	// Tambahan untuk tampilan kamar
 	FindByRoomID(roomID uint, pagination *models.Pagination) ([]models.Review, error)
 }
+
+type RatePlanRepository interface {
+	Create(plan *models.RatePlan) error
+	Update(plan *models.RatePlan) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.RatePlan, error)                                                    // Preload Seasons, DatePrices, Blackouts
+	FindAll(roomTypeID, propertyID uint, pagination *models.Pagination) ([]models.RatePlan, error) // 0 = tanpa filter
+	// FindActiveByRoomTypeID mengembalikan plan aktif dengan prioritas tertinggi, ErrRecordNotFound jika tidak ada
+	FindActiveByRoomTypeID(roomTypeID uint) (*models.RatePlan, error)
+
+	// Komponen Rate Plan
+	CreateSeason(season *models.RateSeason) error
+	DeleteSeason(ratePlanID, seasonID uint) error
+	UpsertDatePrice(datePrice *models.RateDatePrice) error
+	DeleteDatePrice(ratePlanID, datePriceID uint) error
+	CreateBlackout(blackout *models.RateBlackout) error
+	DeleteBlackout(ratePlanID, blackoutID uint) error
+}
+
+type StayRestrictionRepository interface {
+	Create(restriction *models.StayRestriction) error
+	Update(restriction *models.StayRestriction) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.StayRestriction, error)
+	FindAll(roomTypeID, roomID, propertyID uint, pagination *models.Pagination) ([]models.StayRestriction, error) // 0 = tanpa filter
+	// FindForStay mengembalikan batasan level tipe kamar dan level kamar (kamar yang bisa dijual) untuk
+	// tipe-tipe tersebut yang rentangnya beririsan dengan from..to inklusif; Room terisi untuk batasan kamar
+	FindForStay(roomTypeIDs []uint, from, to string) ([]models.StayRestriction, error)
+}
+
+type PromoCodeRepository interface {
+	Create(promo *models.PromoCode) error
+	Update(promo *models.PromoCode) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.PromoCode, error)
+	FindByCode(code string) (*models.PromoCode, error)
+	FindAll(pagination *models.Pagination) ([]models.PromoCode, error)
+	// LockByCode mengambil promo dengan SELECT ... FOR UPDATE (harus dipanggil di dalam transaksi)
+	LockByCode(code string) (*models.PromoCode, error)
+
+	// Redemption
+	CountUserRedemptions(promoCodeID, userID uint) (int64, error)
+	CreateRedemption(redemption *models.PromoRedemption) error // Sekaligus menaikkan UsedCount
+	ReleaseRedemption(bookingID uint) error                    // Menghapus redemption booking & menurunkan UsedCount
+	UpdateRedemptionDiscount(bookingID uint, discount money.Money) error
+	GetStats(promoCodeID uint) (*models.PromoCodeStats, error)
+}
+
+type TaxFeeRepository interface {
+	Create(taxFee *models.TaxFee) error
+	Update(taxFee *models.TaxFee) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.TaxFee, error)
+	FindAll(propertyID uint, pagination *models.Pagination) ([]models.TaxFee, error) // propertyID 0 = semua properti
+	FindActive(propertyID uint) ([]models.TaxFee, error)                             // Urut berdasarkan SortOrder
+}
+
+type ExchangeRateRepository interface {
+	Upsert(rate *models.ExchangeRate) error // Membuat atau menimpa kurs mata uang yang sama
+	Delete(currency string) error
+	FindByCurrency(currency string) (*models.ExchangeRate, error)
+	FindAll() ([]models.ExchangeRate, error)
+}
+
+type CancellationPolicyRepository interface {
+	Create(policy *models.CancellationPolicy) error
+	Update(policy *models.CancellationPolicy) error // Aturan lama diganti seluruhnya dengan policy.Rules
+	Delete(id uint) error
+	FindByID(id uint) (*models.CancellationPolicy, error) // Preload Rules
+	FindAll(pagination *models.Pagination) ([]models.CancellationPolicy, error)
+}
+
+type PaymentRepository interface {
+	Create(payment *models.Payment) error
+	Update(payment *models.Payment) error
+	FindByID(id uint) (*models.Payment, error)
+	FindByReference(provider, reference string) (*models.Payment, error) // ID intent/refund di provider
+	FindByBookingID(bookingID uint) ([]models.Payment, error)            // Urut dari yang paling lama
+}
+
+type PaymentWebhookEventRepository interface {
+	// Record mencatat event; mengembalikan false jika event sudah pernah diproses
+	Record(event *models.PaymentWebhookEvent) (bool, error)
+}
+
+// TxRepositories berisi repository yang terikat pada satu transaksi database
+type TxRepositories struct {
+	Rooms        RoomRepository
+	RoomTypes    RoomTypeRepository
+	Bookings     BookingRepository
+	RatePlans    RatePlanRepository
+	Restrictions StayRestrictionRepository
+	Promos       PromoCodeRepository
+	TaxFees      TaxFeeRepository
+	Rates        ExchangeRateRepository
+	Webhooks     PaymentWebhookEventRepository
+	Payments     PaymentRepository
+	Policies     CancellationPolicyRepository
+}
+
+// Transactor menjalankan fn di dalam satu transaksi. Jika fn mengembalikan error,
+// seluruh perubahan di-rollback.
+type Transactor interface {
+	WithinTransaction(fn func(tx TxRepositories) error) error
+}
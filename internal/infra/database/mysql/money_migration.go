@@ -0,0 +1,144 @@
+package mysql
+
+import (
+	"backend/internal/domain/money"
+	"fmt"
+	"log"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+// moneyColumns adalah kolom uang yang dulu bertipe DECIMAL(10,2) (nominal mayor)
+// dan kini BIGINT (minor unit mata uang dasar, lihat internal/domain/money).
+var moneyColumns = []struct{ Table, Column string }{
+	{"rooms", "price"},
+	{"bookings", "subtotal"},
+	{"bookings", "total_price"},
+	{"bookings", "discount_amount"},
+	{"rate_seasons", "price"},
+	{"rate_date_prices", "price"},
+	{"booking_night_prices", "price"},
+	{"promo_redemptions", "discount_amount"},
+	{"booking_price_components", "amount"},
+	{"tax_fees", "amount"}, // Nilai persen dipindah ke rate_basis_points terlebih dahulu
+}
+
+// MigrateMoneyColumns mengonversi data lama dari DECIMAL ke BIGINT minor unit.
+// Wajib dijalankan sebelum AutoMigrate. Nilai ditulis ke kolom sementara <kolom>_minor lalu
+// kolom lama diganti dalam satu ALTER, sehingga aman dijalankan ulang bila proses sempat terhenti.
+func MigrateMoneyColumns(db *gorm.DB) {
+	scale := int64(math.Pow10(money.Exponent(money.BaseCurrency())))
+
+	if err := migrateTaxFeeRates(db); err != nil {
+		log.Fatalf("gagal migrasi persentase pajak/biaya: %v", err)
+	}
+	if err := migratePromoDiscounts(db, scale); err != nil {
+		log.Fatalf("gagal migrasi nilai diskon promo: %v", err)
+	}
+	for _, col := range moneyColumns {
+		if err := migrateMoneyColumn(db, col.Table, col.Column, scale); err != nil {
+			log.Fatalf("gagal migrasi kolom uang %s.%s: %v", col.Table, col.Column, err)
+		}
+	}
+}
+
+func migrateMoneyColumn(db *gorm.DB, table, column string, scale int64) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(table) || !migrator.HasColumn(table, column) {
+		return nil // Tabel/kolom baru, dibuat langsung sebagai BIGINT oleh AutoMigrate
+	}
+
+	if decimal, err := isDecimalColumn(db, table, column); err != nil || !decimal {
+		return err // Sudah dimigrasi
+	}
+
+	tmp := column + "_minor"
+	if !migrator.HasColumn(table, tmp) {
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` BIGINT NOT NULL DEFAULT 0", table, tmp)).Error; err != nil {
+			return err
+		}
+	}
+	if err := db.Exec(fmt.Sprintf("UPDATE `%s` SET `%s` = ROUND(`%s` * ?)", table, tmp, column), scale).Error; err != nil {
+		return err
+	}
+	if err := db.Exec(fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN `%s`, RENAME COLUMN `%s` TO `%s`", table, column, tmp, column)).Error; err != nil {
+		return err
+	}
+
+	log.Printf("Kolom uang %s.%s dimigrasi ke minor unit.", table, column)
+	return nil
+}
+
+// isDecimalColumn mengecek apakah kolom masih bertipe DECIMAL (belum dimigrasi)
+func isDecimalColumn(db *gorm.DB, table, column string) (bool, error) {
+	columnTypes, err := db.Migrator().ColumnTypes(table)
+	if err != nil {
+		return false, err
+	}
+	for _, columnType := range columnTypes {
+		if columnType.Name() == column {
+			return columnType.DatabaseTypeName() == "decimal", nil
+		}
+	}
+	return false, nil
+}
+
+// migrateTaxFeeRates memindahkan persen pajak/biaya lama dari tax_fees.amount ke rate_basis_points
+// (basis poin). amount baris persentase dinolkan di statement yang sama sehingga aman diulang;
+// nominal flat yang tersisa dikonversi ke minor unit oleh migrateMoneyColumn.
+func migrateTaxFeeRates(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable("tax_fees") || !migrator.HasColumn("tax_fees", "amount") {
+		return nil
+	}
+	if decimal, err := isDecimalColumn(db, "tax_fees", "amount"); err != nil || !decimal {
+		return err
+	}
+
+	if !migrator.HasColumn("tax_fees", "rate_basis_points") {
+		if err := db.Exec("ALTER TABLE `tax_fees` ADD COLUMN `rate_basis_points` BIGINT NOT NULL DEFAULT 0").Error; err != nil {
+			return err
+		}
+	}
+	return db.Exec("UPDATE `tax_fees` SET `rate_basis_points` = ROUND(`amount` * 100), `amount` = 0 WHERE `calc_type` = 'percentage' AND `amount` <> 0").Error
+}
+
+// migratePromoDiscounts memecah promo_codes.discount_value lama (persen atau nominal mayor) ke
+// discount_basis_points dan discount_amount (minor unit), lalu menghapus kolom lama
+func migratePromoDiscounts(db *gorm.DB, scale int64) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable("promo_codes") || !migrator.HasColumn("promo_codes", "discount_value") {
+		return nil
+	}
+
+	for _, column := range []string{"discount_basis_points", "discount_amount"} {
+		if !migrator.HasColumn("promo_codes", column) {
+			if err := db.Exec(fmt.Sprintf("ALTER TABLE `promo_codes` ADD COLUMN `%s` BIGINT NOT NULL DEFAULT 0", column)).Error; err != nil {
+				return err
+			}
+		}
+	}
+	err := db.Exec("UPDATE `promo_codes` SET "+
+		"`discount_basis_points` = IF(`discount_type` = 'percentage', ROUND(`discount_value` * 100), 0), "+
+		"`discount_amount` = IF(`discount_type` = 'fixed', ROUND(`discount_value` * ?), 0)", scale).Error
+	if err != nil {
+		return err
+	}
+	if err := db.Exec("ALTER TABLE `promo_codes` DROP COLUMN `discount_value`").Error; err != nil {
+		return err
+	}
+
+	log.Printf("Nilai diskon promo dimigrasi ke basis poin & minor unit.")
+	return nil
+}
+
+// BackfillBookingCurrency mengisi mata uang tagihan booking lama (dibuat sebelum multi-currency)
+// dengan mata uang dasar. Dijalankan setelah AutoMigrate menambahkan kolomnya.
+func BackfillBookingCurrency(db *gorm.DB) {
+	err := db.Exec("UPDATE bookings SET currency = ?, exchange_rate = 1, guest_total = total_price WHERE currency IS NULL OR currency = ''",
+		money.BaseCurrency()).Error
+	if err != nil {
+		log.Fatalf("gagal mengisi mata uang booking lama: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package mysql_test
+
+import (
+	"testing"
+
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/internal/infra/database/mysql"
+	"backend/internal/infra/database/mysql/mysqltest"
+)
+
+// TestMigrateMoneyColumnsSplitsRates memastikan nilai DECIMAL lama promo & pajak/biaya dipecah ke
+// basis poin (persen) dan minor unit (nominal), serta aman bila migrasi dijalankan ulang
+func TestMigrateMoneyColumnsSplitsRates(t *testing.T) {
+	db := mysqltest.Open(t)
+
+	// Bentuk kolom sebelum money.Money & basis poin
+	for _, stmt := range []string{
+		"ALTER TABLE promo_codes DROP COLUMN discount_basis_points, DROP COLUMN discount_amount, ADD COLUMN discount_value DECIMAL(10,2) NOT NULL",
+		"ALTER TABLE tax_fees DROP COLUMN rate_basis_points, MODIFY COLUMN amount DECIMAL(10,2) NOT NULL",
+		`INSERT INTO promo_codes (created_at, updated_at, tenant_id, code, discount_type, discount_value) VALUES
+			(NOW(), NOW(), 1, 'PCT', 'percentage', 12.5),
+			(NOW(), NOW(), 1, 'FIX', 'fixed', 50000.75)`,
+		`INSERT INTO tax_fees (created_at, updated_at, tenant_id, property_id, code, name, kind, calc_type, amount) VALUES
+			(NOW(), NOW(), 1, 1, 'PPN', 'PPN 11%', 'tax', 'percentage', 11),
+			(NOW(), NOW(), 1, 1, 'CITY', 'City Tax', 'fee', 'flat', 20000.50)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("gagal menyiapkan skema lama: %v", err)
+		}
+	}
+
+	mysql.MigrateMoneyColumns(db)
+	mysql.MigrateMoneyColumns(db) // Dijalankan ulang tidak boleh mengubah hasil
+	if err := db.AutoMigrate(&models.PromoCode{}, &models.TaxFee{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var promos []models.PromoCode
+	if err := db.Order("code").Find(&promos).Error; err != nil {
+		t.Fatal(err)
+	}
+	var taxFees []models.TaxFee
+	if err := db.Order("code").Find(&taxFees).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(promos) != 2 || len(taxFees) != 2 {
+		t.Fatalf("ingin 2 promo & 2 pajak/biaya, dapat %d & %d", len(promos), len(taxFees))
+	}
+
+	tests := []struct {
+		name        string
+		basisPoints int64
+		amount      money.Money
+		wantPoints  int64
+		wantAmount  int64
+	}{
+		{name: "promo fixed", basisPoints: promos[0].DiscountBasisPoints, amount: promos[0].DiscountAmount, wantAmount: 5000075},
+		{name: "promo percentage", basisPoints: promos[1].DiscountBasisPoints, amount: promos[1].DiscountAmount, wantPoints: 1250},
+		{name: "biaya flat", basisPoints: taxFees[0].RateBasisPoints, amount: taxFees[0].Amount, wantAmount: 2000050},
+		{name: "pajak percentage", basisPoints: taxFees[1].RateBasisPoints, amount: taxFees[1].Amount, wantPoints: 1100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.basisPoints != tt.wantPoints || tt.amount.Amount != tt.wantAmount {
+				t.Fatalf("ingin %d bp & %d minor unit, dapat %d bp & %d", tt.wantPoints, tt.wantAmount, tt.basisPoints, tt.amount.Amount)
+			}
+		})
+	}
+}
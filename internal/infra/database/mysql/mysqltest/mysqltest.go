@@ -0,0 +1,106 @@
+// Package mysqltest menyiapkan database MySQL sementara untuk test integrasi.
+//
+// Test yang memakai Open dilewati (skip) jika TEST_MYSQL_DSN tidak diisi, mis.:
+//
+//	TEST_MYSQL_DSN="root:secret@tcp(127.0.0.1:3306)/?parseTime=True&loc=Local" go test ./...
+//
+// User pada DSN harus boleh membuat dan menghapus database; setiap pemanggilan Open membuat
+// database baru sehingga test dapat berjalan paralel tanpa saling mengganggu.
+package mysqltest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"backend/internal/domain/models"
+	"backend/internal/infra/database/mysql"
+	"backend/internal/infra/gorm/repositories"
+
+	driver "github.com/go-sql-driver/mysql"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// DSNEnv adalah environment variable berisi DSN server MySQL untuk test
+const DSNEnv = "TEST_MYSQL_DSN"
+
+// Open membuat database kosong berisi semua tabel aplikasi dengan tenant scope terpasang,
+// lalu menghapusnya saat test selesai. Koneksi yang dikembalikan belum terikat tenant.
+func Open(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv(DSNEnv)
+	if dsn == "" {
+		t.Skipf("%s tidak diisi, test integrasi MySQL dilewati", DSNEnv)
+	}
+	cfg, err := driver.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("%s tidak valid: %v", DSNEnv, err)
+	}
+	cfg.ParseTime = true
+
+	admin, err := gorm.Open(gormmysql.Open(cfg.FormatDSN()), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gagal terhubung ke MySQL test: %v", err)
+	}
+	name := "myhotel_test_" + randomSuffix(t)
+	if err := admin.Exec("CREATE DATABASE " + name).Error; err != nil {
+		t.Fatalf("gagal membuat database test: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP DATABASE IF EXISTS " + name)
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	cfg.DBName = name
+	db, err := gorm.Open(gormmysql.Open(cfg.FormatDSN()), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gagal membuka database test: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if err := repositories.RegisterTenantScope(db); err != nil {
+		t.Fatalf("gagal memasang tenant scope: %v", err)
+	}
+	if err := db.AutoMigrate(mysql.Models()...); err != nil {
+		t.Fatalf("gagal migrasi database test: %v", err)
+	}
+	return db
+}
+
+// CreateTenant membuat tenant aktif dan mengembalikan ID-nya
+func CreateTenant(t testing.TB, db *gorm.DB, code string) uint {
+	t.Helper()
+
+	tenant := &models.Tenant{Code: code, Name: code, IsActive: true}
+	if err := db.Create(tenant).Error; err != nil {
+		t.Fatalf("gagal membuat tenant %s: %v", code, err)
+	}
+	return tenant.ID
+}
+
+// Create menyimpan record lewat db (biasanya koneksi repositories.WithTenant) atau menggagalkan test
+func Create(t testing.TB, db *gorm.DB, value interface{}) {
+	t.Helper()
+
+	if err := db.Create(value).Error; err != nil {
+		t.Fatalf("gagal menyimpan %T: %v", value, err)
+	}
+}
+
+func randomSuffix(t testing.TB) string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("gagal membuat nama database test: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,21 @@
+package mysql
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// BackfillBookingParty mengisi rincian rombongan booking lama (dibuat sebelum ada dewasa/anak):
+// seluruh tamu dianggap dewasa. Dijalankan setelah AutoMigrate menambahkan kolomnya.
+func BackfillBookingParty(db *gorm.DB) {
+	statements := []string{
+		"UPDATE bookings SET adults = guests, children = 0 WHERE adults = 0",
+		"UPDATE booking_modifications SET from_adults = from_guests, to_adults = to_guests WHERE from_adults = 0",
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			log.Fatalf("gagal mengisi rombongan tamu booking lama: %v", err)
+		}
+	}
+}
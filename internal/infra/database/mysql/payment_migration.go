@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"backend/internal/domain/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BackfillPaymentLedger membuat entri ledger untuk booking lama yang status pembayarannya
+// dulu diubah langsung (kolom payment_status/payment_reference), sebelum ada tabel payments.
+// Idempoten: booking yang sudah punya entri ledger dilewati.
+func BackfillPaymentLedger(db *gorm.DB) {
+	reference := "''"
+	if db.Migrator().HasColumn("bookings", "payment_reference") {
+		reference = "COALESCE(b.payment_reference, '')"
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Booking lunas/terefund dianggap dibayar penuh sebesar tagihan tamu
+		if err := tx.Exec(`INSERT INTO payments (tenant_id, booking_id, kind, amount, currency, method, provider, provider_reference, status, note, recorded_by, created_at, updated_at)
+			SELECT b.tenant_id, b.id, 'payment', b.guest_total, b.currency, COALESCE(NULLIF(b.payment_method, ''), 'manual'),
+				CASE WHEN ` + reference + ` <> '' THEN b.payment_method ELSE '' END, ` + reference + `,
+				'succeeded', 'migrasi status pembayaran lama', 0, b.updated_at, b.updated_at
+			FROM bookings b
+			WHERE b.payment_status IN ('paid', 'refunded')
+				AND NOT EXISTS (SELECT 1 FROM payments p WHERE p.booking_id = b.id)`).Error; err != nil {
+			return err
+		}
+		// Booking terefund mendapat refund penuh atas pembayaran migrasi di atas
+		return tx.Exec(`INSERT INTO payments (tenant_id, booking_id, kind, amount, currency, method, provider, provider_reference, status, refund_of_id, note, recorded_by, created_at, updated_at)
+			SELECT p.tenant_id, p.booking_id, 'refund', p.amount, p.currency, p.method, p.provider, '', 'succeeded', p.id,
+				'migrasi status pembayaran lama', 0, p.updated_at, p.updated_at
+			FROM payments p
+			JOIN bookings b ON b.id = p.booking_id
+			WHERE b.payment_status = 'refunded' AND p.kind = 'payment'
+				AND NOT EXISTS (SELECT 1 FROM payments r WHERE r.booking_id = b.id AND r.kind = 'refund')`).Error
+	})
+	if err != nil {
+		log.Fatalf("gagal mengisi ledger pembayaran booking lama: %v", err)
+	}
+}
+
+// BackfillPaymentDueAt memberi batas pembayaran (created_at + deadline) pada booking pending yang
+// belum dibayar dan dibuat sebelum ada batas pembayaran, agar PaymentDeadlineJob ikut membatalkannya
+// dan inventorinya tidak tertahan selamanya. Dijalankan setelah AutoMigrate menambahkan kolomnya.
+func BackfillPaymentDueAt(db *gorm.DB, deadline time.Duration) {
+	err := db.Exec("UPDATE bookings SET payment_due_at = DATE_ADD(created_at, INTERVAL ? SECOND) WHERE booking_status = ? AND payment_status IN ? AND payment_due_at IS NULL",
+		int64(deadline/time.Second), models.StatusPending, models.UnpaidPaymentStatuses).Error
+	if err != nil {
+		log.Fatalf("gagal mengisi batas pembayaran booking lama: %v", err)
+	}
+}
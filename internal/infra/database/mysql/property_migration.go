@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"backend/internal/domain/models"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// propertyOwnedTables adalah tabel yang kini dimiliki sebuah properti
+var propertyOwnedTables = []string{"room_types", "rooms", "tax_fees", "bookings"}
+
+// legacyUniqueColumns adalah kolom yang dulu unik secara global dan kini unik per properti
+var legacyUniqueColumns = map[string]string{
+	"rooms":      "room_number",
+	"room_types": "code",
+	"tax_fees":   "code",
+}
+
+// MigrateProperties memindahkan database hotel tunggal ke model multi-properti. Semua data lama
+// ditempatkan di satu properti default, lalu index unik global (nomor kamar, kode tipe kamar,
+// kode pajak) dihapus agar AutoMigrate membuat index unik per properti. Wajib dijalankan sebelum
+// MigrateRoomTypes dan AutoMigrate; tidak melakukan apa-apa bila rooms sudah punya kolom property_id.
+func MigrateProperties(db *gorm.DB) {
+	if err := migrateProperties(db); err != nil {
+		log.Fatalf("gagal migrasi properti: %v", err)
+	}
+}
+
+func migrateProperties(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable("rooms") || migrator.HasColumn("rooms", "property_id") {
+		return nil // Database baru atau sudah dimigrasi
+	}
+
+	if err := db.AutoMigrate(&models.Property{}, &models.Building{}); err != nil {
+		return err
+	}
+
+	var property models.Property
+	if err := db.Order("id asc").Limit(1).Find(&property).Error; err != nil {
+		return err
+	}
+	if property.ID == 0 {
+		// MigrateTenants sudah berjalan sehingga tenant pertama selalu ada
+		tenant, err := defaultTenant(db)
+		if err != nil {
+			return err
+		}
+		property = models.Property{TenantID: tenant.ID, Code: "MAIN", Name: "MyHotel", IsActive: true}
+		if err := db.Create(&property).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, table := range propertyOwnedTables {
+		if !migrator.HasTable(table) || migrator.HasColumn(table, "property_id") {
+			continue
+		}
+		if err := addNullableColumn(db, table, "property_id"); err != nil {
+			return err
+		}
+		err := db.Exec(fmt.Sprintf("UPDATE `%s` SET property_id = ? WHERE property_id IS NULL", table), property.ID).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	for table, column := range legacyUniqueColumns {
+		if !migrator.HasTable(table) {
+			continue
+		}
+		if err := dropSingleColumnUniqueIndexes(db, table, column); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Data hotel lama dimigrasi ke properti %s.", property.Code)
+	return nil
+}
+
+// dropSingleColumnUniqueIndexes menghapus index unik yang hanya mencakup satu kolom tertentu
+func dropSingleColumnUniqueIndexes(db *gorm.DB, table, column string) error {
+	var indexes []string
+	err := db.Raw(`SELECT INDEX_NAME FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND NON_UNIQUE = 0 AND INDEX_NAME <> 'PRIMARY'
+		GROUP BY INDEX_NAME
+		HAVING COUNT(*) = 1 AND MAX(COLUMN_NAME) = ?`, table, column).Scan(&indexes).Error
+	if err != nil {
+		return err
+	}
+	for _, name := range indexes {
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE `%s` DROP INDEX `%s`", table, name)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package mysql
+
+import (
+	"backend/internal/domain/models"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// legacyRoomColumns adalah kolom produk yang dulu ada di rooms dan kini dipindah ke room_types
+var legacyRoomColumns = []string{"type", "price", "description", "max_occupancy", "cancellation_policy_id"}
+
+// MigrateRoomTypes memindahkan data produk kamar lama (rooms.type/price/...) ke tabel room_types.
+// Setiap kombinasi rooms.type dan rooms.price menjadi satu tipe kamar: harga termurah memakai
+// kode rooms.type, harga lain menjadi varian berkode "<type>-2", "<type>-3", dst. agar harga
+// kamar lama tidak hilang. Setelah itu rooms, bookings, rate_plans, dan booking_modifications
+// diarahkan ke tipe tersebut. Wajib dijalankan setelah MigrateMoneyColumns dan sebelum
+// AutoMigrate; tidak melakukan apa-apa bila rooms sudah tidak punya kolom type.
+func MigrateRoomTypes(db *gorm.DB) {
+	if err := migrateRoomTypes(db); err != nil {
+		log.Fatalf("gagal migrasi tipe kamar: %v", err)
+	}
+}
+
+func migrateRoomTypes(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable("rooms") || !migrator.HasColumn("rooms", "type") {
+		return nil // Database baru atau sudah dimigrasi
+	}
+
+	if err := db.AutoMigrate(&models.CancellationPolicy{}, &models.CancellationRule{}, &models.RoomType{}); err != nil {
+		return err
+	}
+
+	if err := createRoomTypeVariants(db); err != nil {
+		return err
+	}
+
+	// bookings.room_type_id diturunkan dari kamar yang dipesan
+	if migrator.HasTable("bookings") {
+		if err := addNullableColumn(db, "bookings", "room_type_id"); err != nil {
+			return err
+		}
+		if err := db.Exec("UPDATE bookings b JOIN rooms r ON r.id = b.room_id SET b.room_type_id = r.room_type_id WHERE b.room_type_id IS NULL").Error; err != nil {
+			return err
+		}
+	}
+
+	// rate_plans berpindah dari kamar fisik ke tipe kamar
+	if migrator.HasTable("rate_plans") && migrator.HasColumn("rate_plans", "room_id") {
+		if err := addNullableColumn(db, "rate_plans", "room_type_id"); err != nil {
+			return err
+		}
+		if err := db.Exec("UPDATE rate_plans p JOIN rooms r ON r.id = p.room_id SET p.room_type_id = r.room_type_id WHERE p.room_type_id IS NULL").Error; err != nil {
+			return err
+		}
+		if err := dropForeignKeys(db, "rate_plans", "room_id"); err != nil {
+			return err
+		}
+		if err := db.Exec("ALTER TABLE rate_plans DROP COLUMN room_id").Error; err != nil {
+			return err
+		}
+	}
+
+	// booking_modifications mencatat tipe kamar sebelum & sesudah perubahan
+	if migrator.HasTable("booking_modifications") {
+		for _, side := range []string{"from", "to"} {
+			column := side + "_room_type_id"
+			if err := addNullableColumn(db, "booking_modifications", column); err != nil {
+				return err
+			}
+			err := db.Exec(fmt.Sprintf("UPDATE booking_modifications m JOIN rooms r ON r.id = m.%s_room_id SET m.%s = r.room_type_id WHERE m.%s IS NULL",
+				side, column, column)).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Hapus kolom produk lama dari rooms (beserta foreign key kebijakan pembatalannya)
+	if err := dropForeignKeys(db, "rooms", "cancellation_policy_id"); err != nil {
+		return err
+	}
+	for _, column := range legacyRoomColumns {
+		if !migrator.HasColumn("rooms", column) {
+			continue
+		}
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE rooms DROP COLUMN `%s`", column)).Error; err != nil {
+			return err
+		}
+	}
+
+	log.Println("Data kamar lama dimigrasi ke tipe kamar.")
+	return nil
+}
+
+// legacyRoomVariant adalah satu kombinasi rooms.type dan rooms.price pada data kamar lama
+type legacyRoomVariant struct {
+	Type                 string
+	Price                int64
+	TenantID             uint
+	PropertyID           uint
+	Description          string
+	MaxOccupancy         int
+	CancellationPolicyID *uint
+	RoomNumbers          string
+}
+
+// createRoomTypeVariants membuat satu tipe kamar per varian harga lalu mengisi rooms.room_type_id.
+// Tipe kamar yang kodenya sudah ada (migrasi sebelumnya terhenti di tengah jalan) dipakai ulang.
+func createRoomTypeVariants(db *gorm.DB) error {
+	policyExpr := "NULL"
+	if db.Migrator().HasColumn("rooms", "cancellation_policy_id") {
+		policyExpr = "MAX(r.cancellation_policy_id)"
+	}
+	// tenant_id & property_id diisi MigrateTenants dan MigrateProperties yang berjalan lebih dulu
+	var variants []legacyRoomVariant
+	err := db.Raw(fmt.Sprintf(`SELECT r.type AS type, r.price AS price, MAX(r.tenant_id) AS tenant_id, MAX(r.property_id) AS property_id,
+		MAX(r.description) AS description, MAX(r.max_occupancy) AS max_occupancy, %s AS cancellation_policy_id,
+		GROUP_CONCAT(r.room_number ORDER BY r.room_number SEPARATOR ', ') AS room_numbers
+		FROM rooms r
+		WHERE r.deleted_at IS NULL
+		GROUP BY r.type, r.price
+		ORDER BY r.type, r.price`, policyExpr)).Scan(&variants).Error
+	if err != nil {
+		return err
+	}
+	if err := addNullableColumn(db, "rooms", "room_type_id"); err != nil {
+		return err
+	}
+
+	variantNo := make(map[string]int)
+	for _, variant := range variants {
+		variantNo[variant.Type]++
+		code, name := variant.Type, variant.Type
+		if n := variantNo[variant.Type]; n > 1 {
+			code = fmt.Sprintf("%s-%d", variant.Type, n)
+			name = fmt.Sprintf("%s (harga %d)", variant.Type, variant.Price)
+			log.Printf("Perhatian: kamar %s bertipe %q memiliki harga berbeda (%d) dan dipindah ke tipe kamar baru %s; periksa kembali tipe kamar ini.",
+				variant.RoomNumbers, variant.Type, variant.Price, code)
+		}
+
+		var roomTypeID uint
+		err := db.Raw("SELECT id FROM room_types WHERE property_id = ? AND code = ? AND deleted_at IS NULL", variant.PropertyID, code).Scan(&roomTypeID).Error
+		if err != nil {
+			return err
+		}
+		if roomTypeID == 0 {
+			err := db.Exec(`INSERT INTO room_types (tenant_id, property_id, code, name, description, price, max_occupancy, cancellation_policy_id, created_at, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`,
+				variant.TenantID, variant.PropertyID, code, name, variant.Description, variant.Price, variant.MaxOccupancy, variant.CancellationPolicyID).Error
+			if err != nil {
+				return err
+			}
+			if err := db.Raw("SELECT id FROM room_types WHERE property_id = ? AND code = ? AND deleted_at IS NULL", variant.PropertyID, code).Scan(&roomTypeID).Error; err != nil {
+				return err
+			}
+		}
+
+		err = db.Exec("UPDATE rooms SET room_type_id = ? WHERE type = ? AND price = ? AND room_type_id IS NULL",
+			roomTypeID, variant.Type, variant.Price).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	// Kamar yang sudah dihapus (soft delete) tetap diarahkan ke tipe dasar agar riwayat booking-nya utuh
+	return db.Exec(`UPDATE rooms r JOIN room_types rt ON rt.code = r.type AND rt.property_id = r.property_id
+		SET r.room_type_id = rt.id WHERE r.room_type_id IS NULL`).Error
+}
+
+// addNullableColumn menambah kolom BIGINT UNSIGNED nullable bila belum ada;
+// AutoMigrate kemudian menyesuaikan tipe & constraint sesuai model
+func addNullableColumn(db *gorm.DB, table, column string) error {
+	if db.Migrator().HasColumn(table, column) {
+		return nil
+	}
+	return db.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` BIGINT UNSIGNED NULL", table, column)).Error
+}
+
+// dropForeignKeys menghapus semua foreign key pada kolom tertentu agar kolom dapat di-drop
+func dropForeignKeys(db *gorm.DB, table, column string) error {
+	var constraints []string
+	err := db.Raw(`SELECT CONSTRAINT_NAME FROM information_schema.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ? AND REFERENCED_TABLE_NAME IS NOT NULL`,
+		table, column).Scan(&constraints).Error
+	if err != nil {
+		return err
+	}
+	for _, name := range constraints {
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE `%s` DROP FOREIGN KEY `%s`", table, name)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
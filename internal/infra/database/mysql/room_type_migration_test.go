@@ -0,0 +1,109 @@
+package mysql_test
+
+import (
+	"testing"
+
+	"backend/internal/domain/models"
+	"backend/internal/infra/database/mysql"
+	"backend/internal/infra/database/mysql/mysqltest"
+	"backend/internal/infra/gorm/repositories"
+)
+
+// TestMigrateRoomTypesSplitsPriceVariants memastikan kamar lama bertipe sama dengan harga berbeda
+// tidak digabung ke satu harga, melainkan dipecah menjadi varian tipe kamar per harga
+func TestMigrateRoomTypesSplitsPriceVariants(t *testing.T) {
+	db := mysqltest.Open(t)
+	tenantID := mysqltest.CreateTenant(t, db, "legacy")
+	property := &models.Property{Code: "MAIN", Name: "MyHotel", IsActive: true}
+	mysqltest.Create(t, repositories.WithTenant(db, tenantID), property)
+
+	// Bentuk tabel rooms sebelum tipe kamar diperkenalkan (FOREIGN_KEY_CHECKS berlaku per koneksi)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		"SET FOREIGN_KEY_CHECKS = 0",
+		"DROP TABLE booking_modifications",
+		"DROP TABLE rooms",
+		`CREATE TABLE rooms (
+			id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			created_at DATETIME(3) NULL, updated_at DATETIME(3) NULL, deleted_at DATETIME(3) NULL,
+			tenant_id BIGINT UNSIGNED NOT NULL, property_id BIGINT UNSIGNED NOT NULL,
+			room_number VARCHAR(50) NOT NULL, type VARCHAR(50) NOT NULL, price BIGINT NOT NULL,
+			description TEXT, max_occupancy BIGINT NOT NULL, status VARCHAR(20) NOT NULL
+		)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("gagal menyiapkan skema lama: %v", err)
+		}
+	}
+
+	legacyRooms := []struct {
+		number  string
+		kind    string
+		price   int64
+		deleted bool
+	}{
+		{number: "101", kind: "DLX", price: 500000},
+		{number: "102", kind: "DLX", price: 500000},
+		{number: "201", kind: "DLX", price: 750000},
+		{number: "301", kind: "STD", price: 300000},
+		{number: "999", kind: "DLX", price: 900000, deleted: true},
+	}
+	for _, room := range legacyRooms {
+		deletedAt := "NULL"
+		if room.deleted {
+			deletedAt = "NOW()"
+		}
+		err := db.Exec("INSERT INTO rooms (created_at, updated_at, deleted_at, tenant_id, property_id, room_number, type, price, description, max_occupancy, status) VALUES (NOW(), NOW(), "+deletedAt+", ?, ?, ?, ?, ?, '', 2, 'available')",
+			tenantID, property.ID, room.number, room.kind, room.price).Error
+		if err != nil {
+			t.Fatalf("gagal membuat kamar lama %s: %v", room.number, err)
+		}
+	}
+
+	mysql.MigrateRoomTypes(db)
+
+	var migrated []struct {
+		RoomNumber string
+		Code       string
+		Price      int64
+	}
+	err = db.Raw(`SELECT r.room_number AS room_number, rt.code AS code, rt.price AS price
+		FROM rooms r JOIN room_types rt ON rt.id = r.room_type_id ORDER BY r.room_number`).Scan(&migrated).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]struct {
+		code  string
+		price int64
+	}{
+		"101": {"DLX", 500000},
+		"102": {"DLX", 500000},
+		"201": {"DLX-2", 750000},
+		"301": {"STD", 300000},
+		"999": {"DLX", 500000}, // Kamar terhapus diarahkan ke tipe dasar
+	}
+	if len(migrated) != len(want) {
+		t.Fatalf("ingin %d kamar bertipe, dapat %d", len(want), len(migrated))
+	}
+	for _, room := range migrated {
+		if w := want[room.RoomNumber]; room.Code != w.code || room.Price != w.price {
+			t.Errorf("kamar %s bertipe %s (%d), ingin %s (%d)", room.RoomNumber, room.Code, room.Price, w.code, w.price)
+		}
+	}
+
+	var roomTypes int64
+	if err := db.Raw("SELECT COUNT(*) FROM room_types").Scan(&roomTypes).Error; err != nil {
+		t.Fatal(err)
+	}
+	if roomTypes != 3 {
+		t.Fatalf("ingin 3 tipe kamar (DLX, DLX-2, STD), dapat %d", roomTypes)
+	}
+	if db.Migrator().HasColumn("rooms", "type") {
+		t.Fatal("kolom rooms.type seharusnya sudah dihapus")
+	}
+}
@@ -0,0 +1,46 @@
+package mysql
+
+import "backend/internal/domain/models"
+
+// Models mengembalikan semua model yang tabelnya dibuat lewat AutoMigrate, dengan urutan
+// induk sebelum anak agar foreign key dapat dibuat
+func Models() []interface{} {
+	return []interface{}{
+		&models.Tenant{},
+		&models.Property{},
+		&models.Building{},
+		&models.User{},
+		&models.Role{},
+		&models.RolePermission{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+		&models.AccountToken{},
+		&models.UserMFA{},
+		&models.MFARecoveryCode{},
+		&models.RoomType{},
+		&models.Room{},
+		&models.Amenity{},
+		&models.RoomAmenity{},
+		&models.RoomImage{},
+		&models.Booking{},
+		&models.Review{},
+		&models.RoomNight{},
+		&models.BookingTransition{},
+		&models.RatePlan{},
+		&models.RateSeason{},
+		&models.RateDatePrice{},
+		&models.RateBlackout{},
+		&models.StayRestriction{},
+		&models.BookingNightPrice{},
+		&models.PromoCode{},
+		&models.PromoRedemption{},
+		&models.TaxFee{},
+		&models.BookingPriceComponent{},
+		&models.ExchangeRate{},
+		&models.PaymentWebhookEvent{},
+		&models.Payment{},
+		&models.CancellationPolicy{},
+		&models.CancellationRule{},
+		&models.BookingModification{},
+	}
+}
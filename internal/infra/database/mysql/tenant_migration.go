@@ -0,0 +1,107 @@
+package mysql
+
+import (
+	"backend/internal/domain/models"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// tenantOwnedTables adalah tabel yang datanya dimiliki satu tenant (punya kolom tenant_id).
+// Tabel anak (room_nights, rate_seasons, dst.) terisolasi lewat induknya.
+var tenantOwnedTables = []string{
+	"properties", "users", "room_types", "rooms", "room_images", "bookings", "reviews",
+	"rate_plans", "promo_codes", "tax_fees", "exchange_rates", "cancellation_policies",
+}
+
+// bookingChildTables adalah tabel anak booking yang tetap punya tenant_id sendiri karena dicari
+// langsung tanpa melewati booking (mis. webhook pembayaran berdasarkan referensi provider)
+var bookingChildTables = []string{"payments", "payment_webhook_events"}
+
+// tenantUniqueColumns adalah kolom yang dulu unik secara global dan kini unik per tenant
+var tenantUniqueColumns = map[string][]string{
+	"users":                 {"username", "email"},
+	"properties":            {"code"},
+	"promo_codes":           {"code"},
+	"exchange_rates":        {"currency"},
+	"cancellation_policies": {"code"},
+}
+
+// MigrateTenants memastikan minimal ada satu tenant, lalu menempatkan data yang belum punya
+// tenant_id (database sebelum multi-tenant) ke tenant pertama;
+// pembayaran dan event webhook mengikuti tenant booking-nya. Index unik global pada tabel yang
+// baru dimigrasi dihapus agar AutoMigrate membuat index unik per tenant. Wajib dijalankan sebelum
+// MigrateProperties, MigrateRoomTypes, dan AutoMigrate.
+func MigrateTenants(db *gorm.DB, defaultCode string) {
+	if err := migrateTenants(db, defaultCode); err != nil {
+		log.Fatalf("gagal migrasi tenant: %v", err)
+	}
+}
+
+func migrateTenants(db *gorm.DB, defaultCode string) error {
+	if err := db.AutoMigrate(&models.Tenant{}); err != nil {
+		return err
+	}
+
+	tenant, err := defaultTenant(db)
+	if err != nil {
+		return err
+	}
+	if tenant.ID == 0 {
+		if defaultCode == "" {
+			defaultCode = "main"
+		}
+		tenant = &models.Tenant{Code: defaultCode, Name: "MyHotel", IsActive: true}
+		if err := db.Create(tenant).Error; err != nil {
+			return err
+		}
+		log.Printf("Tenant default %s dibuat.", tenant.Code)
+	}
+
+	migrator := db.Migrator()
+	for _, table := range tenantOwnedTables {
+		if !migrator.HasTable(table) || migrator.HasColumn(table, "tenant_id") {
+			continue
+		}
+		if err := addNullableColumn(db, table, "tenant_id"); err != nil {
+			return err
+		}
+		if err := db.Exec(fmt.Sprintf("UPDATE `%s` SET tenant_id = ? WHERE tenant_id IS NULL", table), tenant.ID).Error; err != nil {
+			return err
+		}
+		for _, column := range tenantUniqueColumns[table] {
+			if err := dropSingleColumnUniqueIndexes(db, table, column); err != nil {
+				return err
+			}
+		}
+		log.Printf("Data %s dimigrasi ke tenant %s.", table, tenant.Code)
+	}
+
+	// Tenant tabel anak mengikuti booking-nya; baris tanpa booking masuk tenant pertama
+	for _, table := range bookingChildTables {
+		if !migrator.HasTable(table) || migrator.HasColumn(table, "tenant_id") {
+			continue
+		}
+		if err := addNullableColumn(db, table, "tenant_id"); err != nil {
+			return err
+		}
+		if err := db.Exec(fmt.Sprintf("UPDATE `%s` t JOIN bookings b ON b.id = t.booking_id SET t.tenant_id = b.tenant_id WHERE t.tenant_id IS NULL", table)).Error; err != nil {
+			return err
+		}
+		if err := db.Exec(fmt.Sprintf("UPDATE `%s` SET tenant_id = ? WHERE tenant_id IS NULL", table), tenant.ID).Error; err != nil {
+			return err
+		}
+		log.Printf("Data %s dimigrasi ke tenant booking-nya.", table)
+	}
+	return nil
+}
+
+// defaultTenant mengembalikan tenant pertama; ID 0 jika belum ada tenant sama sekali
+func defaultTenant(db *gorm.DB) (*models.Tenant, error) {
+	var tenant models.Tenant
+	if err := db.Order("id asc").Limit(1).Find(&tenant).Error; err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
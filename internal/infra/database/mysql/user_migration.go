@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// MigrateEmailVerification menambah kolom users.email_verified_at dan menganggap user lama sudah
+// terverifikasi (mereka mendaftar sebelum ada verifikasi email, jadi tidak boleh tiba-tiba diblokir
+// dari booking). Wajib dijalankan sebelum AutoMigrate; tidak melakukan apa-apa bila kolom sudah ada.
+func MigrateEmailVerification(db *gorm.DB) {
+	migrator := db.Migrator()
+	if !migrator.HasTable("users") || migrator.HasColumn("users", "email_verified_at") {
+		return // Database baru atau sudah dimigrasi
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("ALTER TABLE `users` ADD COLUMN `email_verified_at` DATETIME(3) NULL").Error; err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE users SET email_verified_at = created_at").Error
+	})
+	if err != nil {
+		log.Fatalf("gagal migrasi verifikasi email user lama: %v", err)
+	}
+}
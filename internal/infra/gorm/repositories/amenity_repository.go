@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+type gormAmenityRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAmenityRepository(db *gorm.DB) repositories.AmenityRepository {
+	return &gormAmenityRepository{db: db}
+}
+
+// amenitiesOrdered adalah urutan tampil fasilitas (katalog, preload kamar, dan hasil pencarian)
+func amenitiesOrdered(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order ASC, name ASC")
+}
+
+func (r *gormAmenityRepository) Create(amenity *models.Amenity) error {
+	return r.db.Create(amenity).Error
+}
+
+func (r *gormAmenityRepository) Update(amenity *models.Amenity) error {
+	return r.db.Save(amenity).Error
+}
+
+func (r *gormAmenityRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("amenity_id = ?", id).Delete(&models.RoomAmenity{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Amenity{}, id).Error
+	})
+}
+
+func (r *gormAmenityRepository) FindByID(id uint) (*models.Amenity, error) {
+	var amenity models.Amenity
+	if err := r.db.First(&amenity, id).Error; err != nil {
+		return nil, err
+	}
+	return &amenity, nil
+}
+
+func (r *gormAmenityRepository) FindByIDs(ids []uint) ([]models.Amenity, error) {
+	amenities := []models.Amenity{}
+	if len(ids) == 0 {
+		return amenities, nil
+	}
+	err := r.db.Scopes(amenitiesOrdered).Where("id IN ?", ids).Find(&amenities).Error
+	return amenities, err
+}
+
+func (r *gormAmenityRepository) FindAll(pagination *models.Pagination) ([]models.Amenity, error) {
+	var amenities []models.Amenity
+	query := r.db.Order(pagination.Sort)
+
+	if pagination.Limit > 0 {
+		query = query.Limit(pagination.Limit).Offset(pagination.Offset)
+	}
+
+	if err := query.Find(&amenities).Error; err != nil {
+		return nil, err
+	}
+	return amenities, nil
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormAuthTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAuthTokenRepository(db *gorm.DB) repositories.AuthTokenRepository {
+	return &gormAuthTokenRepository{db: db}
+}
+
+func (r *gormAuthTokenRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *gormAuthTokenRepository) FindRefreshTokenByHash(hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *gormAuthTokenRepository) FindRefreshTokenByAccessJTI(jti string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.Where("access_jti = ?", jti).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *gormAuthTokenRepository) RevokeRefreshToken(id uint, at time.Time) (bool, error) {
+	// Update bersyarat: hanya satu request yang berhasil merotasi token yang sama
+	result := r.db.Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *gormAuthTokenRepository) RevokeFamily(familyID string, at time.Time) ([]string, error) {
+	var jtis []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.RefreshToken{}).Where("family_id = ?", familyID).Pluck("access_jti", &jtis).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", familyID).
+			Update("revoked_at", at).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jtis, nil
+}
+
+func (r *gormAuthTokenRepository) RevokeAccessTokens(tokens []models.RevokedToken) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&tokens).Error
+}
+
+func (r *gormAuthTokenRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormAuthTokenRepository) RevokeUserSessions(userID uint, at time.Time) ([]string, error) {
+	var jtis []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userID).Pluck("access_jti", &jtis).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", at).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jtis, nil
+}
+
+func (r *gormAuthTokenRepository) CreateAccountToken(token *models.AccountToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *gormAuthTokenRepository) FindAccountToken(purpose, hash string) (*models.AccountToken, error) {
+	var token models.AccountToken
+	if err := r.db.Where("purpose = ? AND token_hash = ?", purpose, hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *gormAuthTokenRepository) UseAccountToken(id uint, at time.Time) (bool, error) {
+	// Update bersyarat: token hanya bisa dipakai sekali walau dikirim bersamaan
+	result := r.db.Model(&models.AccountToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *gormAuthTokenRepository) InvalidateAccountTokens(userID uint, purpose string, at time.Time) error {
+	return r.db.Model(&models.AccountToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", at).Error
+}
+
+func (r *gormAuthTokenRepository) FailAccountToken(id uint, maxAttempts int, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.AccountToken{}).
+			Where("id = ? AND used_at IS NULL", id).
+			Update("attempts", gorm.Expr("attempts + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.AccountToken{}).
+			Where("id = ? AND used_at IS NULL AND attempts >= ?", id, maxAttempts).
+			Update("used_at", at).Error
+	})
+}
+
+func (r *gormAuthTokenRepository) DeleteExpired(before time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", before).Delete(&models.RefreshToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("expires_at < ?", before).Delete(&models.AccountToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("expires_at < ?", before).Delete(&models.RevokedToken{}).Error
+	})
+}
@@ -4,8 +4,11 @@ import (
 	"backend/internal/domain/models"
 	"backend/internal/domain/repositories"
 	"errors"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type gormBookingRepository struct {
@@ -30,7 +33,15 @@ func (r *gormBookingRepository) Delete(id uint) error {
 
 func (r *gormBookingRepository) FindByID(id uint) (*models.Booking, error) {
 	var booking models.Booking
-	if err := r.db.Preload("Room").Preload("User").First(&booking, id).Error; err != nil {
+	if err := r.db.Preload("RoomType").Preload("Room").Preload("User").Preload("NightPrices").Preload("PriceComponents").Preload("Payments").Preload("Modifications").First(&booking, id).Error; err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+func (r *gormBookingRepository) LockByID(id uint) (*models.Booking, error) {
+	var booking models.Booking
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&booking, id).Error; err != nil {
 		return nil, err
 	}
 	return &booking, nil
@@ -44,21 +55,21 @@ func (r *gormBookingRepository) FindByUserID(userID uint, pagination *models.Pag
 		query = query.Limit(pagination.Limit).Offset(pagination.Offset)
 	}
 
-	if err := query.Preload("Room").Find(&bookings).Error; err != nil {
+	if err := query.Preload("RoomType").Preload("Room").Find(&bookings).Error; err != nil {
 		return nil, err
 	}
 	return bookings, nil
 }
 
-func (r *gormBookingRepository) FindAll(pagination *models.Pagination) ([]models.Booking, error) {
+func (r *gormBookingRepository) FindAll(propertyID uint, pagination *models.Pagination) ([]models.Booking, error) {
 	var bookings []models.Booking
-	query := r.db.Order(pagination.Sort)
+	query := r.db.Scopes(inProperty(propertyID)).Order(pagination.Sort)
 
 	if pagination.Limit > 0 {
 		query = query.Limit(pagination.Limit).Offset(pagination.Offset)
 	}
 
-	if err := query.Preload("Room").Preload("User").Find(&bookings).Error; err != nil {
+	if err := query.Preload("RoomType").Preload("Room").Preload("User").Find(&bookings).Error; err != nil {
 		return nil, err
 	}
 	return bookings, nil
@@ -76,14 +87,18 @@ func (r *gormBookingRepository) UpdateStatus(id uint, newStatus string) error {
 	return nil
 }
 
-func (r *gormBookingRepository) CheckOverlap(roomID uint, checkInDate, checkOutDate string) (bool, error) {
+func (r *gormBookingRepository) CheckOverlap(roomID uint, checkInDate, checkOutDate string, excludeBookingID uint, now time.Time) (bool, error) {
 	var count int64
 
-	err := r.db.Model(&models.Booking{}).
+	query := r.db.Model(&models.Booking{}).
 		Where("room_id = ?", roomID).
-		Where("booking_status IN (?)", []string{models.StatusConfirmed, models.StatusPaid}).
-		Where("check_out_date > ? AND check_in_date < ?", checkInDate, checkOutDate).
-		Count(&count).Error
+		Scopes(activeBookings(now)).
+		Where("check_out_date > ? AND check_in_date < ?", checkInDate, checkOutDate)
+	// Booking yang sedang diubah tidak dianggap bentrok dengan dirinya sendiri
+	if excludeBookingID != 0 {
+		query = query.Where("id <> ?", excludeBookingID)
+	}
+	err := query.Count(&count).Error
 
 	if err != nil {
 		return false, err
@@ -91,3 +106,167 @@ func (r *gormBookingRepository) CheckOverlap(roomID uint, checkInDate, checkOutD
 
 	return count > 0, nil
 }
+
+func (r *gormBookingRepository) TransitionStatus(id uint, fromStatus, toStatus string) error {
+	// WHERE booking_status = fromStatus mencegah dua transisi paralel dari status yang sama
+	result := r.db.Model(&models.Booking{}).
+		Where("id = ? AND booking_status = ?", id, fromStatus).
+		Update("booking_status", toStatus)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrInvalidTransition
+	}
+	return nil
+}
+
+func (r *gormBookingRepository) CreateTransition(transition *models.BookingTransition) error {
+	return r.db.Create(transition).Error
+}
+
+func (r *gormBookingRepository) FindTransitions(bookingID uint) ([]models.BookingTransition, error) {
+	var transitions []models.BookingTransition
+	if err := r.db.Where("booking_id = ?", bookingID).Order("created_at asc, id asc").Find(&transitions).Error; err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+func (r *gormBookingRepository) FindExpiredHolds(now time.Time, roomTypeID uint, limit int) ([]models.Booking, error) {
+	var bookings []models.Booking
+	query := r.db.Where("booking_status = ? AND hold_expires_at <= ?", models.StatusHold, now).Order("hold_expires_at asc")
+
+	if roomTypeID > 0 {
+		query = query.Where("room_type_id = ?", roomTypeID)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (r *gormBookingRepository) FindOverduePending(now time.Time, limit int) ([]models.Booking, error) {
+	var bookings []models.Booking
+	query := r.db.Where("booking_status = ? AND payment_status IN ?", models.StatusPending, models.UnpaidPaymentStatuses).
+		Where("payment_due_at <= ?", now).
+		Order("payment_due_at asc")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (r *gormBookingRepository) ReserveNights(booking *models.Booking) error {
+	nights := booking.Nights()
+	if len(nights) == 0 || booking.RoomID == nil {
+		return nil
+	}
+
+	roomNights := make([]models.RoomNight, 0, len(nights))
+	for _, night := range nights {
+		roomNights = append(roomNights, models.RoomNight{
+			RoomID:    *booking.RoomID,
+			Date:      night,
+			BookingID: booking.ID,
+		})
+	}
+
+	if err := r.db.Create(&roomNights).Error; err != nil {
+		// Duplicate entry pada idx_room_night berarti malam tersebut sudah dimiliki booking lain
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			return models.ErrRoomAlreadyBooked
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *gormBookingRepository) ReleaseNights(bookingID uint) error {
+	return r.db.Where("booking_id = ?", bookingID).Delete(&models.RoomNight{}).Error
+}
+
+func (r *gormBookingRepository) FindOverlappingByRoomType(roomTypeID uint, checkInDate, checkOutDate string, excludeBookingID uint, now time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	query := r.db.Select("id", "room_type_id", "room_id", "check_in_date", "check_out_date").
+		Where("room_type_id = ?", roomTypeID).
+		Scopes(activeBookings(now)).
+		Where("check_out_date > ? AND check_in_date < ?", checkInDate, checkOutDate)
+	if excludeBookingID != 0 {
+		query = query.Where("id <> ?", excludeBookingID)
+	}
+	if err := query.Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (r *gormBookingRepository) FindActiveOn(propertyID uint, date string, now time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := r.db.Preload("RoomType").Preload("User").
+		Scopes(inProperty(propertyID), activeBookings(now)).
+		Where("check_in_date <= ? AND check_out_date > ?", date, date).
+		Order("check_in_date asc, id asc").
+		Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// activeBookings adalah scope untuk booking yang masih menempati kamar.
+// Hold yang sudah kedaluwarsa diabaikan walaupun belum dibersihkan sweeper.
+func activeBookings(now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("booking_status IN (?)", models.ActiveBookingStatuses).
+			Where("booking_status <> ? OR hold_expires_at > ?", models.StatusHold, now)
+	}
+}
+
+func (r *gormBookingRepository) ReplacePriceDetails(booking *models.Booking) error {
+	if err := r.db.Where("booking_id = ?", booking.ID).Delete(&models.BookingNightPrice{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("booking_id = ?", booking.ID).Delete(&models.BookingPriceComponent{}).Error; err != nil {
+		return err
+	}
+	for i := range booking.NightPrices {
+		booking.NightPrices[i].ID = 0
+		booking.NightPrices[i].BookingID = booking.ID
+	}
+	for i := range booking.PriceComponents {
+		booking.PriceComponents[i].ID = 0
+		booking.PriceComponents[i].BookingID = booking.ID
+	}
+	if len(booking.NightPrices) > 0 {
+		if err := r.db.Create(&booking.NightPrices).Error; err != nil {
+			return err
+		}
+	}
+	if len(booking.PriceComponents) > 0 {
+		return r.db.Create(&booking.PriceComponents).Error
+	}
+	return nil
+}
+
+func (r *gormBookingRepository) CreateModification(modification *models.BookingModification) error {
+	return r.db.Create(modification).Error
+}
+
+func (r *gormBookingRepository) FindModifications(bookingID uint) ([]models.BookingModification, error) {
+	var modifications []models.BookingModification
+	if err := r.db.Where("booking_id = ?", bookingID).Order("created_at asc, id asc").Find(&modifications).Error; err != nil {
+		return nil, err
+	}
+	return modifications, nil
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+type gormCancellationPolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCancellationPolicyRepository(db *gorm.DB) repositories.CancellationPolicyRepository {
+	return &gormCancellationPolicyRepository{db: db}
+}
+
+// cancellationRulesOrdered adalah preload aturan dari jam terbesar (urutan evaluasi)
+func cancellationRulesOrdered(db *gorm.DB) *gorm.DB {
+	return db.Order("hours_before DESC")
+}
+
+func (r *gormCancellationPolicyRepository) Create(policy *models.CancellationPolicy) error {
+	return r.db.Create(policy).Error
+}
+
+func (r *gormCancellationPolicyRepository) Update(policy *models.CancellationPolicy) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("policy_id = ?", policy.ID).Delete(&models.CancellationRule{}).Error; err != nil {
+			return err
+		}
+		for i := range policy.Rules {
+			policy.Rules[i].ID = 0
+			policy.Rules[i].PolicyID = policy.ID
+		}
+		return tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(policy).Error
+	})
+}
+
+func (r *gormCancellationPolicyRepository) Delete(id uint) error {
+	return r.db.Delete(&models.CancellationPolicy{}, id).Error
+}
+
+func (r *gormCancellationPolicyRepository) FindByID(id uint) (*models.CancellationPolicy, error) {
+	var policy models.CancellationPolicy
+	if err := r.db.Preload("Rules", cancellationRulesOrdered).First(&policy, id).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *gormCancellationPolicyRepository) FindAll(pagination *models.Pagination) ([]models.CancellationPolicy, error) {
+	var policies []models.CancellationPolicy
+	query := r.db.Order(pagination.Sort)
+
+	if pagination.Limit > 0 {
+		query = query.Limit(pagination.Limit).Offset(pagination.Offset)
+	}
+
+	if err := query.Preload("Rules", cancellationRulesOrdered).Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
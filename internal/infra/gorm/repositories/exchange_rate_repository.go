@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormExchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewGormExchangeRateRepository(db *gorm.DB) repositories.ExchangeRateRepository {
+	return &gormExchangeRateRepository{db: db}
+}
+
+func (r *gormExchangeRateRepository) Upsert(rate *models.ExchangeRate) error {
+	// Satu mata uang hanya punya satu kurs aktif per tenant (unique tenant_id + currency)
+	return r.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(rate).Error
+}
+
+func (r *gormExchangeRateRepository) Delete(currency string) error {
+	result := r.db.Where("currency = ?", currency).Delete(&models.ExchangeRate{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *gormExchangeRateRepository) FindByCurrency(currency string) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	if err := r.db.Where("currency = ?", currency).First(&rate).Error; err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *gormExchangeRateRepository) FindAll() ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	err := r.db.Order("currency ASC").Find(&rates).Error
+	return rates, err
+}
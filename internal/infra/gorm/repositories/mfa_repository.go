@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type gormMFARepository struct {
+	db *gorm.DB
+}
+
+func NewGormMFARepository(db *gorm.DB) repositories.MFARepository {
+	return &gormMFARepository{db: db}
+}
+
+func (r *gormMFARepository) FindByUserID(userID uint) (*models.UserMFA, error) {
+	var mfa models.UserMFA
+	if err := r.db.Where("user_id = ?", userID).First(&mfa).Error; err != nil {
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+func (r *gormMFARepository) Save(mfa *models.UserMFA) error {
+	return r.db.Save(mfa).Error
+}
+
+func (r *gormMFARepository) Enable(mfa *models.UserMFA, codes []models.MFARecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(mfa).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, mfa.UserID, codes)
+	})
+}
+
+func (r *gormMFARepository) ReplaceRecoveryCodes(userID uint, codes []models.MFARecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codes)
+	})
+}
+
+// replaceRecoveryCodes menghapus recovery code lama user lalu menyimpan yang baru
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codes []models.MFARecoveryCode) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
+
+func (r *gormMFARepository) MarkStepUsed(userID uint, step int64) (bool, error) {
+	// Update bersyarat: kode yang sama tidak bisa dipakai dua kali walau dikirim bersamaan
+	result := r.db.Model(&models.UserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *gormMFARepository) UseRecoveryCode(userID uint, hash string, at time.Time) (bool, error) {
+	result := r.db.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *gormMFARepository) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.MFARecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *gormMFARepository) Delete(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserMFA{}).Error
+	})
+}
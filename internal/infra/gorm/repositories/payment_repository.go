@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+type gormPaymentRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPaymentRepository(db *gorm.DB) repositories.PaymentRepository {
+	return &gormPaymentRepository{db: db}
+}
+
+func (r *gormPaymentRepository) Create(payment *models.Payment) error {
+	return r.db.Create(payment).Error
+}
+
+func (r *gormPaymentRepository) Update(payment *models.Payment) error {
+	return r.db.Save(payment).Error
+}
+
+func (r *gormPaymentRepository) FindByID(id uint) (*models.Payment, error) {
+	var payment models.Payment
+	if err := r.db.First(&payment, id).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *gormPaymentRepository) FindByReference(provider, reference string) (*models.Payment, error) {
+	var payment models.Payment
+	if err := r.db.Where("provider = ? AND provider_reference = ?", provider, reference).First(&payment).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *gormPaymentRepository) FindByBookingID(bookingID uint) ([]models.Payment, error) {
+	var payments []models.Payment
+	if err := r.db.Where("booking_id = ?", bookingID).Order("id asc").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
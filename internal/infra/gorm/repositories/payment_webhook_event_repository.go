@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+type gormPaymentWebhookEventRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPaymentWebhookEventRepository(db *gorm.DB) repositories.PaymentWebhookEventRepository {
+	return &gormPaymentWebhookEventRepository{db: db}
+}
+
+func (r *gormPaymentWebhookEventRepository) Record(event *models.PaymentWebhookEvent) (bool, error) {
+	if err := r.db.Create(event).Error; err != nil {
+		// Duplicate entry pada idx_provider_event berarti event sudah pernah diproses
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormPromoCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPromoCodeRepository(db *gorm.DB) repositories.PromoCodeRepository {
+	return &gormPromoCodeRepository{db: db}
+}
+
+func (r *gormPromoCodeRepository) Create(promo *models.PromoCode) error {
+	return r.db.Create(promo).Error
+}
+
+func (r *gormPromoCodeRepository) Update(promo *models.PromoCode) error {
+	// used_count hanya diubah atomik oleh redemption, jangan ditimpa nilai lama dari form admin
+	return r.db.Omit("used_count").Save(promo).Error
+}
+
+func (r *gormPromoCodeRepository) Delete(id uint) error {
+	return r.db.Delete(&models.PromoCode{}, id).Error
+}
+
+func (r *gormPromoCodeRepository) FindByID(id uint) (*models.PromoCode, error) {
+	var promo models.PromoCode
+	if err := r.db.First(&promo, id).Error; err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+func (r *gormPromoCodeRepository) FindByCode(code string) (*models.PromoCode, error) {
+	var promo models.PromoCode
+	if err := r.db.Where("code = ?", code).First(&promo).Error; err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+func (r *gormPromoCodeRepository) FindAll(pagination *models.Pagination) ([]models.PromoCode, error) {
+	var promos []models.PromoCode
+	query := r.db.Order(pagination.Sort)
+
+	if pagination.Limit > 0 {
+		query = query.Limit(pagination.Limit).Offset(pagination.Offset)
+	}
+
+	if err := query.Find(&promos).Error; err != nil {
+		return nil, err
+	}
+	return promos, nil
+}
+
+func (r *gormPromoCodeRepository) LockByCode(code string) (*models.PromoCode, error) {
+	var promo models.PromoCode
+	// Lock baris promo agar kuota global & per user tidak terlampaui oleh booking paralel
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&promo).Error; err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+func (r *gormPromoCodeRepository) CountUserRedemptions(promoCodeID, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.PromoRedemption{}).
+		Where("promo_code_id = ? AND user_id = ?", promoCodeID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *gormPromoCodeRepository) CreateRedemption(redemption *models.PromoRedemption) error {
+	if err := r.db.Create(redemption).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&models.PromoCode{}).
+		Where("id = ?", redemption.PromoCodeID).
+		Update("used_count", gorm.Expr("used_count + 1")).Error
+}
+
+func (r *gormPromoCodeRepository) ReleaseRedemption(bookingID uint) error {
+	var redemption models.PromoRedemption
+	result := r.db.Where("booking_id = ?", bookingID).Limit(1).Find(&redemption)
+	if result.Error != nil || result.RowsAffected == 0 {
+		// Booking tanpa promo tidak perlu dilepas
+		return result.Error
+	}
+
+	if err := r.db.Delete(&redemption).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&models.PromoCode{}).
+		Where("id = ? AND used_count > 0", redemption.PromoCodeID).
+		Update("used_count", gorm.Expr("used_count - 1")).Error
+}
+
+func (r *gormPromoCodeRepository) UpdateRedemptionDiscount(bookingID uint, discount money.Money) error {
+	return r.db.Model(&models.PromoRedemption{}).
+		Where("booking_id = ?", bookingID).
+		Update("discount_amount", discount).Error
+}
+
+func (r *gormPromoCodeRepository) GetStats(promoCodeID uint) (*models.PromoCodeStats, error) {
+	promo, err := r.FindByID(promoCodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.PromoCodeStats{
+		PromoCodeID: promo.ID,
+		Code:        promo.Code,
+		UsageLimit:  promo.UsageLimit,
+		Remaining:   -1,
+	}
+	err = r.db.Model(&models.PromoRedemption{}).
+		Select("COUNT(*) AS redemptions, COUNT(DISTINCT user_id) AS unique_users, COALESCE(SUM(discount_amount), 0) AS total_discount").
+		Where("promo_code_id = ?", promoCodeID).
+		Scan(stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if promo.UsageLimit > 0 {
+		stats.Remaining = promo.UsageLimit - promo.UsedCount
+		if stats.Remaining < 0 {
+			stats.Remaining = 0
+		}
+	}
+	return stats, nil
+}
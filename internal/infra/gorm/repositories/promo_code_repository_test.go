@@ -0,0 +1,101 @@
+package repositories_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/internal/infra/database/mysql/mysqltest"
+	"backend/internal/infra/gorm/repositories"
+)
+
+// TestPromoCodeUpdateKeepsUsedCount memastikan edit admin tidak menimpa used_count yang
+// dinaikkan redemption, baik berurutan dengan data lama maupun berjalan bersamaan
+func TestPromoCodeUpdateKeepsUsedCount(t *testing.T) {
+	root := mysqltest.Open(t)
+	db := repositories.WithTenant(root, mysqltest.CreateTenant(t, root, "alpha"))
+	repo := repositories.NewGormPromoCodeRepository(db)
+
+	redeem := func(t *testing.T, promoID, bookingID uint) {
+		t.Helper()
+		redemption := &models.PromoRedemption{
+			PromoCodeID:    promoID,
+			UserID:         1,
+			BookingID:      bookingID,
+			DiscountAmount: money.New(10000, "IDR"),
+			CreatedAt:      time.Now(),
+		}
+		if err := repo.CreateRedemption(redemption); err != nil {
+			t.Errorf("gagal redeem booking %d: %v", bookingID, err)
+		}
+	}
+	usedCount := func(t *testing.T, promoID uint) int {
+		t.Helper()
+		stored, err := repo.FindByID(promoID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stored.UsedCount
+	}
+
+	tests := []struct {
+		name        string
+		redemptions int
+		concurrent  bool
+	}{
+		{name: "edit dengan data lama setelah redemption", redemptions: 1},
+		{name: "edit bersamaan dengan redemption", redemptions: 10, concurrent: true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promo := &models.PromoCode{Code: fmt.Sprintf("PROMO%d", i), DiscountType: models.DiscountPercentage, DiscountBasisPoints: 1000, UsageLimit: 100, IsActive: true}
+			mysqltest.Create(t, db, promo)
+			stale, err := repo.FindByID(promo.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bookingBase := uint(i+1) * 1000
+
+			if tt.concurrent {
+				var wg sync.WaitGroup
+				for n := 0; n < tt.redemptions; n++ {
+					wg.Add(1)
+					go func(bookingID uint) {
+						defer wg.Done()
+						redeem(t, promo.ID, bookingID)
+					}(bookingBase + uint(n))
+				}
+				for n := 0; n < tt.redemptions; n++ {
+					wg.Add(1)
+					go func(n int) {
+						defer wg.Done()
+						edit := *stale
+						edit.Description = "Diubah admin"
+						edit.UsageLimit = 200 + n
+						if err := repo.Update(&edit); err != nil {
+							t.Errorf("gagal mengubah promo: %v", err)
+						}
+					}(n)
+				}
+				wg.Wait()
+			} else {
+				redeem(t, promo.ID, bookingBase)
+				stale.Description = "Diubah admin"
+				if err := repo.Update(stale); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := usedCount(t, promo.ID); got != tt.redemptions {
+				t.Fatalf("ingin used_count %d, dapat %d", tt.redemptions, got)
+			}
+			stored, _ := repo.FindByID(promo.ID)
+			if stored.Description != "Diubah admin" {
+				t.Fatalf("perubahan admin tidak tersimpan: %q", stored.Description)
+			}
+		})
+	}
+}
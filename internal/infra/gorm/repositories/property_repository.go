@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+type gormPropertyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPropertyRepository(db *gorm.DB) repositories.PropertyRepository {
+	return &gormPropertyRepository{db: db}
+}
+
+// inProperty adalah scope untuk data milik satu properti (propertyID 0 = semua properti)
+func inProperty(propertyID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if propertyID == 0 {
+			return db
+		}
+		return db.Where("property_id = ?", propertyID)
+	}
+}
+
+func (r *gormPropertyRepository) Create(property *models.Property) error {
+	return r.db.Create(property).Error
+}
+
+func (r *gormPropertyRepository) Update(property *models.Property) error {
+	// Omit gedung: dikelola lewat method gedung
+	return r.db.Omit("Buildings").Save(property).Error
+}
+
+func (r *gormPropertyRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Property{}, id).Error
+}
+
+func (r *gormPropertyRepository) FindByID(id uint) (*models.Property, error) {
+	var property models.Property
+	err := r.db.Preload("Buildings", func(db *gorm.DB) *gorm.DB {
+		return db.Order("code asc")
+	}).First(&property, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &property, nil
+}
+
+func (r *gormPropertyRepository) FindAll(city string, activeOnly bool, pagination *models.Pagination) ([]models.Property, error) {
+	var properties []models.Property
+	query := r.db.Order(pagination.Sort)
+
+	if city != "" {
+		query = query.Where("city = ?", city)
+	}
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	if pagination.Limit > 0 {
+		query = query.Limit(pagination.Limit).Offset(pagination.Offset)
+	}
+
+	if err := query.Find(&properties).Error; err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
+func (r *gormPropertyRepository) CreateBuilding(building *models.Building) error {
+	return r.db.Create(building).Error
+}
+
+func (r *gormPropertyRepository) UpdateBuilding(building *models.Building) error {
+	return r.db.Save(building).Error
+}
+
+func (r *gormPropertyRepository) DeleteBuilding(propertyID, buildingID uint) error {
+	result := r.db.Where("property_id = ?", propertyID).Delete(&models.Building{}, buildingID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *gormPropertyRepository) FindBuilding(propertyID, buildingID uint) (*models.Building, error) {
+	var building models.Building
+	if err := r.db.Where("property_id = ?", propertyID).First(&building, buildingID).Error; err != nil {
+		return nil, err
+	}
+	return &building, nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormRatePlanRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRatePlanRepository(db *gorm.DB) repositories.RatePlanRepository {
+	return &gormRatePlanRepository{db: db}
+}
+
+// withComponents memuat seluruh komponen plan. Musim diurutkan dari yang paling baru dimulai
+// agar musim yang lebih spesifik/terbaru menang bila rentangnya bertumpuk.
+func withComponents(db *gorm.DB) *gorm.DB {
+	return db.Preload("Seasons", func(db *gorm.DB) *gorm.DB {
+		return db.Order("start_date desc")
+	}).Preload("DatePrices").Preload("Blackouts").Preload("CancellationPolicy.Rules", cancellationRulesOrdered)
+}
+
+func (r *gormRatePlanRepository) Create(plan *models.RatePlan) error {
+	return r.db.Create(plan).Error
+}
+
+func (r *gormRatePlanRepository) Update(plan *models.RatePlan) error {
+	// Omit asosiasi: komponen plan dikelola lewat method masing-masing
+	return r.db.Omit(clause.Associations).Save(plan).Error
+}
+
+func (r *gormRatePlanRepository) Delete(id uint) error {
+	return r.db.Delete(&models.RatePlan{}, id).Error
+}
+
+func (r *gormRatePlanRepository) FindByID(id uint) (*models.RatePlan, error) {
+	var plan models.RatePlan
+	if err := r.db.Scopes(withComponents).Preload("RoomType").First(&plan, id).Error; err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *gormRatePlanRepository) FindAll(roomTypeID, propertyID uint, pagination *models.Pagination) ([]models.RatePlan, error) {
+	var plans []models.RatePlan
+	query := r.db.Order(pagination.Sort)
+
+	if roomTypeID > 0 {
+		query = query.Where("room_type_id = ?", roomTypeID)
+	}
+	if propertyID > 0 {
+		// Rate plan dimiliki properti melalui tipe kamarnya
+		query = query.Where("room_type_id IN (?)", r.db.Model(&models.RoomType{}).Select("id").Where("property_id = ?", propertyID))
+	}
+	if pagination.Limit > 0 {
+		query = query.Limit(pagination.Limit).Offset(pagination.Offset)
+	}
+
+	if err := query.Scopes(withComponents).Find(&plans).Error; err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+func (r *gormRatePlanRepository) FindActiveByRoomTypeID(roomTypeID uint) (*models.RatePlan, error) {
+	var plan models.RatePlan
+	err := r.db.Scopes(withComponents).
+		Where("room_type_id = ? AND is_active = ?", roomTypeID, true).
+		Order("priority desc, id desc").
+		First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *gormRatePlanRepository) CreateSeason(season *models.RateSeason) error {
+	return r.db.Create(season).Error
+}
+
+func (r *gormRatePlanRepository) DeleteSeason(ratePlanID, seasonID uint) error {
+	return deleteComponent(r.db, &models.RateSeason{}, ratePlanID, seasonID)
+}
+
+func (r *gormRatePlanRepository) UpsertDatePrice(datePrice *models.RateDatePrice) error {
+	// Satu tanggal hanya punya satu harga per plan (idx_rate_plan_date)
+	return r.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"price"}),
+	}).Create(datePrice).Error
+}
+
+func (r *gormRatePlanRepository) DeleteDatePrice(ratePlanID, datePriceID uint) error {
+	return deleteComponent(r.db, &models.RateDatePrice{}, ratePlanID, datePriceID)
+}
+
+func (r *gormRatePlanRepository) CreateBlackout(blackout *models.RateBlackout) error {
+	return r.db.Create(blackout).Error
+}
+
+func (r *gormRatePlanRepository) DeleteBlackout(ratePlanID, blackoutID uint) error {
+	return deleteComponent(r.db, &models.RateBlackout{}, ratePlanID, blackoutID)
+}
+
+// deleteComponent menghapus komponen plan hanya jika memang milik ratePlanID
+func deleteComponent(db *gorm.DB, model interface{}, ratePlanID, id uint) error {
+	result := db.Where("id = ? AND rate_plan_id = ?", id, ratePlanID).Delete(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
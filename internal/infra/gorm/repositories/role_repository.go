@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+type gormRoleRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRoleRepository(db *gorm.DB) repositories.RoleRepository {
+	return &gormRoleRepository{db: db}
+}
+
+func (r *gormRoleRepository) Create(role *models.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(role).Error; err != nil {
+			return err
+		}
+		return replaceRolePermissions(tx, role)
+	})
+}
+
+func (r *gormRoleRepository) Update(role *models.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(role).Error; err != nil {
+			return err
+		}
+		return replaceRolePermissions(tx, role)
+	})
+}
+
+// replaceRolePermissions mengganti isi role_permissions role dengan role.Permissions
+func replaceRolePermissions(tx *gorm.DB, role *models.Role) error {
+	if err := tx.Where("role_id = ?", role.ID).Delete(&models.RolePermission{}).Error; err != nil {
+		return err
+	}
+	if len(role.Permissions) == 0 {
+		return nil
+	}
+	grants := make([]models.RolePermission, len(role.Permissions))
+	for i, permission := range role.Permissions {
+		grants[i] = models.RolePermission{RoleID: role.ID, Permission: permission}
+	}
+	return tx.Create(&grants).Error
+}
+
+func (r *gormRoleRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		// Hapus permanen agar nama role bisa dipakai lagi (unique per tenant)
+		return tx.Unscoped().Delete(&models.Role{}, id).Error
+	})
+}
+
+func (r *gormRoleRepository) FindByID(id uint) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.First(&role, id).Error; err != nil {
+		return nil, err
+	}
+	if err := loadRolePermissions(r.db, []*models.Role{&role}); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *gormRoleRepository) FindByName(name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	if err := loadRolePermissions(r.db, []*models.Role{&role}); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *gormRoleRepository) FindAll() ([]models.Role, error) {
+	var roles []models.Role
+	if err := r.db.Order("is_system desc, name asc").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	refs := make([]*models.Role, len(roles))
+	for i := range roles {
+		refs[i] = &roles[i]
+	}
+	if err := loadRolePermissions(r.db, refs); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// loadRolePermissions mengisi Permissions setiap role dari role_permissions dalam satu query
+func loadRolePermissions(db *gorm.DB, roles []*models.Role) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	byID := make(map[uint]*models.Role, len(roles))
+	ids := make([]uint, len(roles))
+	for i, role := range roles {
+		role.Permissions = []string{}
+		byID[role.ID] = role
+		ids[i] = role.ID
+	}
+
+	var grants []models.RolePermission
+	if err := db.Where("role_id IN ?", ids).Order("permission asc").Find(&grants).Error; err != nil {
+		return err
+	}
+	for _, grant := range grants {
+		byID[grant.RoleID].Permissions = append(byID[grant.RoleID].Permissions, grant.Permission)
+	}
+	return nil
+}
+
+func (r *gormRoleRepository) CountUsers(name string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
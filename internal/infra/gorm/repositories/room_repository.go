@@ -3,7 +3,9 @@ package repositories
 import (
 	"backend/internal/domain/models"
 	"backend/internal/domain/repositories"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type gormRoomRepository struct {
@@ -28,40 +30,153 @@ func (r *gormRoomRepository) Delete(id uint) error {
 
 func (r *gormRoomRepository) FindByID(id uint) (*models.Room, error) {
 	var room models.Room
-	// Preload Images untuk Fitur Galeri Foto
-	if err := r.db.Preload("Images").First(&room, id).Error; err != nil {
+	// Preload Images untuk Fitur Galeri Foto, tipe kamar, lokasi & fasilitasnya
+	if err := r.db.Preload("RoomType").Preload("Building").Preload("Images").Preload("Amenities", amenitiesOrdered).First(&room, id).Error; err != nil {
 		return nil, err
 	}
 	return &room, nil
 }
 
-func (r *gormRoomRepository) FindAll(pagination *models.Pagination) ([]models.Room, error) {
+func (r *gormRoomRepository) LockByID(id uint) (*models.Room, error) {
+	var room models.Room
+	// Lock baris kamar agar pemesanan paralel untuk kamar yang sama berjalan berurutan
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&room, id).Error; err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// roomsIn adalah scope kamar di properti/kota filter yang tipenya dapat menampung okupansi filter
+func (r *gormRoomRepository) roomsIn(filter models.RoomFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(inProperty(filter.PropertyID))
+		if filter.City != "" {
+			db = db.Where("property_id IN (?)", r.db.Model(&models.Property{}).Select("id").Where("city = ?", filter.City))
+		}
+		if filter.Occupancy > 0 {
+			db = db.Where("room_type_id IN (?)", r.db.Model(&models.RoomType{}).Select("id").Where("max_occupancy >= ?", filter.Occupancy))
+		}
+		return db
+	}
+}
+
+// roomsMatching adalah scope roomsIn ditambah filter tipe kamar, rentang harga, dan fasilitas
+func (r *gormRoomRepository) roomsMatching(filter models.RoomFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(r.roomsIn(filter))
+		if filter.RoomTypeID > 0 {
+			db = db.Where("room_type_id = ?", filter.RoomTypeID)
+		}
+		if filter.MinPrice.IsPositive() || filter.MaxPrice.IsPositive() {
+			types := r.db.Model(&models.RoomType{}).Select("id")
+			if filter.MinPrice.IsPositive() {
+				types = types.Where("price >= ?", filter.MinPrice.Amount)
+			}
+			if filter.MaxPrice.IsPositive() {
+				types = types.Where("price <= ?", filter.MaxPrice.Amount)
+			}
+			db = db.Where("room_type_id IN (?)", types)
+		}
+		if len(filter.Amenities) > 0 {
+			// Kamar harus memiliki semua fasilitas yang diminta
+			withAll := r.db.Table("room_amenities").
+				Select("room_amenities.room_id").
+				Joins("JOIN amenities ON amenities.id = room_amenities.amenity_id AND amenities.deleted_at IS NULL").
+				Where("amenities.code IN ?", filter.Amenities).
+				Group("room_amenities.room_id").
+				Having("COUNT(DISTINCT amenities.id) = ?", len(filter.Amenities))
+			db = db.Where("id IN (?)", withAll)
+		}
+		return db
+	}
+}
+
+func (r *gormRoomRepository) FindAll(filter models.RoomFilter, pagination *models.Pagination) ([]models.Room, error) {
 	var rooms []models.Room
-	query := r.db.Limit(pagination.Limit).Offset(pagination.Offset).Order(pagination.Sort)
-	
-	if err := query.Preload("Images").Find(&rooms).Error; err != nil {
+	query := r.db.Scopes(r.roomsMatching(filter)).Order(pagination.Sort)
+
+	if pagination.Limit > 0 {
+		query = query.Limit(pagination.Limit).Offset(pagination.Offset)
+	}
+
+	err := query.Preload("RoomType").Preload("Building").Preload("Images").Preload("Amenities", amenitiesOrdered).Find(&rooms).Error
+	if err != nil {
 		return nil, err
 	}
 	return rooms, nil
 }
 
-func (r *gormRoomRepository) FindAvailable(checkInDate, checkOutDate string, pagination *models.Pagination) ([]models.Room, error) {
-	var availableRooms []models.Room
+func (r *gormRoomRepository) Facets(filter models.RoomFilter) (models.SearchFacets, error) {
+	// Filter tipe kamar, harga, dan fasilitas diterapkan di memori agar facet dimensinya tetap terhitung
+	var rooms []models.Room
+	err := r.db.Select("id", "room_type_id").Scopes(r.roomsIn(filter)).
+		Preload("RoomType").Preload("Amenities", amenitiesOrdered).
+		Find(&rooms).Error
+	if err != nil {
+		return models.SearchFacets{}, err
+	}
+
+	items := make([]models.SearchItem, 0, len(rooms))
+	for _, room := range rooms {
+		if room.RoomType == nil {
+			continue
+		}
+		items = append(items, models.SearchItem{RoomType: *room.RoomType, Amenities: room.Amenities})
+	}
+	return models.BuildFacets(items, filter), nil
+}
+
+func (r *gormRoomRepository) SetAmenities(roomID uint, amenityIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("room_id = ?", roomID).Delete(&models.RoomAmenity{}).Error; err != nil {
+			return err
+		}
+		if len(amenityIDs) == 0 {
+			return nil
+		}
+		links := make([]models.RoomAmenity, len(amenityIDs))
+		for i, amenityID := range amenityIDs {
+			links[i] = models.RoomAmenity{RoomID: roomID, AmenityID: amenityID}
+		}
+		return tx.Create(&links).Error
+	})
+}
+
+func (r *gormRoomRepository) CountByRoomType(roomTypeID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Room{}).Where("room_type_id = ?", roomTypeID).Count(&count).Error
+	return count, err
+}
 
-	// Subquery untuk mencari Room ID yang sudah dibooking pada periode tertentu
-	subQuery := r.db.Model(&models.Booking{}).
+func (r *gormRoomRepository) CountByBuilding(buildingID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Room{}).Where("building_id = ?", buildingID).Count(&count).Error
+	return count, err
+}
+
+func (r *gormRoomRepository) CountSellable(roomTypeID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Room{}).
+		Where("room_type_id = ? AND status <> ?", roomTypeID, "maintenance").
+		Count(&count).Error
+	return count, err
+}
+
+func (r *gormRoomRepository) FindFreeForStay(roomTypeID uint, checkInDate, checkOutDate string, excludeBookingID uint) ([]models.Room, error) {
+	var rooms []models.Room
+
+	// Subquery untuk mencari kamar fisik yang sudah ditempati booking lain pada periode tersebut
+	occupied := r.db.Model(&models.RoomNight{}).
 		Select("room_id").
-		Where("check_out_date > ? AND check_in_date < ?", checkInDate, checkOutDate).
-		Where("booking_status IN (?)", []string{models.StatusConfirmed, models.StatusPaid}) 
-
-	// Query utama: Kamar yang ID-nya TIDAK ADA di hasil sub-query, dan statusnya 'available'
-	query := r.db.Limit(pagination.Limit).Offset(pagination.Offset).Order(pagination.Sort)
-	
-	if err := query.Preload("Images").
-		Where("id NOT IN (?)", subQuery).
-		Where("status = ?", "available").
-		Find(&availableRooms).Error; err != nil {
+		Where("date >= ? AND date < ?", checkInDate, checkOutDate).
+		Where("booking_id <> ?", excludeBookingID)
+
+	err := r.db.Where("room_type_id = ? AND status <> ?", roomTypeID, "maintenance").
+		Where("id NOT IN (?)", occupied).
+		Order("room_number asc").
+		Find(&rooms).Error
+	if err != nil {
 		return nil, err
 	}
-	return availableRooms, nil
-}
\ No newline at end of file
+	return rooms, nil
+}
@@ -0,0 +1,252 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormRoomTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRoomTypeRepository(db *gorm.DB) repositories.RoomTypeRepository {
+	return &gormRoomTypeRepository{db: db}
+}
+
+func (r *gormRoomTypeRepository) Create(roomType *models.RoomType) error {
+	return r.db.Create(roomType).Error
+}
+
+func (r *gormRoomTypeRepository) Update(roomType *models.RoomType) error {
+	return r.db.Save(roomType).Error
+}
+
+func (r *gormRoomTypeRepository) Delete(id uint) error {
+	return r.db.Delete(&models.RoomType{}, id).Error
+}
+
+func (r *gormRoomTypeRepository) FindByID(id uint) (*models.RoomType, error) {
+	var roomType models.RoomType
+	if err := r.db.Preload("CancellationPolicy.Rules", cancellationRulesOrdered).First(&roomType, id).Error; err != nil {
+		return nil, err
+	}
+	return &roomType, nil
+}
+
+func (r *gormRoomTypeRepository) LockByID(id uint) (*models.RoomType, error) {
+	var roomType models.RoomType
+	// Lock baris tipe kamar agar pemesanan paralel untuk tipe yang sama berjalan berurutan
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&roomType, id).Error; err != nil {
+		return nil, err
+	}
+	return &roomType, nil
+}
+
+// matchingProperties adalah scope tipe kamar milik properti aktif yang sesuai filter
+func (r *gormRoomTypeRepository) matchingProperties(filter models.PropertyFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		properties := r.db.Model(&models.Property{}).Select("id").Where("is_active = ?", true)
+		if filter.PropertyID > 0 {
+			properties = properties.Where("id = ?", filter.PropertyID)
+		}
+		if filter.City != "" {
+			properties = properties.Where("city = ?", filter.City)
+		}
+		return db.Where("property_id IN (?)", properties).Preload("Property")
+	}
+}
+
+func (r *gormRoomTypeRepository) FindAll(filter models.PropertyFilter, pagination *models.Pagination) ([]models.RoomType, error) {
+	var roomTypes []models.RoomType
+	query := r.db.Scopes(r.matchingProperties(filter)).Order(pagination.Sort)
+
+	if pagination.Limit > 0 {
+		query = query.Limit(pagination.Limit).Offset(pagination.Offset)
+	}
+
+	if err := query.Find(&roomTypes).Error; err != nil {
+		return nil, err
+	}
+	return roomTypes, nil
+}
+
+func (r *gormRoomTypeRepository) FindAvailable(checkInDate, checkOutDate string, filter models.RoomFilter, party models.Party, pagination *models.Pagination, now time.Time) ([]models.RoomType, models.SearchFacets, error) {
+	var facets models.SearchFacets
+	checkIn, err := time.Parse("2006-01-02", checkInDate)
+	if err != nil {
+		return nil, facets, err
+	}
+	checkOut, err := time.Parse("2006-01-02", checkOutDate)
+	if err != nil {
+		return nil, facets, err
+	}
+
+	var roomTypes []models.RoomType
+	query := r.db.Scopes(r.matchingProperties(filter.PropertyFilter)).Order(pagination.Sort)
+	if !party.IsZero() {
+		// Kapasitas total dan batas dewasa (0 = tidak dibatasi selain kapasitas total)
+		query = query.Where("room_types.max_occupancy >= ? AND (room_types.max_adults = 0 OR room_types.max_adults >= ?)",
+			party.Total(), party.Adults)
+	}
+	if filter.Occupancy > 0 {
+		query = query.Where("room_types.max_occupancy >= ?", filter.Occupancy)
+	}
+	if err := query.Find(&roomTypes).Error; err != nil {
+		return nil, facets, err
+	}
+	if len(roomTypes) == 0 {
+		return roomTypes, models.BuildFacets(nil, filter), nil
+	}
+	typeIDs := make([]uint, len(roomTypes))
+	for i, roomType := range roomTypes {
+		typeIDs[i] = roomType.ID
+	}
+
+	// Jumlah kamar fisik yang bisa dijual per tipe
+	var counts []struct {
+		RoomTypeID uint
+		Total      int
+	}
+	err = r.db.Model(&models.Room{}).
+		Select("room_type_id, COUNT(*) AS total").
+		Where("room_type_id IN ? AND status <> ?", typeIDs, "maintenance").
+		Group("room_type_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, facets, err
+	}
+	sellable := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		sellable[count.RoomTypeID] = count.Total
+	}
+
+	// Booking aktif yang beririsan dengan periode, dikelompokkan per tipe kamar
+	var bookings []models.Booking
+	err = r.db.Select("id", "room_type_id", "check_in_date", "check_out_date").
+		Scopes(activeBookings(now)).
+		Where("room_type_id IN ?", typeIDs).
+		Where("check_out_date > ? AND check_in_date < ?", checkInDate, checkOutDate).
+		Find(&bookings).Error
+	if err != nil {
+		return nil, facets, err
+	}
+	overlapping := make(map[uint][]models.Booking)
+	for _, booking := range bookings {
+		overlapping[booking.RoomTypeID] = append(overlapping[booking.RoomTypeID], booking)
+	}
+
+	amenities, err := r.sharedAmenities(typeIDs, sellable)
+	if err != nil {
+		return nil, facets, err
+	}
+
+	// Batasan masa inap (termasuk tanggal check-out untuk closed-to-departure)
+	restrictions, err := stayRestrictionsFor(r.db, typeIDs, checkInDate, checkOutDate)
+	if err != nil {
+		return nil, facets, err
+	}
+	rules := models.GroupStayRules(restrictions)
+
+	// Facet dihitung dari semua tipe yang tersedia, filter tipe/harga/fasilitas diterapkan sesudahnya
+	items := make([]models.SearchItem, 0, len(roomTypes))
+	for _, roomType := range roomTypes {
+		// Tipe yang batasannya dilanggar tidak ditampilkan; kamar yang batasannya dilanggar mengurangi sisa
+		if rules[roomType.ID].ForRoomType().Check(checkIn, checkOut, now) != nil {
+			continue
+		}
+		restricted, _ := rules[roomType.ID].CheckRooms(checkIn, checkOut, now)
+		remaining := min(models.RemainingRooms(sellable[roomType.ID], overlapping[roomType.ID], checkIn, checkOut),
+			sellable[roomType.ID]-restricted)
+		if remaining <= 0 {
+			continue
+		}
+		roomType.Remaining = &remaining
+		roomType.Amenities = amenities[roomType.ID]
+		if roomType.Amenities == nil {
+			roomType.Amenities = []models.Amenity{}
+		}
+		items = append(items, models.SearchItem{RoomType: roomType, Amenities: roomType.Amenities})
+	}
+	facets = models.BuildFacets(items, filter)
+
+	available := make([]models.RoomType, 0, len(items))
+	for _, item := range items {
+		if (filter.RoomTypeID == 0 || item.RoomType.ID == filter.RoomTypeID) &&
+			filter.PriceMatches(item.RoomType.Price) && filter.HasAmenities(item.Amenities) {
+			available = append(available, item.RoomType)
+		}
+	}
+
+	// Pagination dilakukan setelah penyaringan agar setiap halaman hanya berisi tipe yang tersedia
+	if pagination.Limit > 0 {
+		if pagination.Offset >= len(available) {
+			return []models.RoomType{}, facets, nil
+		}
+		end := pagination.Offset + pagination.Limit
+		if end > len(available) {
+			end = len(available)
+		}
+		available = available[pagination.Offset:end]
+	}
+	return available, facets, nil
+}
+
+// sharedAmenities mengembalikan fasilitas yang dimiliki semua kamar yang bisa dijual per tipe kamar,
+// karena kamar fisik baru ditetapkan saat check-in sehingga hanya fasilitas bersama yang terjamin
+func (r *gormRoomTypeRepository) sharedAmenities(typeIDs []uint, sellable map[uint]int) (map[uint][]models.Amenity, error) {
+	var counts []struct {
+		RoomTypeID uint
+		AmenityID  uint
+		Total      int
+	}
+	err := r.db.Model(&models.Room{}).
+		Select("rooms.room_type_id, room_amenities.amenity_id, COUNT(*) AS total").
+		Joins("JOIN room_amenities ON room_amenities.room_id = rooms.id").
+		Where("rooms.room_type_id IN ? AND rooms.status <> ?", typeIDs, "maintenance").
+		Group("rooms.room_type_id, room_amenities.amenity_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	shared := make(map[uint][]uint)
+	var amenityIDs []uint
+	for _, count := range counts {
+		if count.Total == sellable[count.RoomTypeID] {
+			shared[count.RoomTypeID] = append(shared[count.RoomTypeID], count.AmenityID)
+			amenityIDs = append(amenityIDs, count.AmenityID)
+		}
+	}
+	var catalogue []models.Amenity
+	if len(amenityIDs) > 0 {
+		if err := r.db.Scopes(amenitiesOrdered).Where("id IN ?", amenityIDs).Find(&catalogue).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	result := make(map[uint][]models.Amenity, len(shared))
+	for typeID, ids := range shared {
+		owned := make(map[uint]bool, len(ids))
+		for _, id := range ids {
+			owned[id] = true
+		}
+		amenities := []models.Amenity{}
+		for _, amenity := range catalogue {
+			if owned[amenity.ID] {
+				amenities = append(amenities, amenity)
+			}
+		}
+		result[typeID] = amenities
+	}
+	return result, nil
+}
+
+func (r *gormRoomTypeRepository) CountByProperty(propertyID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.RoomType{}).Where("property_id = ?", propertyID).Count(&count).Error
+	return count, err
+}
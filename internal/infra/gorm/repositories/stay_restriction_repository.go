@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+type gormStayRestrictionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormStayRestrictionRepository(db *gorm.DB) repositories.StayRestrictionRepository {
+	return &gormStayRestrictionRepository{db: db}
+}
+
+func (r *gormStayRestrictionRepository) Create(restriction *models.StayRestriction) error {
+	return r.db.Create(restriction).Error
+}
+
+func (r *gormStayRestrictionRepository) Update(restriction *models.StayRestriction) error {
+	return r.db.Omit("Room").Save(restriction).Error
+}
+
+func (r *gormStayRestrictionRepository) Delete(id uint) error {
+	return r.db.Delete(&models.StayRestriction{}, id).Error
+}
+
+func (r *gormStayRestrictionRepository) FindByID(id uint) (*models.StayRestriction, error) {
+	var restriction models.StayRestriction
+	if err := r.db.First(&restriction, id).Error; err != nil {
+		return nil, err
+	}
+	return &restriction, nil
+}
+
+func (r *gormStayRestrictionRepository) FindAll(roomTypeID, roomID, propertyID uint, pagination *models.Pagination) ([]models.StayRestriction, error) {
+	var restrictions []models.StayRestriction
+	query := r.db.Scopes(inProperty(propertyID)).Order(pagination.Sort)
+
+	if roomTypeID > 0 {
+		query = query.Where("room_type_id = ?", roomTypeID)
+	}
+	if roomID > 0 {
+		query = query.Where("room_id = ?", roomID)
+	}
+	if pagination.Limit > 0 {
+		query = query.Limit(pagination.Limit).Offset(pagination.Offset)
+	}
+
+	if err := query.Find(&restrictions).Error; err != nil {
+		return nil, err
+	}
+	return restrictions, nil
+}
+
+func (r *gormStayRestrictionRepository) FindForStay(roomTypeIDs []uint, from, to string) ([]models.StayRestriction, error) {
+	return stayRestrictionsFor(r.db, roomTypeIDs, from, to)
+}
+
+// stayRestrictionsFor memuat batasan level tipe kamar dan level kamar yang beririsan dengan from..to.
+// Dipakai juga oleh pencarian ketersediaan tipe kamar.
+func stayRestrictionsFor(db *gorm.DB, roomTypeIDs []uint, from, to string) ([]models.StayRestriction, error) {
+	var restrictions []models.StayRestriction
+	if len(roomTypeIDs) == 0 {
+		return restrictions, nil
+	}
+
+	// Batasan kamar hanya relevan untuk kamar yang bisa dijual (maintenance sudah tidak dihitung)
+	rooms := db.Model(&models.Room{}).Select("id").
+		Where("room_type_id IN ? AND status <> ?", roomTypeIDs, "maintenance")
+
+	err := db.Where("room_type_id IN ? OR room_id IN (?)", roomTypeIDs, rooms).
+		Where("start_date <= ? AND end_date >= ?", to, from).
+		Preload("Room", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "room_type_id")
+		}).
+		Order("id asc").
+		Find(&restrictions).Error
+	if err != nil {
+		return nil, err
+	}
+	return restrictions, nil
+}
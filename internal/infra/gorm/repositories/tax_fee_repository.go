@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+type gormTaxFeeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTaxFeeRepository(db *gorm.DB) repositories.TaxFeeRepository {
+	return &gormTaxFeeRepository{db: db}
+}
+
+func (r *gormTaxFeeRepository) Create(taxFee *models.TaxFee) error {
+	return r.db.Create(taxFee).Error
+}
+
+func (r *gormTaxFeeRepository) Update(taxFee *models.TaxFee) error {
+	return r.db.Save(taxFee).Error
+}
+
+func (r *gormTaxFeeRepository) Delete(id uint) error {
+	return r.db.Delete(&models.TaxFee{}, id).Error
+}
+
+func (r *gormTaxFeeRepository) FindByID(id uint) (*models.TaxFee, error) {
+	var taxFee models.TaxFee
+	if err := r.db.First(&taxFee, id).Error; err != nil {
+		return nil, err
+	}
+	return &taxFee, nil
+}
+
+func (r *gormTaxFeeRepository) FindAll(propertyID uint, pagination *models.Pagination) ([]models.TaxFee, error) {
+	var taxFees []models.TaxFee
+	query := r.db.Scopes(inProperty(propertyID)).Order(pagination.Sort)
+
+	if pagination.Limit > 0 {
+		query = query.Limit(pagination.Limit).Offset(pagination.Offset)
+	}
+
+	if err := query.Find(&taxFees).Error; err != nil {
+		return nil, err
+	}
+	return taxFees, nil
+}
+
+func (r *gormTaxFeeRepository) FindActive(propertyID uint) ([]models.TaxFee, error) {
+	var taxFees []models.TaxFee
+	err := r.db.Where("property_id = ? AND is_active = ?", propertyID, true).Order("sort_order ASC, id ASC").Find(&taxFees).Error
+	return taxFees, err
+}
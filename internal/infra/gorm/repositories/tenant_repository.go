@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+type gormTenantRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTenantRepository(db *gorm.DB) repositories.TenantRepository {
+	return &gormTenantRepository{db: db}
+}
+
+func (r *gormTenantRepository) FindByCode(code string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	if err := r.db.Where("code = ? AND is_active = ?", code, true).First(&tenant).Error; err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+func (r *gormTenantRepository) FindByDomain(domain string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	if err := r.db.Where("domain = ? AND is_active = ?", domain, true).First(&tenant).Error; err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
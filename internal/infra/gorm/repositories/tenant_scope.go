@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// tenantContextKey menyimpan ID tenant di context koneksi GORM
+type tenantContextKey struct{}
+
+// tenantScopedSetting menandai statement yang sudah diberi kondisi tenant, agar query yang
+// dieksekusi ulang (mis. Count lalu Find pada builder yang sama) tidak mendapat kondisi ganda
+const tenantScopedSetting = "tenant:scoped"
+
+// WithTenant mengikat koneksi ke satu tenant. Semua repository yang dibuat dari koneksi ini
+// (termasuk transaksi turunannya) hanya membaca & menulis data tenant tersebut.
+func WithTenant(db *gorm.DB, tenantID uint) *gorm.DB {
+	return db.WithContext(context.WithValue(db.Statement.Context, tenantContextKey{}, tenantID))
+}
+
+// RegisterTenantScope memasang tenant scope pada callback GORM sehingga setiap model yang
+// punya field TenantID otomatis difilter (query, count, update, delete) dan diisi (create)
+// sesuai tenant koneksi. Koneksi tanpa tenant (migrasi, job latar belakang) tidak difilter.
+func RegisterTenantScope(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:before_create").Register("tenant:create", stampTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scopeToTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", scopeToTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:before_update").Register("tenant:update", func(db *gorm.DB) {
+		stampTenant(db)
+		scopeToTenant(db)
+	}); err != nil {
+		return err
+	}
+	return callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scopeToTenant)
+}
+
+// tenantField mengembalikan field TenantID model statement dan tenant koneksi,
+// ok false jika model tidak dimiliki tenant atau koneksi tidak terikat tenant
+func tenantField(db *gorm.DB) (field *schema.Field, tenantID uint, ok bool) {
+	if db.Statement.Schema == nil {
+		return nil, 0, false
+	}
+	field = db.Statement.Schema.LookUpField("TenantID")
+	if field == nil {
+		return nil, 0, false
+	}
+	tenantID, ok = db.Statement.Context.Value(tenantContextKey{}).(uint)
+	return field, tenantID, ok
+}
+
+// scopeToTenant menambahkan kondisi tenant_id pada tabel utama statement
+func scopeToTenant(db *gorm.DB) {
+	field, tenantID, ok := tenantField(db)
+	if !ok {
+		return
+	}
+	if _, scoped := db.Statement.Settings.Load(tenantScopedSetting); scoped {
+		return
+	}
+	db.Statement.Settings.Store(tenantScopedSetting, true)
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenantID},
+	}})
+}
+
+// stampTenant mengisi TenantID data yang disimpan dengan tenant koneksi, sehingga data tidak
+// dapat dibuat atau dipindahkan ke tenant lain meskipun struct berisi TenantID berbeda
+func stampTenant(db *gorm.DB) {
+	field, tenantID, ok := tenantField(db)
+	if !ok {
+		return
+	}
+
+	// Save() atas record yang tidak ditemukan di tenant ini jatuh ke upsert berdasarkan primary key
+	// (ON CONFLICT UpdateAll), yang dapat menimpa record milik tenant lain dengan ID yang sama
+	if c, exists := db.Statement.Clauses["ON CONFLICT"]; exists {
+		if onConflict, isOnConflict := c.Expression.(clause.OnConflict); isOnConflict && onConflict.UpdateAll {
+			db.AddError(gorm.ErrRecordNotFound)
+			return
+		}
+	}
+
+	value := db.Statement.ReflectValue
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			db.AddError(field.Set(db.Statement.Context, reflect.Indirect(value.Index(i)), tenantID))
+		}
+	case reflect.Struct:
+		db.AddError(field.Set(db.Statement.Context, value, tenantID))
+	}
+}
@@ -0,0 +1,286 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/internal/infra/database/mysql/mysqltest"
+	"backend/internal/infra/gorm/repositories"
+
+	"gorm.io/gorm"
+)
+
+// TestTenantScopeIsolation memastikan koneksi WithTenant(A) tidak dapat membaca, mengubah,
+// menghapus, maupun menimpa (Save upsert) data milik tenant B
+func TestTenantScopeIsolation(t *testing.T) {
+	root := mysqltest.Open(t)
+	tenantA := mysqltest.CreateTenant(t, root, "alpha")
+	tenantB := mysqltest.CreateTenant(t, root, "beta")
+	dbA := repositories.WithTenant(root, tenantA)
+
+	propertyA := &models.Property{Code: "A1", Name: "Alpha Jakarta", IsActive: true}
+	mysqltest.Create(t, dbA, propertyA)
+	propertyB := &models.Property{Code: "B1", Name: "Beta Bandung", IsActive: true}
+	mysqltest.Create(t, repositories.WithTenant(root, tenantB), propertyB)
+
+	// assertUntouched membaca ulang properti tenant B tanpa tenant scope
+	assertUntouched := func(t *testing.T) {
+		t.Helper()
+		var stored models.Property
+		if err := root.First(&stored, propertyB.ID).Error; err != nil {
+			t.Fatalf("properti tenant B hilang: %v", err)
+		}
+		if stored.TenantID != tenantB || stored.Name != propertyB.Name {
+			t.Fatalf("properti tenant B berubah: tenant %d, nama %q", stored.TenantID, stored.Name)
+		}
+	}
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T)
+	}{
+		{
+			name: "Find hanya data tenant sendiri",
+			run: func(t *testing.T) {
+				var properties []models.Property
+				if err := dbA.Find(&properties).Error; err != nil {
+					t.Fatal(err)
+				}
+				if len(properties) != 1 || properties[0].ID != propertyA.ID {
+					t.Fatalf("ingin hanya properti %d, dapat %+v", propertyA.ID, properties)
+				}
+			},
+		},
+		{
+			name: "First pada ID tenant lain tidak ditemukan",
+			run: func(t *testing.T) {
+				var property models.Property
+				if err := dbA.First(&property, propertyB.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+					t.Fatalf("ingin ErrRecordNotFound, dapat %v", err)
+				}
+			},
+		},
+		{
+			name: "Count hanya data tenant sendiri",
+			run: func(t *testing.T) {
+				var count int64
+				if err := dbA.Model(&models.Property{}).Count(&count).Error; err != nil {
+					t.Fatal(err)
+				}
+				if count != 1 {
+					t.Fatalf("ingin 1 properti, dapat %d", count)
+				}
+			},
+		},
+		{
+			name: "Update pada ID tenant lain tidak berpengaruh",
+			run: func(t *testing.T) {
+				result := dbA.Model(&models.Property{}).Where("id = ?", propertyB.ID).Update("name", "Diambil alih")
+				if result.Error != nil || result.RowsAffected != 0 {
+					t.Fatalf("ingin 0 baris berubah, dapat %d (%v)", result.RowsAffected, result.Error)
+				}
+				assertUntouched(t)
+			},
+		},
+		{
+			name: "Delete pada ID tenant lain tidak berpengaruh",
+			run: func(t *testing.T) {
+				result := dbA.Delete(&models.Property{}, propertyB.ID)
+				if result.Error != nil || result.RowsAffected != 0 {
+					t.Fatalf("ingin 0 baris terhapus, dapat %d (%v)", result.RowsAffected, result.Error)
+				}
+				assertUntouched(t)
+			},
+		},
+		{
+			name: "Save upsert atas ID tenant lain ditolak",
+			run: func(t *testing.T) {
+				hijacked := *propertyB
+				hijacked.Name = "Diambil alih"
+				if err := dbA.Save(&hijacked).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+					t.Fatalf("ingin ErrRecordNotFound, dapat %v", err)
+				}
+				assertUntouched(t)
+			},
+		},
+		{
+			name: "Create memakai tenant koneksi",
+			run: func(t *testing.T) {
+				property := &models.Property{TenantID: tenantB, Code: "A2", Name: "Alpha Surabaya", IsActive: true}
+				mysqltest.Create(t, dbA, property)
+				var stored models.Property
+				if err := root.First(&stored, property.ID).Error; err != nil {
+					t.Fatal(err)
+				}
+				if stored.TenantID != tenantA {
+					t.Fatalf("properti tersimpan di tenant %d, ingin %d", stored.TenantID, tenantA)
+				}
+				if err := dbA.Delete(property).Error; err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+// tenantFixture adalah data satu tenant: user, booking, dan pembayarannya
+type tenantFixture struct {
+	db      *gorm.DB
+	user    *models.User
+	booking *models.Booking
+	payment *models.Payment
+}
+
+// seedTenant membuat properti, tipe kamar, user, booking, dan pembayaran di tenant tersebut.
+// Username & email user sama di setiap tenant.
+func seedTenant(t *testing.T, root *gorm.DB, code string) *tenantFixture {
+	t.Helper()
+
+	db := repositories.WithTenant(root, mysqltest.CreateTenant(t, root, code))
+	property := &models.Property{Code: "P1", Name: code, IsActive: true}
+	mysqltest.Create(t, db, property)
+	roomType := &models.RoomType{PropertyID: property.ID, Code: "DLX", Name: "Deluxe", Price: money.New(500000, "IDR"), MaxOccupancy: 2}
+	mysqltest.Create(t, db, roomType)
+	user := &models.User{Username: "guest", Password: "x", Email: "guest@example.com", FullName: "Guest", Role: models.RoleMember}
+	mysqltest.Create(t, db, user)
+
+	checkIn := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	booking := &models.Booking{
+		UserID:       user.ID,
+		PropertyID:   property.ID,
+		RoomTypeID:   roomType.ID,
+		CheckInDate:  checkIn,
+		CheckOutDate: checkIn.AddDate(0, 0, 2),
+		TotalPrice:   money.New(1000000, "IDR"),
+		Currency:     "IDR",
+		GuestTotal:   money.New(1000000, "IDR"),
+	}
+	mysqltest.Create(t, db, booking)
+	payment := &models.Payment{
+		BookingID:         booking.ID,
+		Amount:            money.New(1000000, "IDR"),
+		Currency:          "IDR",
+		Method:            "mock",
+		Provider:          "mock",
+		ProviderReference: "pi_" + code,
+	}
+	mysqltest.Create(t, db, payment)
+	return &tenantFixture{db: db, user: user, booking: booking, payment: payment}
+}
+
+// TestTenantScopeRepositories memastikan repository booking, user, dan pembayaran pada koneksi
+// tenant A tidak dapat menemukan data tenant B, serta username & email hanya unik per tenant
+func TestTenantScopeRepositories(t *testing.T) {
+	root := mysqltest.Open(t)
+	alpha := seedTenant(t, root, "alpha")
+	beta := seedTenant(t, root, "beta") // Username & email sama dengan alpha
+
+	bookings := repositories.NewGormBookingRepository(alpha.db)
+	users := repositories.NewGormRepository(alpha.db)
+	payments := repositories.NewGormPaymentRepository(alpha.db)
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T)
+	}{
+		{
+			name: "BookingRepository.FindByID booking tenant lain tidak ditemukan",
+			run: func(t *testing.T) {
+				if _, err := bookings.FindByID(beta.booking.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+					t.Fatalf("ingin ErrRecordNotFound, dapat %v", err)
+				}
+				if booking, err := bookings.FindByID(alpha.booking.ID); err != nil || len(booking.Payments) != 1 {
+					t.Fatalf("booking sendiri: %v, %d pembayaran", err, len(booking.Payments))
+				}
+			},
+		},
+		{
+			name: "BookingRepository.FindByUserID user tenant lain kosong",
+			run: func(t *testing.T) {
+				found, err := bookings.FindByUserID(beta.user.ID, &models.Pagination{Sort: "id asc"})
+				if err != nil || len(found) != 0 {
+					t.Fatalf("ingin 0 booking, dapat %d (%v)", len(found), err)
+				}
+				found, err = bookings.FindByUserID(alpha.user.ID, &models.Pagination{Sort: "id asc"})
+				if err != nil || len(found) != 1 || found[0].ID != alpha.booking.ID {
+					t.Fatalf("ingin hanya booking %d, dapat %+v (%v)", alpha.booking.ID, found, err)
+				}
+			},
+		},
+		{
+			name: "UserRepository.FindByEmail mengembalikan user tenant sendiri",
+			run: func(t *testing.T) {
+				user, err := users.FindByEmail(beta.user.Email)
+				if err != nil || user.ID != alpha.user.ID {
+					t.Fatalf("ingin user %d, dapat %+v (%v)", alpha.user.ID, user, err)
+				}
+			},
+		},
+		{
+			name: "UserRepository.FindByUsername mengembalikan user tenant sendiri",
+			run: func(t *testing.T) {
+				user, err := users.FindByUsername(beta.user.Username)
+				if err != nil || user.ID != alpha.user.ID {
+					t.Fatalf("ingin user %d, dapat %+v (%v)", alpha.user.ID, user, err)
+				}
+			},
+		},
+		{
+			name: "UserRepository.FindByID user tenant lain tidak ditemukan",
+			run: func(t *testing.T) {
+				if _, err := users.FindByID(beta.user.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+					t.Fatalf("ingin ErrRecordNotFound, dapat %v", err)
+				}
+			},
+		},
+		{
+			name: "PaymentRepository.FindByReference pembayaran tenant lain tidak ditemukan",
+			run: func(t *testing.T) {
+				if _, err := payments.FindByReference("mock", beta.payment.ProviderReference); !errors.Is(err, gorm.ErrRecordNotFound) {
+					t.Fatalf("ingin ErrRecordNotFound, dapat %v", err)
+				}
+				if _, err := payments.FindByID(beta.payment.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+					t.Fatalf("ingin ErrRecordNotFound lewat FindByID, dapat %v", err)
+				}
+				payment, err := payments.FindByReference("mock", alpha.payment.ProviderReference)
+				if err != nil || payment.ID != alpha.payment.ID {
+					t.Fatalf("ingin pembayaran %d, dapat %+v (%v)", alpha.payment.ID, payment, err)
+				}
+			},
+		},
+		{
+			name: "Pembayaran tersimpan di tenant booking-nya",
+			run: func(t *testing.T) {
+				var stored models.Payment
+				if err := root.First(&stored, beta.payment.ID).Error; err != nil {
+					t.Fatal(err)
+				}
+				if stored.TenantID != beta.booking.TenantID {
+					t.Fatalf("pembayaran di tenant %d, ingin %d", stored.TenantID, beta.booking.TenantID)
+				}
+			},
+		},
+		{
+			name: "Username & email ganda dalam satu tenant ditolak",
+			run: func(t *testing.T) {
+				for _, user := range []*models.User{
+					{Username: alpha.user.Username, Password: "x", Email: "other@example.com", FullName: "Other"},
+					{Username: "other", Password: "x", Email: alpha.user.Email, FullName: "Other"},
+				} {
+					if err := alpha.db.Create(user).Error; err == nil {
+						t.Fatalf("user ganda %s/%s tersimpan", user.Username, user.Email)
+					}
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+func NewGormTransactor(db *gorm.DB) repositories.Transactor {
+	return &gormTransactor{db: db}
+}
+
+func (t *gormTransactor) WithinTransaction(fn func(tx repositories.TxRepositories) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(repositories.TxRepositories{
+			Rooms:        NewGormRoomRepository(tx),
+			RoomTypes:    NewGormRoomTypeRepository(tx),
+			Bookings:     NewGormBookingRepository(tx),
+			RatePlans:    NewGormRatePlanRepository(tx),
+			Restrictions: NewGormStayRestrictionRepository(tx),
+			Promos:       NewGormPromoCodeRepository(tx),
+			TaxFees:      NewGormTaxFeeRepository(tx),
+			Rates:        NewGormExchangeRateRepository(tx),
+			Webhooks:     NewGormPaymentWebhookEventRepository(tx),
+			Payments:     NewGormPaymentRepository(tx),
+			Policies:     NewGormCancellationPolicyRepository(tx),
+		})
+	})
+}
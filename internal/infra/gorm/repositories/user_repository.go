@@ -43,6 +43,14 @@ func (r *gormUserRepository) FindByUsername(username string) (*models.User, erro
 	return &user, nil
 }
 
+func (r *gormUserRepository) FindByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *gormUserRepository) FindAllMembers(pagination *models.Pagination) ([]models.User, error) {
 	var users []models.User
 
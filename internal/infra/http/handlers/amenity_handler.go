@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/pkg/utils"
+	"errors"
+	"strconv"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AmenityHandler struct {
+	amenityService services.AmenityService
+}
+
+func NewAmenityHandler(amenityService services.AmenityService) *AmenityHandler {
+	return &AmenityHandler{amenityService: amenityService}
+}
+
+type AmenityInput struct {
+	Code      string  `json:"code"` // mis. WIFI, BALCONY, SEA_VIEW
+	Name      string  `json:"name"`
+	Category  *string `json:"category"`
+	SortOrder *int    `json:"sort_order"`
+}
+
+// applyAmenityInput: Menyalin field yang diberikan dari input ke fasilitas
+func applyAmenityInput(amenity *models.Amenity, input AmenityInput) {
+	if input.Code != "" {
+		amenity.Code = input.Code
+	}
+	if input.Name != "" {
+		amenity.Name = input.Name
+	}
+	if input.Category != nil {
+		amenity.Category = *input.Category
+	}
+	if input.SortOrder != nil {
+		amenity.SortOrder = *input.SortOrder
+	}
+}
+
+// respondAmenityError: Memetakan error fasilitas ke HTTP status
+func respondAmenityError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, models.ErrAmenityNotFound) {
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return utils.RespondError(c, fiber.StatusConflict, "Kode fasilitas sudah digunakan")
+	}
+	return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+}
+
+// GetAmenities: Mengambil katalog fasilitas untuk pilihan filter (Public & Admin)
+func (h *AmenityHandler) GetAmenities(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 50)
+
+	pagination := &models.Pagination{
+		Page:   page,
+		Limit:  limit,
+		Sort:   c.Query("sort", "sort_order asc, name asc"),
+		Offset: (page - 1) * limit,
+	}
+
+	amenities, err := h.amenityService.GetAmenities(pagination)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data fasilitas")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data fasilitas", fiber.Map{
+		"amenities": amenities,
+		"page":      page,
+		"limit":     limit,
+	})
+}
+
+// CreateAmenity: Menambah fasilitas ke katalog (Admin Only)
+func (h *AmenityHandler) CreateAmenity(c *fiber.Ctx) error {
+	var input AmenityInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	amenity := &models.Amenity{}
+	applyAmenityInput(amenity, input)
+
+	createdAmenity, err := h.amenityService.CreateAmenity(amenity)
+	if err != nil {
+		return respondAmenityError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Fasilitas berhasil dibuat", createdAmenity)
+}
+
+// UpdateAmenity: Mengubah fasilitas (Admin Only)
+func (h *AmenityHandler) UpdateAmenity(c *fiber.Ctx) error {
+	amenityID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID fasilitas tidak valid")
+	}
+
+	var input AmenityInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	existingAmenity, err := h.amenityService.GetAmenityByID(uint(amenityID))
+	if err != nil {
+		return respondAmenityError(c, err)
+	}
+	applyAmenityInput(existingAmenity, input)
+
+	updatedAmenity, err := h.amenityService.UpdateAmenity(existingAmenity)
+	if err != nil {
+		return respondAmenityError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Fasilitas berhasil diubah", updatedAmenity)
+}
+
+// DeleteAmenity: Menghapus fasilitas dari katalog dan semua kamar (Admin Only)
+func (h *AmenityHandler) DeleteAmenity(c *fiber.Ctx) error {
+	amenityID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID fasilitas tidak valid")
+	}
+
+	if err := h.amenityService.DeleteAmenity(uint(amenityID)); err != nil {
+		return respondAmenityError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Fasilitas berhasil dihapus", nil)
+}
@@ -5,6 +5,7 @@ import (
 	"backend/internal/domain/models"
 	"backend/pkg/utils"
 	"errors"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/gofiber/fiber/v2"
@@ -29,7 +30,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		return utils.RespondError(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	token, user, err := h.authService.Login(input.Username, input.Password)
+	result, err := h.authService.Login(input.Username, input.Password)
 
 	if err != nil {
 		if errors.Is(err, models.ErrRecordNotFound) || errors.Is(err, models.ErrInvalidCredentials) {
@@ -38,10 +39,94 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal login")
 	}
 
-	return utils.RespondSuccess(c, fiber.StatusOK, "Login Berhasil", fiber.Map{
-		"token": token,
-		"user":  user,
-	})
+	// Langkah kedua: kirim kode 2FA bersama mfa_token ke POST /api/auth/login/mfa
+	if result.Challenge != nil {
+		return utils.RespondSuccess(c, fiber.StatusOK, "Masukkan kode autentikasi dua faktor", fiber.Map{
+			"mfa_required":   true,
+			"mfa_token":      result.Challenge.Token,
+			"mfa_expires_at": result.Challenge.ExpiresAt,
+		})
+	}
+
+	response := tokenResponse(result.Tokens)
+	response["user"] = result.User
+	if result.MFAEnrollmentRequired {
+		response["mfa_enrollment_required"] = true
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Login Berhasil", response)
+}
+
+type MFALoginInput struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"` // Kode TOTP 6 digit atau recovery code
+}
+
+// VerifyMFALogin: Langkah kedua login untuk user dengan 2FA aktif
+func (h *AuthHandler) VerifyMFALogin(c *fiber.Ctx) error {
+	var input MFALoginInput
+	if err := c.BodyParser(&input); err != nil || input.MFAToken == "" || input.Code == "" {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	tokens, user, err := h.authService.VerifyMFALogin(input.MFAToken, input.Code)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidAccountToken) {
+			return utils.RespondError(c, fiber.StatusUnauthorized, "Sesi login 2FA tidak valid atau sudah kedaluwarsa, silakan login ulang")
+		}
+		if errors.Is(err, models.ErrInvalidMFACode) || errors.Is(err, models.ErrMFANotEnabled) {
+			return utils.RespondError(c, fiber.StatusUnauthorized, models.ErrInvalidMFACode.Error())
+		}
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal login")
+	}
+
+	response := tokenResponse(tokens)
+	response["user"] = user
+	return utils.RespondSuccess(c, fiber.StatusOK, "Login Berhasil", response)
+}
+
+// tokenResponse: Payload token untuk klien; "token" tetap berisi access token agar klien lama tetap jalan
+func tokenResponse(tokens *models.TokenPair) fiber.Map {
+	return fiber.Map{
+		"token":              tokens.AccessToken,
+		"expires_at":         tokens.AccessExpiresAt,
+		"refresh_token":      tokens.RefreshToken,
+		"refresh_expires_at": tokens.RefreshExpiresAt,
+	}
+}
+
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// Refresh: Menukar refresh token dengan pasangan token baru (refresh token lama tidak berlaku lagi)
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var input RefreshInput
+	if err := c.BodyParser(&input); err != nil || input.RefreshToken == "" {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	tokens, err := h.authService.Refresh(input.RefreshToken)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidRefreshToken) || errors.Is(err, models.ErrRefreshTokenReused) {
+			return utils.RespondError(c, fiber.StatusUnauthorized, err.Error())
+		}
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal memperbarui token")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Token berhasil diperbarui", tokenResponse(tokens))
+}
+
+// Logout: Mencabut access token yang dipakai request ini beserta refresh token sesinya (Login Required)
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+	jti, _ := c.Locals("tokenID").(string)
+	expiresAt, _ := c.Locals("tokenExpiresAt").(time.Time)
+
+	if err := h.authService.Logout(userID, jti, expiresAt); err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal logout")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Logout berhasil", nil)
 }
 
 type RegisterInput struct {
@@ -74,5 +159,83 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 		}
 		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mendaftarkan user")
 	}
-	return utils.RespondSuccess(c, fiber.StatusOK, "Pendaftaran berhasil", nil)
+	return utils.RespondSuccess(c, fiber.StatusOK, "Pendaftaran berhasil, silakan cek email untuk verifikasi", nil)
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ForgotPassword: Mengirim tautan reset password ke email. Respons selalu sama agar keberadaan akun tidak bocor.
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var input ForgotPasswordInput
+	if err := c.BodyParser(&input); err != nil || input.Email == "" {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := h.authService.ForgotPassword(input.Email); err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengirim email reset password")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Jika email terdaftar, tautan reset password sudah dikirim", nil)
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+// ResetPassword: Mengganti password dengan token dari email reset password (semua sesi login dicabut)
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var input ResetPasswordInput
+	if err := c.BodyParser(&input); err != nil || input.Token == "" {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if len(input.Password) < 6 {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Password minimal 6 karakter")
+	}
+
+	if err := h.authService.ResetPassword(input.Token, input.Password); err != nil {
+		if errors.Is(err, models.ErrInvalidAccountToken) {
+			return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+		}
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengganti password")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Password berhasil diganti, silakan login kembali", nil)
+}
+
+// VerifyEmail: Memverifikasi email dengan token dari email verifikasi (?token=)
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Token wajib diisi")
+	}
+
+	user, err := h.authService.VerifyEmail(token)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidAccountToken) {
+			return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+		}
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal memverifikasi email")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Email berhasil diverifikasi", fiber.Map{
+		"email":             user.Email,
+		"email_verified_at": user.EmailVerifiedAt,
+	})
+}
+
+// ResendVerification: Mengirim ulang tautan verifikasi email (Login Required)
+func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+
+	if err := h.authService.ResendVerification(userID); err != nil {
+		if errors.Is(err, models.ErrEmailAlreadyVerified) {
+			return utils.RespondError(c, fiber.StatusConflict, err.Error())
+		}
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengirim email verifikasi")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Tautan verifikasi email sudah dikirim", nil)
 }
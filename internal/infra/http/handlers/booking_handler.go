@@ -20,49 +20,159 @@ func NewBookingHandler(bookingService services.BookingService) *BookingHandler {
 }
 
 type CreateBookingInput struct {
-	RoomID        uint   `json:"room_id" validate:"required"`
+	RoomTypeID    uint   `json:"room_type_id" validate:"required"`
 	CheckInDate   string `json:"check_in_date" validate:"required"`
 	CheckOutDate  string `json:"check_out_date" validate:"required"`
 	PaymentMethod string `json:"payment_method"`
+	Adults        int    `json:"adults"`      // Opsional: default 1 (atau rombongan di quote token)
+	ChildAges     []int  `json:"child_ages"`  // Opsional: usia setiap anak, mis. [4, 9]
+	Guests        int    `json:"guests"`      // Deprecated: gunakan adults; diperlakukan sebagai jumlah dewasa
+	QuoteToken    string `json:"quote_token"` // Opsional: mengunci harga dari POST /api/room-types/:id/quote
+	PromoCode     string `json:"promo_code"`
+	Currency      string `json:"currency"` // Alternatif dari query ?currency=
 }
 
-// CreateBooking: Membuat booking baru (Member Only)
-func (h *BookingHandler) CreateBooking(c *fiber.Ctx) error {
-	// Ambil UserID dari context (dari JWT middleware)
-	userID := c.Locals("userID").(uint)
-
+// parseCreateBookingInput: Parse body CreateBookingInput menjadi models.Booking dan opsi booking
+func parseCreateBookingInput(c *fiber.Ctx, userID uint) (*models.Booking, services.BookingOptions, error) {
 	var input CreateBookingInput
 	if err := c.BodyParser(&input); err != nil {
-		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+		return nil, services.BookingOptions{}, errors.New("Format request tidak valid")
 	}
 
 	// Parse tanggal
-	checkIn, err := time.Parse("2006-01-02", input.CheckInDate)
+	checkIn, checkOut, err := parseStayDates(input.CheckInDate, input.CheckOutDate)
 	if err != nil {
-		return utils.RespondError(c, fiber.StatusBadRequest, "Format tanggal check-in tidak valid (gunakan format YYYY-MM-DD)")
+		return nil, services.BookingOptions{}, err
 	}
-
-	checkOut, err := time.Parse("2006-01-02", input.CheckOutDate)
-	if err != nil {
-		return utils.RespondError(c, fiber.StatusBadRequest, "Format tanggal check-out tidak valid (gunakan format YYYY-MM-DD)")
+	if input.Guests < 0 || input.Adults < 0 {
+		return nil, services.BookingOptions{}, models.ErrInvalidGuestCount
 	}
 
 	booking := &models.Booking{
 		UserID:        userID,
-		RoomID:        input.RoomID,
+		RoomTypeID:    input.RoomTypeID,
 		CheckInDate:   checkIn,
 		CheckOutDate:  checkOut,
 		PaymentMethod: input.PaymentMethod,
 	}
+	booking.SetParty(parseParty(input.Adults, input.ChildAges, input.Guests))
+	opts := services.BookingOptions{
+		QuoteToken: input.QuoteToken,
+		PromoCode:  input.PromoCode,
+		Currency:   c.Query("currency", input.Currency),
+	}
+	return booking, opts, nil
+}
+
+// parseParty: Menyusun rombongan tamu dari input; field lama guests dianggap jumlah dewasa
+func parseParty(adults int, childAges []int, guests int) models.Party {
+	if adults == 0 && len(childAges) == 0 {
+		adults = guests
+	}
+	if childAges == nil {
+		childAges = []int{}
+	}
+	return models.Party{Adults: adults, ChildAges: childAges}
+}
+
+// parseStayDates: Parse tanggal check-in/out format YYYY-MM-DD
+func parseStayDates(checkInDate, checkOutDate string) (time.Time, time.Time, error) {
+	checkIn, err := time.Parse("2006-01-02", checkInDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Format tanggal check-in tidak valid (gunakan format YYYY-MM-DD)")
+	}
+
+	checkOut, err := time.Parse("2006-01-02", checkOutDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Format tanggal check-out tidak valid (gunakan format YYYY-MM-DD)")
+	}
+	return checkIn, checkOut, nil
+}
+
+// respondBookingError: Memetakan error domain pemesanan ke HTTP status
+func respondBookingError(c *fiber.Ctx, err error) error {
+	// Pelanggaran batasan masa inap dikirim beserta kodenya (MIN_STAY, CLOSED_TO_ARRIVAL, dst.)
+	var violation *models.RestrictionViolation
+	if errors.As(err, &violation) {
+		return utils.RespondErrorWithData(c, fiber.StatusUnprocessableEntity, violation.Error(), violation)
+	}
 
-	createdBooking, err := h.bookingService.CreateBooking(booking)
+	switch {
+	case errors.Is(err, models.ErrRecordNotFound):
+		return utils.RespondError(c, fiber.StatusNotFound, "Pemesanan tidak ditemukan")
+	case errors.Is(err, models.ErrRoomNotFound), errors.Is(err, models.ErrRoomTypeNotFound),
+		errors.Is(err, models.ErrPromoNotFound):
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrBookingForbidden), errors.Is(err, models.ErrPropertyAccessDenied),
+		errors.Is(err, models.ErrEmailNotVerified):
+		return utils.RespondError(c, fiber.StatusForbidden, err.Error())
+	case errors.Is(err, models.ErrRoomAlreadyBooked),
+		errors.Is(err, models.ErrNoRoomsAvailable),
+		errors.Is(err, models.ErrRoomUnderMaintenance),
+		errors.Is(err, models.ErrRoomNotAssignable),
+		errors.Is(err, models.ErrInvalidTransition),
+		errors.Is(err, models.ErrHoldExpired),
+		errors.Is(err, models.ErrBookingNotModifiable),
+		errors.Is(err, models.ErrPropertyMismatch),
+		errors.Is(err, models.ErrPromoUsageLimit),
+		errors.Is(err, models.ErrPromoPerUserLimit):
+		return utils.RespondError(c, fiber.StatusConflict, err.Error())
+	}
+	return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+}
+
+// CreateBooking: Membuat booking baru (Member Only)
+func (h *BookingHandler) CreateBooking(c *fiber.Ctx) error {
+	// Ambil UserID dari context (dari JWT middleware)
+	userID := c.Locals("userID").(uint)
+
+	booking, opts, err := parseCreateBookingInput(c, userID)
 	if err != nil {
 		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	createdBooking, err := h.bookingService.CreateBooking(booking, opts)
+	if err != nil {
+		return respondBookingError(c, err)
+	}
+
 	return utils.RespondSuccess(c, fiber.StatusCreated, "Pemesanan berhasil dibuat", createdBooking)
 }
 
+// CreateHold: Menahan kamar sementara saat memulai checkout (Member Only)
+func (h *BookingHandler) CreateHold(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+
+	booking, opts, err := parseCreateBookingInput(c, userID)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	hold, err := h.bookingService.CreateHold(booking, opts)
+	if err != nil {
+		return respondBookingError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Kamar berhasil ditahan", hold)
+}
+
+// FinalizeHold: Mengubah hold menjadi pemesanan (Member Only)
+func (h *BookingHandler) FinalizeHold(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
+	}
+
+	booking, err := h.bookingService.FinalizeHold(uint(bookingID), userID)
+	if err != nil {
+		return respondBookingError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Pemesanan berhasil dibuat", booking)
+}
+
 // GetMyBookings: Mengambil booking saya (Member)
 func (h *BookingHandler) GetMyBookings(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(uint)
@@ -97,17 +207,130 @@ func (h *BookingHandler) CancelBooking(c *fiber.Ctx) error {
 		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
 	}
 
-	if err := h.bookingService.CancelBooking(uint(bookingID), userID); err != nil {
-		if errors.Is(err, errors.New("anda tidak memiliki izin membatalkan pemesanan ini")) {
-			return utils.RespondError(c, fiber.StatusForbidden, "Anda tidak memiliki izin membatalkan pemesanan ini")
+	breakdown, err := h.bookingService.CancelBooking(uint(bookingID), userID)
+	if err != nil {
+		return respondPaymentError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Pemesanan berhasil dibatalkan", breakdown)
+}
+
+// PreviewCancellation: Melihat rincian penalti & refund sebelum membatalkan (Member)
+func (h *BookingHandler) PreviewCancellation(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
+	}
+
+	breakdown, err := h.bookingService.PreviewCancellation(uint(bookingID), userID)
+	if err != nil {
+		return respondBookingError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil menghitung rincian pembatalan", breakdown)
+}
+
+type ModifyBookingInput struct {
+	RoomTypeID   *uint   `json:"room_type_id"`
+	CheckInDate  *string `json:"check_in_date"`
+	CheckOutDate *string `json:"check_out_date"`
+	Adults       *int    `json:"adults"`
+	ChildAges    *[]int  `json:"child_ages"`
+	Guests       *int    `json:"guests"` // Deprecated: gunakan adults; mengganti rombongan menjadi dewasa saja
+}
+
+// parseBookingChanges: Parse ModifyBookingInput menjadi services.BookingChanges
+func parseBookingChanges(input ModifyBookingInput) (services.BookingChanges, error) {
+	changes := services.BookingChanges{RoomTypeID: input.RoomTypeID, Adults: input.Adults, ChildAges: input.ChildAges}
+	if input.Guests != nil && input.Adults == nil && input.ChildAges == nil {
+		noChildren := []int{}
+		changes.Adults, changes.ChildAges = input.Guests, &noChildren
+	}
+	if input.CheckInDate != nil {
+		checkIn, err := time.Parse("2006-01-02", *input.CheckInDate)
+		if err != nil {
+			return changes, errors.New("Format tanggal check-in tidak valid (gunakan format YYYY-MM-DD)")
+		}
+		changes.CheckInDate = &checkIn
+	}
+	if input.CheckOutDate != nil {
+		checkOut, err := time.Parse("2006-01-02", *input.CheckOutDate)
+		if err != nil {
+			return changes, errors.New("Format tanggal check-out tidak valid (gunakan format YYYY-MM-DD)")
 		}
+		changes.CheckOutDate = &checkOut
+	}
+	return changes, nil
+}
+
+// ModifyBooking: Mengubah tanggal, tipe kamar, atau jumlah tamu booking (Member)
+func (h *BookingHandler) ModifyBooking(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
+	}
+
+	var input ModifyBookingInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+	changes, err := parseBookingChanges(input)
+	if err != nil {
 		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	return utils.RespondSuccess(c, fiber.StatusOK, "Pemesanan berhasil dibatalkan", nil)
+	booking, modification, err := h.bookingService.ModifyBooking(uint(bookingID), userID, changes)
+	if err != nil {
+		return respondPaymentError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Pemesanan berhasil diubah", fiber.Map{
+		"booking":      booking,
+		"modification": modification,
+		"outstanding":  booking.PaymentSummary().Outstanding,
+	})
+}
+
+// GetMyBookingModifications: Riwayat perubahan booking milik member (Member)
+func (h *BookingHandler) GetMyBookingModifications(c *fiber.Ctx) error {
+	return h.getBookingModifications(c, c.Locals("userID").(uint))
+}
+
+// GetBookingModifications: Riwayat perubahan booking (Admin Only)
+func (h *BookingHandler) GetBookingModifications(c *fiber.Ctx) error {
+	return h.getBookingModifications(c, 0)
+}
+
+func (h *BookingHandler) getBookingModifications(c *fiber.Ctx, userID uint) error {
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
+	}
+
+	modifications, err := h.bookingService.GetBookingModifications(uint(bookingID), userID)
+	if err != nil {
+		return respondBookingError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil riwayat perubahan pemesanan", modifications)
+}
+
+// RequireBookingAccess: Middleware admin, staf properti hanya boleh mengelola booking propertinya
+func (h *BookingHandler) RequireBookingAccess(c *fiber.Ctx) error {
+	return requirePropertyAccess(c, func(id uint) (uint, error) {
+		booking, err := h.bookingService.GetBookingByID(id)
+		if err != nil {
+			return 0, errors.New("Pemesanan tidak ditemukan")
+		}
+		return booking.PropertyID, nil
+	})
 }
 
-// GetAllBookings: Mengambil semua booking (Admin Only)
+// GetAllBookings: Mengambil semua booking, filter ?property_id= (Admin Only)
 func (h *BookingHandler) GetAllBookings(c *fiber.Ctx) error {
 	page := c.QueryInt("page", 1)
 	limit := c.QueryInt("limit", 10)
@@ -119,7 +342,12 @@ func (h *BookingHandler) GetAllBookings(c *fiber.Ctx) error {
 		Offset: (page - 1) * limit,
 	}
 
-	bookings, err := h.bookingService.GetAllBookings(pagination)
+	propertyID, err := propertyScope(c)
+	if err != nil {
+		return respondBookingError(c, err)
+	}
+
+	bookings, err := h.bookingService.GetAllBookings(propertyID, pagination)
 	if err != nil {
 		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data pemesanan")
 	}
@@ -131,26 +359,152 @@ func (h *BookingHandler) GetAllBookings(c *fiber.Ctx) error {
 	})
 }
 
-type UpdatePaymentStatusInput struct {
-	PaymentStatus string `json:"payment_status" validate:"required"`
+type TransitionBookingInput struct {
+	Note string `json:"note"`
 }
 
-// UpdatePaymentStatus: Mengubah status pembayaran (Admin Only)
-func (h *BookingHandler) UpdatePaymentStatus(c *fiber.Ctx) error {
+// transitionBooking: Helper untuk endpoint perubahan status pemesanan (Admin/Front Desk)
+func (h *BookingHandler) transitionBooking(c *fiber.Ctx, toStatus, successMessage string) error {
+	performedBy := c.Locals("userID").(uint)
+
 	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
 		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
 	}
 
-	var input UpdatePaymentStatusInput
-	if err := c.BodyParser(&input); err != nil {
-		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	// Body bersifat opsional (hanya berisi catatan)
+	var input TransitionBookingInput
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+		}
+	}
+
+	booking, err := h.bookingService.TransitionBooking(uint(bookingID), toStatus, performedBy, input.Note)
+	if err != nil {
+		return respondBookingError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, successMessage, booking)
+}
+
+// ConfirmBooking: pending -> confirmed (Admin Only)
+func (h *BookingHandler) ConfirmBooking(c *fiber.Ctx) error {
+	return h.transitionBooking(c, models.StatusConfirmed, "Pemesanan berhasil dikonfirmasi")
+}
+
+type CheckInInput struct {
+	RoomID uint   `json:"room_id"` // Opsional: kosong = pakai kamar yang sudah ditetapkan / kamar kosong pertama
+	Note   string `json:"note"`
+}
+
+// CheckIn: confirmed -> checked_in sekaligus menetapkan kamar fisik (Admin/Front Desk)
+func (h *BookingHandler) CheckIn(c *fiber.Ctx) error {
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
+	}
+
+	var input CheckInInput
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+		}
+	}
+
+	booking, err := h.bookingService.CheckIn(uint(bookingID), input.RoomID, c.Locals("userID").(uint), input.Note)
+	if err != nil {
+		return respondBookingError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Check-in berhasil", booking)
+}
+
+type AssignRoomInput struct {
+	RoomID uint `json:"room_id"` // Kosong = kamar kosong pertama bertipe sama
+}
+
+// AssignRoom: Menetapkan/memindahkan kamar fisik booking dari room board (Admin/Front Desk)
+func (h *BookingHandler) AssignRoom(c *fiber.Ctx) error {
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
+	}
+
+	var input AssignRoomInput
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+		}
+	}
+
+	booking, err := h.bookingService.AssignRoom(uint(bookingID), input.RoomID)
+	if err != nil {
+		return respondBookingError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Kamar berhasil ditetapkan", booking)
+}
+
+// GetRoomBoard: Papan penempatan kamar per tanggal, ?date=YYYY-MM-DD (default hari ini)
+// dan ?property_id= (Admin/Front Desk)
+func (h *BookingHandler) GetRoomBoard(c *fiber.Ctx) error {
+	propertyID, err := propertyScope(c)
+	if err != nil {
+		return respondBookingError(c, err)
+	}
+
+	date := time.Now()
+	if c.Query("date") != "" {
+		parsed, err := time.Parse("2006-01-02", c.Query("date"))
+		if err != nil {
+			return utils.RespondError(c, fiber.StatusBadRequest, "Format tanggal tidak valid (gunakan format YYYY-MM-DD)")
+		}
+		date = parsed
 	}
 
-	updatedBooking, err := h.bookingService.UpdatePaymentStatus(uint(bookingID), input.PaymentStatus)
+	board, err := h.bookingService.GetRoomBoard(propertyID, date)
 	if err != nil {
-		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengubah status pembayaran")
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil room board")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil room board", board)
+}
+
+// CheckOut: checked_in -> checked_out (Admin/Front Desk)
+func (h *BookingHandler) CheckOut(c *fiber.Ctx) error {
+	return h.transitionBooking(c, models.StatusCheckedOut, "Check-out berhasil")
+}
+
+// CompleteBooking: checked_out -> completed (Admin Only)
+func (h *BookingHandler) CompleteBooking(c *fiber.Ctx) error {
+	return h.transitionBooking(c, models.StatusCompleted, "Pemesanan berhasil diselesaikan")
+}
+
+// MarkNoShow: confirmed -> no_show (Admin/Front Desk)
+func (h *BookingHandler) MarkNoShow(c *fiber.Ctx) error {
+	return h.transitionBooking(c, models.StatusNoShow, "Pemesanan ditandai no-show")
+}
+
+// AdminCancelBooking: pending/confirmed -> cancelled (Admin Only)
+func (h *BookingHandler) AdminCancelBooking(c *fiber.Ctx) error {
+	return h.transitionBooking(c, models.StatusCancelled, "Pemesanan berhasil dibatalkan")
+}
+
+// GetBookingTransitions: Riwayat perubahan status pemesanan (Admin Only)
+func (h *BookingHandler) GetBookingTransitions(c *fiber.Ctx) error {
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
+	}
+
+	transitions, err := h.bookingService.GetBookingTransitions(uint(bookingID))
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return utils.RespondError(c, fiber.StatusNotFound, "Pemesanan tidak ditemukan")
+		}
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil riwayat status pemesanan")
 	}
 
-	return utils.RespondSuccess(c, fiber.StatusOK, "Status pembayaran berhasil diubah", updatedBooking)
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil riwayat status pemesanan", transitions)
 }
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/pkg/utils"
+	"errors"
+	"strconv"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CancellationPolicyHandler struct {
+	policyService services.CancellationPolicyService
+}
+
+func NewCancellationPolicyHandler(policyService services.CancellationPolicyService) *CancellationPolicyHandler {
+	return &CancellationPolicyHandler{policyService: policyService}
+}
+
+type CancellationRuleInput struct {
+	HoursBefore    int     `json:"hours_before"`    // Batas jam sebelum check-in
+	PenaltyPercent float64 `json:"penalty_percent"` // Penalti (%) dari total tagihan
+}
+
+type CancellationPolicyInput struct {
+	Code        string                  `json:"code"`
+	Name        string                  `json:"name"`
+	Description *string                 `json:"description"`
+	Rules       []CancellationRuleInput `json:"rules"` // Jika diisi, menggantikan seluruh aturan lama
+}
+
+// applyCancellationPolicyInput: Menyalin field yang diberikan dari input ke kebijakan pembatalan
+func applyCancellationPolicyInput(policy *models.CancellationPolicy, input CancellationPolicyInput) {
+	if input.Code != "" {
+		policy.Code = input.Code
+	}
+	if input.Name != "" {
+		policy.Name = input.Name
+	}
+	if input.Description != nil {
+		policy.Description = *input.Description
+	}
+	if input.Rules != nil {
+		policy.Rules = make([]models.CancellationRule, 0, len(input.Rules))
+		for _, rule := range input.Rules {
+			policy.Rules = append(policy.Rules, models.CancellationRule{
+				HoursBefore:    rule.HoursBefore,
+				PenaltyPercent: rule.PenaltyPercent,
+			})
+		}
+	}
+}
+
+// respondCancellationPolicyError: Memetakan error kebijakan pembatalan ke HTTP status
+func respondCancellationPolicyError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, models.ErrCancellationPolicyNotFound) {
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return utils.RespondError(c, fiber.StatusConflict, "Kode kebijakan pembatalan sudah digunakan")
+	}
+	return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+}
+
+// GetCancellationPolicies: Mengambil semua kebijakan pembatalan (Admin Only)
+func (h *CancellationPolicyHandler) GetCancellationPolicies(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	pagination := &models.Pagination{
+		Page:   page,
+		Limit:  limit,
+		Sort:   c.Query("sort", "created_at desc"),
+		Offset: (page - 1) * limit,
+	}
+
+	policies, err := h.policyService.GetCancellationPolicies(pagination)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data kebijakan pembatalan")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data kebijakan pembatalan", fiber.Map{
+		"cancellation_policies": policies,
+		"page":                  page,
+		"limit":                 limit,
+	})
+}
+
+// GetCancellationPolicyByID: Mengambil detail kebijakan pembatalan (Admin Only)
+func (h *CancellationPolicyHandler) GetCancellationPolicyByID(c *fiber.Ctx) error {
+	policyID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID kebijakan pembatalan tidak valid")
+	}
+
+	policy, err := h.policyService.GetCancellationPolicyByID(uint(policyID))
+	if err != nil {
+		return respondCancellationPolicyError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data kebijakan pembatalan", policy)
+}
+
+// CreateCancellationPolicy: Membuat kebijakan pembatalan (Admin Only)
+func (h *CancellationPolicyHandler) CreateCancellationPolicy(c *fiber.Ctx) error {
+	var input CancellationPolicyInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	policy := &models.CancellationPolicy{}
+	applyCancellationPolicyInput(policy, input)
+
+	createdPolicy, err := h.policyService.CreateCancellationPolicy(policy)
+	if err != nil {
+		return respondCancellationPolicyError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Kebijakan pembatalan berhasil dibuat", createdPolicy)
+}
+
+// UpdateCancellationPolicy: Mengubah kebijakan pembatalan (Admin Only)
+func (h *CancellationPolicyHandler) UpdateCancellationPolicy(c *fiber.Ctx) error {
+	policyID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID kebijakan pembatalan tidak valid")
+	}
+
+	var input CancellationPolicyInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	existingPolicy, err := h.policyService.GetCancellationPolicyByID(uint(policyID))
+	if err != nil {
+		return respondCancellationPolicyError(c, err)
+	}
+	applyCancellationPolicyInput(existingPolicy, input)
+
+	updatedPolicy, err := h.policyService.UpdateCancellationPolicy(existingPolicy)
+	if err != nil {
+		return respondCancellationPolicyError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Kebijakan pembatalan berhasil diubah", updatedPolicy)
+}
+
+// DeleteCancellationPolicy: Menghapus kebijakan pembatalan (Admin Only)
+func (h *CancellationPolicyHandler) DeleteCancellationPolicy(c *fiber.Ctx) error {
+	policyID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID kebijakan pembatalan tidak valid")
+	}
+
+	if err := h.policyService.DeleteCancellationPolicy(uint(policyID)); err != nil {
+		return respondCancellationPolicyError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Kebijakan pembatalan berhasil dihapus", nil)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/pkg/utils"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExchangeRateHandler struct {
+	exchangeRateService services.ExchangeRateService
+}
+
+func NewExchangeRateHandler(exchangeRateService services.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{exchangeRateService: exchangeRateService}
+}
+
+type ExchangeRateInput struct {
+	Rate float64 `json:"rate"` // Unit mata uang ini untuk 1 unit mata uang dasar
+}
+
+// GetExchangeRates: Mengambil semua kurs (Admin Only)
+func (h *ExchangeRateHandler) GetExchangeRates(c *fiber.Ctx) error {
+	rates, err := h.exchangeRateService.GetExchangeRates()
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data kurs")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data kurs", rates)
+}
+
+// SetExchangeRate: Membuat/mengubah kurs mata uang (Admin Only)
+func (h *ExchangeRateHandler) SetExchangeRate(c *fiber.Ctx) error {
+	var input ExchangeRateInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	rate, err := h.exchangeRateService.SetExchangeRate(c.Params("currency"), input.Rate)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Kurs berhasil disimpan", rate)
+}
+
+// DeleteExchangeRate: Menghapus kurs mata uang (Admin Only)
+func (h *ExchangeRateHandler) DeleteExchangeRate(c *fiber.Ctx) error {
+	if err := h.exchangeRateService.DeleteExchangeRate(c.Params("currency")); err != nil {
+		if errors.Is(err, models.ErrExchangeRateNotFound) {
+			return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+		}
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal menghapus kurs")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Kurs berhasil dihapus", nil)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/pkg/utils"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MFAHandler struct {
+	mfaService services.MFAService
+}
+
+func NewMFAHandler(mfaService services.MFAService) *MFAHandler {
+	return &MFAHandler{mfaService: mfaService}
+}
+
+// respondMFAError: Memetakan error 2FA ke HTTP status
+func respondMFAError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, models.ErrRecordNotFound):
+		return utils.RespondError(c, fiber.StatusNotFound, "User tidak ditemukan")
+	case errors.Is(err, models.ErrInvalidMFACode):
+		return utils.RespondError(c, fiber.StatusUnauthorized, err.Error())
+	case errors.Is(err, models.ErrPermissionDenied), errors.Is(err, models.ErrPermissionNotHeld):
+		return utils.RespondError(c, fiber.StatusForbidden, err.Error())
+	case errors.Is(err, models.ErrMFAAlreadyEnabled), errors.Is(err, models.ErrMFANotEnabled),
+		errors.Is(err, models.ErrMFANotEnrolled), errors.Is(err, models.ErrMFARequired):
+		return utils.RespondError(c, fiber.StatusConflict, err.Error())
+	}
+	return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal memproses autentikasi dua faktor")
+}
+
+type MFACodeInput struct {
+	Code string `json:"code" validate:"required"` // Kode TOTP 6 digit atau recovery code
+}
+
+// parseMFACode: Membaca kode 2FA dari body request
+func parseMFACode(c *fiber.Ctx) (string, error) {
+	var input MFACodeInput
+	if err := c.BodyParser(&input); err != nil || input.Code == "" {
+		return "", errors.New("Kode autentikasi wajib diisi")
+	}
+	return input.Code, nil
+}
+
+// GetStatus: Status 2FA user yang sedang login (Login Required)
+func (h *MFAHandler) GetStatus(c *fiber.Ctx) error {
+	status, err := h.mfaService.GetStatus(c.Locals("userID").(uint))
+	if err != nil {
+		return respondMFAError(c, err)
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil status autentikasi dua faktor", status)
+}
+
+// Enroll: Membuat secret TOTP & otpauth URI untuk dipindai aplikasi authenticator (Login Required)
+func (h *MFAHandler) Enroll(c *fiber.Ctx) error {
+	enrollment, err := h.mfaService.Enroll(c.Locals("userID").(uint))
+	if err != nil {
+		return respondMFAError(c, err)
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Pindai otpauth_uri dengan aplikasi authenticator lalu konfirmasi dengan kodenya", enrollment)
+}
+
+// Confirm: Mengaktifkan 2FA dengan kode pertama dari authenticator; recovery code hanya ditampilkan sekali (Login Required)
+func (h *MFAHandler) Confirm(c *fiber.Ctx) error {
+	code, err := parseMFACode(c)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	codes, err := h.mfaService.Confirm(c.Locals("userID").(uint), code)
+	if err != nil {
+		return respondMFAError(c, err)
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Autentikasi dua faktor aktif, simpan recovery code di tempat aman", fiber.Map{
+		"recovery_codes": codes,
+	})
+}
+
+// RegenerateRecoveryCodes: Mengganti seluruh recovery code (Login Required, butuh kode 2FA)
+func (h *MFAHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	code, err := parseMFACode(c)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(c.Locals("userID").(uint), code)
+	if err != nil {
+		return respondMFAError(c, err)
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Recovery code baru berhasil dibuat, recovery code lama tidak berlaku", fiber.Map{
+		"recovery_codes": codes,
+	})
+}
+
+// Disable: Menonaktifkan 2FA milik sendiri (Login Required, butuh kode 2FA)
+func (h *MFAHandler) Disable(c *fiber.Ctx) error {
+	code, err := parseMFACode(c)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.mfaService.Disable(c.Locals("userID").(uint), code); err != nil {
+		return respondMFAError(c, err)
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Autentikasi dua faktor dinonaktifkan", nil)
+}
+
+// ResetUserMFA: Menghapus 2FA user lain yang kehilangan perangkat & recovery code dan mencabut sesinya
+// (izin staff.manage; staf hanya boleh mereset user non-admin yang izinnya ia miliki semua)
+func (h *MFAHandler) ResetUserMFA(c *fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID user tidak valid")
+	}
+
+	if err := h.mfaService.Reset(c.Locals("userID").(uint), uint(userID)); err != nil {
+		return respondMFAError(c, err)
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Autentikasi dua faktor user berhasil direset", nil)
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/pkg/utils"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PaymentSignatureHeader adalah header berisi signature HMAC webhook pembayaran
+const PaymentSignatureHeader = "X-Payment-Signature"
+
+type PaymentHandler struct {
+	paymentService services.PaymentService
+}
+
+func NewPaymentHandler(paymentService services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+// respondPaymentError: Memetakan error pembayaran ke HTTP status
+func respondPaymentError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, models.ErrInvalidWebhookSignature):
+		return utils.RespondError(c, fiber.StatusUnauthorized, err.Error())
+	case errors.Is(err, models.ErrPaymentNotFound):
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrInvalidPaymentAmount),
+		errors.Is(err, models.ErrInvalidRefundAmount),
+		errors.Is(err, models.ErrPaymentCurrencyMismatch),
+		errors.Is(err, money.ErrInvalidAmount):
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrPaymentNotAllowed),
+		errors.Is(err, models.ErrRefundNotAllowed),
+		errors.Is(err, models.ErrPaymentAmountMismatch):
+		return utils.RespondError(c, fiber.StatusConflict, err.Error())
+	}
+	return respondBookingError(c, err)
+}
+
+type CreatePaymentIntentInput struct {
+	Amount money.Money `json:"amount"` // Opsional: nominal deposit, kosong = seluruh sisa tagihan
+}
+
+// CreatePaymentIntent: Membuat payment intent untuk booking (Member Only)
+func (h *PaymentHandler) CreatePaymentIntent(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
+	}
+
+	var input CreatePaymentIntentInput
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+		}
+	}
+
+	intent, err := h.paymentService.CreatePaymentIntent(uint(bookingID), userID, input.Amount)
+	if err != nil {
+		return respondPaymentError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Payment intent berhasil dibuat", intent)
+}
+
+// GetMyPayments: Melihat ledger pembayaran & sisa tagihan booking milik member (Member Only)
+func (h *PaymentHandler) GetMyPayments(c *fiber.Ctx) error {
+	return h.getPayments(c, c.Locals("userID").(uint))
+}
+
+// GetPayments: Melihat ledger pembayaran & sisa tagihan booking (Admin Only)
+func (h *PaymentHandler) GetPayments(c *fiber.Ctx) error {
+	return h.getPayments(c, 0)
+}
+
+func (h *PaymentHandler) getPayments(c *fiber.Ctx, userID uint) error {
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
+	}
+
+	summary, err := h.paymentService.GetPaymentSummary(uint(bookingID), userID)
+	if err != nil {
+		return respondPaymentError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data pembayaran", summary)
+}
+
+type RecordPaymentInput struct {
+	Amount    money.Money `json:"amount"`    // Kosong = seluruh sisa tagihan
+	Method    string      `json:"method"`    // cash, bank_transfer, card_terminal, dll
+	Reference string      `json:"reference"` // Opsional: nomor slip/struk
+	Note      string      `json:"note"`
+}
+
+// RecordPayment: Mencatat pembayaran manual di front desk (Admin Only)
+func (h *PaymentHandler) RecordPayment(c *fiber.Ctx) error {
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
+	}
+
+	var input RecordPaymentInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	booking, err := h.paymentService.RecordPayment(uint(bookingID), &models.Payment{
+		Amount:            input.Amount,
+		Method:            input.Method,
+		ProviderReference: input.Reference,
+		Note:              input.Note,
+		RecordedBy:        c.Locals("userID").(uint),
+	})
+	if err != nil {
+		return respondPaymentError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Pembayaran berhasil dicatat", booking)
+}
+
+// CapturePayment: Menagih pembayaran yang sudah diotorisasi (Admin Only)
+func (h *PaymentHandler) CapturePayment(c *fiber.Ctx) error {
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
+	}
+
+	booking, err := h.paymentService.CapturePayment(uint(bookingID))
+	if err != nil {
+		return respondPaymentError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Pembayaran berhasil ditagih", booking)
+}
+
+type RefundPaymentInput struct {
+	Amount money.Money `json:"amount"` // Opsional: kosong = seluruh pembayaran bersih
+	Note   string      `json:"note"`
+}
+
+// RefundPayment: Mengembalikan pembayaran booking, penuh atau sebagian (Admin Only)
+func (h *PaymentHandler) RefundPayment(c *fiber.Ctx) error {
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pemesanan tidak valid")
+	}
+
+	var input RefundPaymentInput
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+		}
+	}
+
+	booking, err := h.paymentService.RefundPayment(uint(bookingID), input.Amount, c.Locals("userID").(uint), input.Note)
+	if err != nil {
+		return respondPaymentError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Refund berhasil diproses", booking)
+}
+
+// HandleWebhook: Menerima webhook dari payment provider (Public, diverifikasi HMAC)
+func (h *PaymentHandler) HandleWebhook(c *fiber.Ctx) error {
+	if err := h.paymentService.HandleWebhook(c.Body(), c.Get(PaymentSignatureHeader)); err != nil {
+		return respondPaymentError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Webhook diproses", nil)
+}
+
+type SimulatePaymentInput struct {
+	Event string `json:"event"` // payment.succeeded | payment.failed | refund.succeeded
+}
+
+// SimulatePayment: Mensimulasikan webhook provider lokal/mock untuk intent milik member
+// (hanya terdaftar jika PAYMENT_PROVIDER=mock dan PAYMENT_SIMULATION_ENABLED=true)
+func (h *PaymentHandler) SimulatePayment(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+	var input SimulatePaymentInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+	if input.Event == "" {
+		input.Event = models.PaymentEventSucceeded
+	}
+
+	if err := h.paymentService.SimulateWebhook(c.Params("intentId"), userID, input.Event); err != nil {
+		return respondPaymentError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Webhook simulasi diproses", nil)
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/pkg/utils"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PromoCodeHandler struct {
+	promoCodeService services.PromoCodeService
+}
+
+func NewPromoCodeHandler(promoCodeService services.PromoCodeService) *PromoCodeHandler {
+	return &PromoCodeHandler{promoCodeService: promoCodeService}
+}
+
+type PromoCodeInput struct {
+	Code          string       `json:"code"`
+	Description   *string      `json:"description"`
+	DiscountType  string       `json:"discount_type"`  // percentage | fixed
+	DiscountValue *json.Number `json:"discount_value"` // Persen atau nominal, angka/string desimal
+	ValidFrom     *string      `json:"valid_from"`     // YYYY-MM-DD, "" untuk menghapus
+	ValidUntil    *string      `json:"valid_until"`    // YYYY-MM-DD (inklusif), "" untuk menghapus
+	MinNights     *int         `json:"min_nights"`
+	PerUserLimit  *int         `json:"per_user_limit"`
+	UsageLimit    *int         `json:"usage_limit"`
+	RoomTypes     *string      `json:"room_types"` // Contoh: "Suite,Deluxe"
+	IsActive      *bool        `json:"is_active"`
+}
+
+// applyPromoCodeInput: Menyalin field yang diberikan dari input ke promo
+func applyPromoCodeInput(promo *models.PromoCode, input PromoCodeInput) error {
+	if input.Code != "" {
+		promo.Code = input.Code
+	}
+	if input.Description != nil {
+		promo.Description = *input.Description
+	}
+	if input.DiscountType != "" {
+		promo.DiscountType = input.DiscountType
+	}
+	if input.DiscountValue != nil {
+		basisPoints, amount, err := parseRateOrAmount(*input.DiscountValue, promo.DiscountType == models.DiscountPercentage)
+		if err != nil {
+			return err
+		}
+		promo.DiscountBasisPoints, promo.DiscountAmount = basisPoints, amount
+	}
+	if input.ValidFrom != nil {
+		validFrom, err := parseOptionalDate(*input.ValidFrom)
+		if err != nil {
+			return err
+		}
+		promo.ValidFrom = validFrom
+	}
+	if input.ValidUntil != nil {
+		validUntil, err := parseOptionalDate(*input.ValidUntil)
+		if err != nil {
+			return err
+		}
+		if validUntil != nil {
+			// Berlaku sampai akhir hari tersebut
+			endOfDay := validUntil.Add(24*time.Hour - time.Second)
+			validUntil = &endOfDay
+		}
+		promo.ValidUntil = validUntil
+	}
+	if input.MinNights != nil {
+		promo.MinNights = *input.MinNights
+	}
+	if input.PerUserLimit != nil {
+		promo.PerUserLimit = *input.PerUserLimit
+	}
+	if input.UsageLimit != nil {
+		promo.UsageLimit = *input.UsageLimit
+	}
+	if input.RoomTypes != nil {
+		promo.RoomTypes = *input.RoomTypes
+	}
+	if input.IsActive != nil {
+		promo.IsActive = *input.IsActive
+	}
+	return nil
+}
+
+// parseOptionalDate: Parse tanggal YYYY-MM-DD, string kosong berarti nil
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, errors.New("Format tanggal tidak valid (gunakan format YYYY-MM-DD)")
+	}
+	return &date, nil
+}
+
+// parseRateOrAmount: Nilai persen dibaca sebagai basis poin, selain itu sebagai nominal mata uang
+// dasar; isian lainnya dikosongkan agar hanya satu yang berlaku
+func parseRateOrAmount(value json.Number, percentage bool) (int64, money.Money, error) {
+	if percentage {
+		basisPoints, err := money.ParseBasisPoints(value.String())
+		if err != nil {
+			return 0, money.Zero(), errors.New("Persentase tidak valid (maksimal 2 desimal)")
+		}
+		return basisPoints, money.Zero(), nil
+	}
+	amount, err := money.Parse(value.String(), "")
+	if err != nil {
+		return 0, money.Zero(), errors.New("Nominal tidak valid")
+	}
+	return 0, amount, nil
+}
+
+// respondPromoCodeError: Memetakan error kode promo ke HTTP status
+func respondPromoCodeError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, models.ErrPromoNotFound) {
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return utils.RespondError(c, fiber.StatusConflict, "Kode promo sudah digunakan")
+	}
+	return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+}
+
+// GetPromoCodes: Mengambil semua kode promo (Admin Only)
+func (h *PromoCodeHandler) GetPromoCodes(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	pagination := &models.Pagination{
+		Page:   page,
+		Limit:  limit,
+		Sort:   c.Query("sort", "created_at desc"),
+		Offset: (page - 1) * limit,
+	}
+
+	promos, err := h.promoCodeService.GetPromoCodes(pagination)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data kode promo")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data kode promo", fiber.Map{
+		"promo_codes": promos,
+		"page":        page,
+		"limit":       limit,
+	})
+}
+
+// GetPromoCodeByID: Mengambil detail kode promo (Admin Only)
+func (h *PromoCodeHandler) GetPromoCodeByID(c *fiber.Ctx) error {
+	promoCodeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID kode promo tidak valid")
+	}
+
+	promo, err := h.promoCodeService.GetPromoCodeByID(uint(promoCodeID))
+	if err != nil {
+		return respondPromoCodeError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data kode promo", promo)
+}
+
+// CreatePromoCode: Membuat kode promo (Admin Only)
+func (h *PromoCodeHandler) CreatePromoCode(c *fiber.Ctx) error {
+	var input PromoCodeInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	promo := &models.PromoCode{IsActive: true}
+	if err := applyPromoCodeInput(promo, input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	createdPromo, err := h.promoCodeService.CreatePromoCode(promo)
+	if err != nil {
+		return respondPromoCodeError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Kode promo berhasil dibuat", createdPromo)
+}
+
+// UpdatePromoCode: Mengubah kode promo (Admin Only)
+func (h *PromoCodeHandler) UpdatePromoCode(c *fiber.Ctx) error {
+	promoCodeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID kode promo tidak valid")
+	}
+
+	var input PromoCodeInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	existingPromo, err := h.promoCodeService.GetPromoCodeByID(uint(promoCodeID))
+	if err != nil {
+		return respondPromoCodeError(c, err)
+	}
+	if err := applyPromoCodeInput(existingPromo, input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	updatedPromo, err := h.promoCodeService.UpdatePromoCode(existingPromo)
+	if err != nil {
+		return respondPromoCodeError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Kode promo berhasil diubah", updatedPromo)
+}
+
+// DeletePromoCode: Menghapus kode promo (Admin Only)
+func (h *PromoCodeHandler) DeletePromoCode(c *fiber.Ctx) error {
+	promoCodeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID kode promo tidak valid")
+	}
+
+	if err := h.promoCodeService.DeletePromoCode(uint(promoCodeID)); err != nil {
+		return respondPromoCodeError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Kode promo berhasil dihapus", nil)
+}
+
+// GetPromoCodeStats: Statistik redemption kode promo (Admin Only)
+func (h *PromoCodeHandler) GetPromoCodeStats(c *fiber.Ctx) error {
+	promoCodeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID kode promo tidak valid")
+	}
+
+	stats, err := h.promoCodeService.GetPromoCodeStats(uint(promoCodeID))
+	if err != nil {
+		return respondPromoCodeError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil statistik kode promo", stats)
+}
@@ -0,0 +1,399 @@
+package handlers
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/pkg/utils"
+	"errors"
+	"strconv"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PropertyHandler struct {
+	propertyService services.PropertyService
+}
+
+func NewPropertyHandler(propertyService services.PropertyService) *PropertyHandler {
+	return &PropertyHandler{propertyService: propertyService}
+}
+
+// staffPropertyID: Properti admin yang sedang login (0 = staf grup, boleh mengakses semua properti)
+func staffPropertyID(c *fiber.Ctx) uint {
+	propertyID, _ := c.Locals("propertyID").(uint)
+	return propertyID
+}
+
+// propertyScope: Properti yang dipakai query admin. Staf properti selalu terkunci ke propertinya;
+// staf grup boleh memilih lewat ?property_id= (0 = semua properti).
+func propertyScope(c *fiber.Ctx) (uint, error) {
+	requested := uint(c.QueryInt("property_id", 0))
+	staffProperty := staffPropertyID(c)
+	if staffProperty == 0 {
+		return requested, nil
+	}
+	if requested != 0 && requested != staffProperty {
+		return 0, models.ErrPropertyAccessDenied
+	}
+	return staffProperty, nil
+}
+
+// ownedProperty: Properti untuk data baru (tipe kamar, pajak/biaya). Staf properti boleh mengosongkan
+// property_id (otomatis propertinya sendiri) tetapi tidak boleh mengisi properti lain.
+func ownedProperty(c *fiber.Ctx, requested uint) (uint, error) {
+	staffProperty := staffPropertyID(c)
+	if staffProperty == 0 {
+		return requested, nil
+	}
+	if requested != 0 && requested != staffProperty {
+		return 0, models.ErrPropertyAccessDenied
+	}
+	return staffProperty, nil
+}
+
+// requirePropertyAccess: Menolak staf properti yang mengakses resource :id milik properti lain.
+// owner mengembalikan properti pemilik resource; staf grup dilewatkan tanpa query tambahan.
+func requirePropertyAccess(c *fiber.Ctx, owner func(id uint) (uint, error)) error {
+	staffProperty := staffPropertyID(c)
+	if staffProperty == 0 {
+		return c.Next()
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID tidak valid")
+	}
+	propertyID, err := owner(uint(id))
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	}
+	if propertyID != staffProperty {
+		return utils.RespondError(c, fiber.StatusForbidden, models.ErrPropertyAccessDenied.Error())
+	}
+	return c.Next()
+}
+
+// respondPropertyError: Memetakan error properti/gedung ke HTTP status
+func respondPropertyError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, models.ErrPropertyNotFound), errors.Is(err, models.ErrBuildingNotFound),
+		errors.Is(err, models.ErrStaffNotFound):
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrPropertyAccessDenied):
+		return utils.RespondError(c, fiber.StatusForbidden, err.Error())
+	case errors.Is(err, models.ErrPropertyInUse), errors.Is(err, models.ErrBuildingInUse):
+		return utils.RespondError(c, fiber.StatusConflict, err.Error())
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return utils.RespondError(c, fiber.StatusConflict, "Kode sudah digunakan")
+	}
+	return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+}
+
+// parsePropertyID: Parse :id properti dan pastikan staf properti hanya mengakses propertinya sendiri
+func parsePropertyID(c *fiber.Ctx) (uint, error) {
+	propertyID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return 0, errors.New("ID properti tidak valid")
+	}
+	if staffProperty := staffPropertyID(c); staffProperty != 0 && staffProperty != uint(propertyID) {
+		return 0, models.ErrPropertyAccessDenied
+	}
+	return uint(propertyID), nil
+}
+
+// GetProperties: Mengambil daftar properti aktif, filter ?city= (Public)
+func (h *PropertyHandler) GetProperties(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	pagination := &models.Pagination{
+		Page:   page,
+		Limit:  limit,
+		Sort:   c.Query("sort", "name asc"),
+		Offset: (page - 1) * limit,
+	}
+
+	properties, err := h.propertyService.GetProperties(c.Query("city"), true, pagination)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data properti")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data properti", fiber.Map{
+		"properties": properties,
+		"page":       page,
+		"limit":      limit,
+	})
+}
+
+// GetPropertyByID: Mengambil detail properti aktif beserta gedungnya (Public)
+func (h *PropertyHandler) GetPropertyByID(c *fiber.Ctx) error {
+	propertyID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID properti tidak valid")
+	}
+
+	property, err := h.propertyService.GetPropertyByID(uint(propertyID))
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+	if !property.IsActive {
+		return utils.RespondError(c, fiber.StatusNotFound, models.ErrPropertyNotFound.Error())
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data properti", property)
+}
+
+// GetAllProperties: Mengambil semua properti termasuk yang nonaktif (Admin Only)
+func (h *PropertyHandler) GetAllProperties(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	pagination := &models.Pagination{
+		Page:   page,
+		Limit:  limit,
+		Sort:   c.Query("sort", "name asc"),
+		Offset: (page - 1) * limit,
+	}
+
+	// Staf properti hanya melihat propertinya sendiri
+	if staffProperty := staffPropertyID(c); staffProperty != 0 {
+		property, err := h.propertyService.GetPropertyByID(staffProperty)
+		if err != nil {
+			return respondPropertyError(c, err)
+		}
+		return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data properti", fiber.Map{
+			"properties": []models.Property{*property},
+			"page":       1,
+			"limit":      limit,
+		})
+	}
+
+	properties, err := h.propertyService.GetProperties(c.Query("city"), false, pagination)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data properti")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data properti", fiber.Map{
+		"properties": properties,
+		"page":       page,
+		"limit":      limit,
+	})
+}
+
+type PropertyInput struct {
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`
+	City        *string `json:"city"`
+	Address     *string `json:"address"`
+	Description *string `json:"description"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// applyPropertyInput: Menyalin field yang diberikan dari input ke properti
+func applyPropertyInput(property *models.Property, input PropertyInput) {
+	if input.Code != "" {
+		property.Code = input.Code
+	}
+	if input.Name != "" {
+		property.Name = input.Name
+	}
+	if input.City != nil {
+		property.City = *input.City
+	}
+	if input.Address != nil {
+		property.Address = *input.Address
+	}
+	if input.Description != nil {
+		property.Description = *input.Description
+	}
+	if input.IsActive != nil {
+		property.IsActive = *input.IsActive
+	}
+}
+
+// CreateProperty: Membuat properti baru (Admin Grup)
+func (h *PropertyHandler) CreateProperty(c *fiber.Ctx) error {
+	if staffPropertyID(c) != 0 {
+		return utils.RespondError(c, fiber.StatusForbidden, models.ErrPropertyAccessDenied.Error())
+	}
+
+	var input PropertyInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	property := &models.Property{IsActive: true}
+	applyPropertyInput(property, input)
+
+	createdProperty, err := h.propertyService.CreateProperty(property)
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Properti berhasil dibuat", createdProperty)
+}
+
+// UpdateProperty: Mengubah data properti (Admin)
+func (h *PropertyHandler) UpdateProperty(c *fiber.Ctx) error {
+	propertyID, err := parsePropertyID(c)
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+
+	var input PropertyInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	existingProperty, err := h.propertyService.GetPropertyByID(propertyID)
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+	applyPropertyInput(existingProperty, input)
+
+	updatedProperty, err := h.propertyService.UpdateProperty(existingProperty)
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Properti berhasil diubah", updatedProperty)
+}
+
+// DeleteProperty: Menghapus properti tanpa tipe kamar (Admin Grup)
+func (h *PropertyHandler) DeleteProperty(c *fiber.Ctx) error {
+	if staffPropertyID(c) != 0 {
+		return utils.RespondError(c, fiber.StatusForbidden, models.ErrPropertyAccessDenied.Error())
+	}
+
+	propertyID, err := parsePropertyID(c)
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+
+	if err := h.propertyService.DeleteProperty(propertyID); err != nil {
+		return respondPropertyError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Properti berhasil dihapus", nil)
+}
+
+type BuildingInput struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Floors *int   `json:"floors"` // 0 = jumlah lantai tidak dibatasi
+}
+
+// applyBuildingInput: Menyalin field yang diberikan dari input ke gedung
+func applyBuildingInput(building *models.Building, input BuildingInput) {
+	if input.Code != "" {
+		building.Code = input.Code
+	}
+	if input.Name != "" {
+		building.Name = input.Name
+	}
+	if input.Floors != nil {
+		building.Floors = *input.Floors
+	}
+}
+
+// CreateBuilding: Menambah gedung ke properti (Admin)
+func (h *PropertyHandler) CreateBuilding(c *fiber.Ctx) error {
+	propertyID, err := parsePropertyID(c)
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+
+	var input BuildingInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	building := &models.Building{PropertyID: propertyID}
+	applyBuildingInput(building, input)
+
+	createdBuilding, err := h.propertyService.CreateBuilding(building)
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Gedung berhasil dibuat", createdBuilding)
+}
+
+// UpdateBuilding: Mengubah data gedung (Admin)
+func (h *PropertyHandler) UpdateBuilding(c *fiber.Ctx) error {
+	propertyID, err := parsePropertyID(c)
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+	buildingID, err := strconv.ParseUint(c.Params("buildingId"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID gedung tidak valid")
+	}
+
+	var input BuildingInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	existingBuilding, err := h.propertyService.GetBuilding(propertyID, uint(buildingID))
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+	applyBuildingInput(existingBuilding, input)
+
+	updatedBuilding, err := h.propertyService.UpdateBuilding(existingBuilding)
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Gedung berhasil diubah", updatedBuilding)
+}
+
+// DeleteBuilding: Menghapus gedung tanpa kamar (Admin)
+func (h *PropertyHandler) DeleteBuilding(c *fiber.Ctx) error {
+	propertyID, err := parsePropertyID(c)
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+	buildingID, err := strconv.ParseUint(c.Params("buildingId"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID gedung tidak valid")
+	}
+
+	if err := h.propertyService.DeleteBuilding(propertyID, uint(buildingID)); err != nil {
+		return respondPropertyError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Gedung berhasil dihapus", nil)
+}
+
+type AssignStaffInput struct {
+	PropertyID *uint `json:"property_id"` // null/0 = staf grup (semua properti)
+}
+
+// AssignStaff: Menugaskan admin ke satu properti (Admin Grup)
+func (h *PropertyHandler) AssignStaff(c *fiber.Ctx) error {
+	if staffPropertyID(c) != 0 {
+		return utils.RespondError(c, fiber.StatusForbidden, models.ErrPropertyAccessDenied.Error())
+	}
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID user tidak valid")
+	}
+
+	var input AssignStaffInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	user, err := h.propertyService.AssignStaff(uint(userID), input.PropertyID)
+	if err != nil {
+		return respondPropertyError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Penugasan staf berhasil diubah", user)
+}
@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/pkg/utils"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RatePlanHandler struct {
+	ratePlanService services.RatePlanService
+	roomTypeService services.RoomTypeService
+}
+
+func NewRatePlanHandler(ratePlanService services.RatePlanService, roomTypeService services.RoomTypeService) *RatePlanHandler {
+	return &RatePlanHandler{ratePlanService: ratePlanService, roomTypeService: roomTypeService}
+}
+
+// RequireRatePlanAccess: Middleware admin, staf properti hanya boleh mengelola rate plan
+// milik tipe kamar propertinya
+func (h *RatePlanHandler) RequireRatePlanAccess(c *fiber.Ctx) error {
+	return requirePropertyAccess(c, func(id uint) (uint, error) {
+		plan, err := h.ratePlanService.GetRatePlanByID(id)
+		if err != nil {
+			return 0, err
+		}
+		if plan.RoomType == nil {
+			return 0, models.ErrRoomTypeNotFound
+		}
+		return plan.RoomType.PropertyID, nil
+	})
+}
+
+// respondRatePlanError: Memetakan error rate plan ke HTTP status
+func respondRatePlanError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, models.ErrRatePlanNotFound), errors.Is(err, models.ErrRoomTypeNotFound),
+		errors.Is(err, models.ErrCancellationPolicyNotFound):
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrRecordNotFound):
+		return utils.RespondError(c, fiber.StatusNotFound, "Komponen rate plan tidak ditemukan")
+	case errors.Is(err, models.ErrPropertyAccessDenied):
+		return utils.RespondError(c, fiber.StatusForbidden, err.Error())
+	}
+	return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+}
+
+// GetRatePlans: Mengambil semua rate plan, filter ?room_type_id= & ?property_id= (Admin Only)
+func (h *RatePlanHandler) GetRatePlans(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	pagination := &models.Pagination{
+		Page:   page,
+		Limit:  limit,
+		Sort:   c.Query("sort", "created_at desc"),
+		Offset: (page - 1) * limit,
+	}
+
+	propertyID, err := propertyScope(c)
+	if err != nil {
+		return respondRatePlanError(c, err)
+	}
+
+	plans, err := h.ratePlanService.GetRatePlans(uint(c.QueryInt("room_type_id", 0)), propertyID, pagination)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data rate plan")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data rate plan", fiber.Map{
+		"rate_plans": plans,
+		"page":       page,
+		"limit":      limit,
+	})
+}
+
+// GetRatePlanByID: Mengambil detail rate plan (Admin Only)
+func (h *RatePlanHandler) GetRatePlanByID(c *fiber.Ctx) error {
+	ratePlanID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID rate plan tidak valid")
+	}
+
+	plan, err := h.ratePlanService.GetRatePlanByID(uint(ratePlanID))
+	if err != nil {
+		return respondRatePlanError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data rate plan", plan)
+}
+
+type RatePlanInput struct {
+	RoomTypeID           uint     `json:"room_type_id"`
+	Name                 string   `json:"name"`
+	IsActive             *bool    `json:"is_active"`
+	Priority             *int     `json:"priority"`
+	WeekendDays          string   `json:"weekend_days"` // Contoh: "5,6" (Jumat & Sabtu)
+	WeekendUpliftPercent *float64 `json:"weekend_uplift_percent"`
+	CancellationPolicyID *uint    `json:"cancellation_policy_id"` // 0 = ikuti kebijakan tipe kamar
+}
+
+// CreateRatePlan: Membuat rate plan baru (Admin Only)
+func (h *RatePlanHandler) CreateRatePlan(c *fiber.Ctx) error {
+	var input RatePlanInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	// Staf properti hanya boleh membuat rate plan untuk tipe kamar propertinya
+	if staffProperty := staffPropertyID(c); staffProperty != 0 {
+		roomType, err := h.roomTypeService.GetRoomTypeByID(input.RoomTypeID)
+		if err != nil {
+			return respondRatePlanError(c, err)
+		}
+		if roomType.PropertyID != staffProperty {
+			return respondRatePlanError(c, models.ErrPropertyAccessDenied)
+		}
+	}
+
+	plan := &models.RatePlan{
+		RoomTypeID:  input.RoomTypeID,
+		Name:        input.Name,
+		IsActive:    true,
+		WeekendDays: "5,6",
+	}
+	applyRatePlanInput(plan, input)
+
+	createdPlan, err := h.ratePlanService.CreateRatePlan(plan)
+	if err != nil {
+		return respondRatePlanError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Rate plan berhasil dibuat", createdPlan)
+}
+
+// UpdateRatePlan: Mengubah rate plan (Admin Only)
+func (h *RatePlanHandler) UpdateRatePlan(c *fiber.Ctx) error {
+	ratePlanID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID rate plan tidak valid")
+	}
+
+	var input RatePlanInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	existingPlan, err := h.ratePlanService.GetRatePlanByID(uint(ratePlanID))
+	if err != nil {
+		return respondRatePlanError(c, err)
+	}
+
+	// Update field yang diberikan
+	if input.Name != "" {
+		existingPlan.Name = input.Name
+	}
+	applyRatePlanInput(existingPlan, input)
+
+	updatedPlan, err := h.ratePlanService.UpdateRatePlan(existingPlan)
+	if err != nil {
+		return respondRatePlanError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Rate plan berhasil diubah", updatedPlan)
+}
+
+// applyRatePlanInput: Menyalin field opsional dari input ke plan
+func applyRatePlanInput(plan *models.RatePlan, input RatePlanInput) {
+	if input.IsActive != nil {
+		plan.IsActive = *input.IsActive
+	}
+	if input.Priority != nil {
+		plan.Priority = *input.Priority
+	}
+	if input.WeekendDays != "" {
+		plan.WeekendDays = input.WeekendDays
+	}
+	if input.WeekendUpliftPercent != nil {
+		plan.WeekendUpliftPercent = *input.WeekendUpliftPercent
+	}
+	if input.CancellationPolicyID != nil {
+		plan.CancellationPolicyID = input.CancellationPolicyID
+	}
+}
+
+// DeleteRatePlan: Menghapus rate plan (Admin Only)
+func (h *RatePlanHandler) DeleteRatePlan(c *fiber.Ctx) error {
+	ratePlanID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID rate plan tidak valid")
+	}
+
+	if err := h.ratePlanService.DeleteRatePlan(uint(ratePlanID)); err != nil {
+		return respondRatePlanError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Rate plan berhasil dihapus", nil)
+}
+
+type RateSeasonInput struct {
+	Name      string      `json:"name"`
+	StartDate string      `json:"start_date"`
+	EndDate   string      `json:"end_date"`
+	Price     money.Money `json:"price"`
+}
+
+// AddSeason: Menambah harga musim (Admin Only)
+func (h *RatePlanHandler) AddSeason(c *fiber.Ctx) error {
+	ratePlanID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID rate plan tidak valid")
+	}
+
+	var input RateSeasonInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	startDate, endDate, err := parseDateRange(input.StartDate, input.EndDate)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	season, err := h.ratePlanService.AddSeason(&models.RateSeason{
+		RatePlanID: uint(ratePlanID),
+		Name:       input.Name,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Price:      input.Price,
+	})
+	if err != nil {
+		return respondRatePlanError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Musim berhasil ditambah", season)
+}
+
+// DeleteSeason: Menghapus harga musim (Admin Only)
+func (h *RatePlanHandler) DeleteSeason(c *fiber.Ctx) error {
+	return h.deleteComponent(c, "seasonId", h.ratePlanService.DeleteSeason, "Musim berhasil dihapus")
+}
+
+type RateDatePriceInput struct {
+	Date  string      `json:"date"`
+	Price money.Money `json:"price"`
+}
+
+// SetDatePrice: Mengatur harga tanggal khusus (Admin Only)
+func (h *RatePlanHandler) SetDatePrice(c *fiber.Ctx) error {
+	ratePlanID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID rate plan tidak valid")
+	}
+
+	var input RateDatePriceInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	date, err := time.Parse("2006-01-02", input.Date)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format tanggal tidak valid (gunakan format YYYY-MM-DD)")
+	}
+
+	datePrice, err := h.ratePlanService.SetDatePrice(&models.RateDatePrice{
+		RatePlanID: uint(ratePlanID),
+		Date:       date,
+		Price:      input.Price,
+	})
+	if err != nil {
+		return respondRatePlanError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Harga tanggal khusus berhasil disimpan", datePrice)
+}
+
+// DeleteDatePrice: Menghapus harga tanggal khusus (Admin Only)
+func (h *RatePlanHandler) DeleteDatePrice(c *fiber.Ctx) error {
+	return h.deleteComponent(c, "datePriceId", h.ratePlanService.DeleteDatePrice, "Harga tanggal khusus berhasil dihapus")
+}
+
+type RateBlackoutInput struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Reason    string `json:"reason"`
+}
+
+// AddBlackout: Menambah tanggal blackout (Admin Only)
+func (h *RatePlanHandler) AddBlackout(c *fiber.Ctx) error {
+	ratePlanID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID rate plan tidak valid")
+	}
+
+	var input RateBlackoutInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	startDate, endDate, err := parseDateRange(input.StartDate, input.EndDate)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	blackout, err := h.ratePlanService.AddBlackout(&models.RateBlackout{
+		RatePlanID: uint(ratePlanID),
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Reason:     input.Reason,
+	})
+	if err != nil {
+		return respondRatePlanError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Blackout berhasil ditambah", blackout)
+}
+
+// DeleteBlackout: Menghapus tanggal blackout (Admin Only)
+func (h *RatePlanHandler) DeleteBlackout(c *fiber.Ctx) error {
+	return h.deleteComponent(c, "blackoutId", h.ratePlanService.DeleteBlackout, "Blackout berhasil dihapus")
+}
+
+// deleteComponent: Helper hapus komponen rate plan berdasarkan :id dan param komponen
+func (h *RatePlanHandler) deleteComponent(c *fiber.Ctx, param string, deleteFn func(ratePlanID, componentID uint) error, successMessage string) error {
+	ratePlanID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID rate plan tidak valid")
+	}
+
+	componentID, err := strconv.ParseUint(c.Params(param), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID komponen rate plan tidak valid")
+	}
+
+	if err := deleteFn(uint(ratePlanID), uint(componentID)); err != nil {
+		return respondRatePlanError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, successMessage, nil)
+}
+
+// parseDateRange: Parse pasangan tanggal YYYY-MM-DD
+func parseDateRange(start, end string) (time.Time, time.Time, error) {
+	startDate, err := time.Parse("2006-01-02", start)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Format tanggal mulai tidak valid (gunakan format YYYY-MM-DD)")
+	}
+
+	endDate, err := time.Parse("2006-01-02", end)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Format tanggal akhir tidak valid (gunakan format YYYY-MM-DD)")
+	}
+	return startDate, endDate, nil
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/pkg/utils"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RoleHandler struct {
+	roleService services.RoleService
+}
+
+func NewRoleHandler(roleService services.RoleService) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+// respondRoleError: Memetakan error role ke HTTP status
+func respondRoleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, models.ErrRoleNotFound), errors.Is(err, models.ErrStaffNotFound):
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrPermissionDenied), errors.Is(err, models.ErrPermissionNotHeld):
+		return utils.RespondError(c, fiber.StatusForbidden, err.Error())
+	case errors.Is(err, models.ErrRoleNameTaken), errors.Is(err, models.ErrSystemRole),
+		errors.Is(err, models.ErrRoleInUse), errors.Is(err, models.ErrCannotChangeOwnRole):
+		return utils.RespondError(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, models.ErrInvalidRoleName), errors.Is(err, models.ErrInvalidPermission):
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+	return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal memproses role")
+}
+
+type RoleInput struct {
+	Name        string    `json:"name"` // Hanya saat membuat
+	Description *string   `json:"description"`
+	Permissions *[]string `json:"permissions"` // Daftar lengkap izin, menggantikan izin sebelumnya
+}
+
+// applyRoleInput: Menyalin field yang diberikan dari input ke role
+func applyRoleInput(role *models.Role, input RoleInput) {
+	if input.Description != nil {
+		role.Description = *input.Description
+	}
+	if input.Permissions != nil {
+		role.Permissions = *input.Permissions
+	}
+}
+
+// GetPermissions: Katalog semua izin yang bisa diberikan ke role (izin role.manage)
+func (h *RoleHandler) GetPermissions(c *fiber.Ctx) error {
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil daftar izin", h.roleService.GetPermissions())
+}
+
+// GetRoles: Mengambil semua role beserta izinnya (izin role.manage)
+func (h *RoleHandler) GetRoles(c *fiber.Ctx) error {
+	roles, err := h.roleService.GetRoles()
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data role")
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data role", roles)
+}
+
+// GetRoleByID: Mengambil detail role (izin role.manage)
+func (h *RoleHandler) GetRoleByID(c *fiber.Ctx) error {
+	roleID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID role tidak valid")
+	}
+
+	role, err := h.roleService.GetRoleByID(uint(roleID))
+	if err != nil {
+		return respondRoleError(c, err)
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data role", role)
+}
+
+// CreateRole: Membuat role staf baru (izin role.manage)
+func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
+	var input RoleInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	role := &models.Role{Name: input.Name}
+	applyRoleInput(role, input)
+
+	createdRole, err := h.roleService.CreateRole(c.Locals("userID").(uint), role)
+	if err != nil {
+		return respondRoleError(c, err)
+	}
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Role berhasil dibuat", createdRole)
+}
+
+// UpdateRole: Mengubah deskripsi dan izin role (izin role.manage)
+func (h *RoleHandler) UpdateRole(c *fiber.Ctx) error {
+	roleID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID role tidak valid")
+	}
+
+	var input RoleInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	existingRole, err := h.roleService.GetRoleByID(uint(roleID))
+	if err != nil {
+		return respondRoleError(c, err)
+	}
+	applyRoleInput(existingRole, input)
+
+	updatedRole, err := h.roleService.UpdateRole(c.Locals("userID").(uint), existingRole)
+	if err != nil {
+		return respondRoleError(c, err)
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Role berhasil diubah", updatedRole)
+}
+
+// DeleteRole: Menghapus role yang tidak dipakai user (izin role.manage)
+func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
+	roleID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID role tidak valid")
+	}
+
+	if err := h.roleService.DeleteRole(uint(roleID)); err != nil {
+		return respondRoleError(c, err)
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Role berhasil dihapus", nil)
+}
+
+type AssignRoleInput struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// AssignUserRole: Mengganti role user; user harus login ulang agar role baru berlaku (izin staff.manage, staf grup)
+func (h *RoleHandler) AssignUserRole(c *fiber.Ctx) error {
+	// Role berlaku di semua properti, jadi seperti AssignStaff hanya staf grup yang boleh menggantinya
+	if staffPropertyID(c) != 0 {
+		return utils.RespondError(c, fiber.StatusForbidden, models.ErrPropertyAccessDenied.Error())
+	}
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID user tidak valid")
+	}
+
+	var input AssignRoleInput
+	if err := c.BodyParser(&input); err != nil || input.Role == "" {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	user, err := h.roleService.AssignRole(c.Locals("userID").(uint), uint(userID), input.Role)
+	if err != nil {
+		return respondRoleError(c, err)
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Role user berhasil diubah", user)
+}
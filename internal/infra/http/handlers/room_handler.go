@@ -3,9 +3,13 @@ package handlers
 import (
 	"backend/internal/app/services"
 	"backend/internal/domain/models"
+	"backend/internal/domain/money"
 	"backend/pkg/utils"
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -18,7 +22,93 @@ func NewRoomHandler(roomService services.RoomService) *RoomHandler {
 	return &RoomHandler{roomService: roomService}
 }
 
-// GetAllRooms: Mengambil semua kamar (Public)
+// RequireRoomAccess: Middleware admin, staf properti hanya boleh mengelola kamar propertinya
+func (h *RoomHandler) RequireRoomAccess(c *fiber.Ctx) error {
+	return requirePropertyAccess(c, func(id uint) (uint, error) {
+		room, err := h.roomService.GetRoomByID(id)
+		if err != nil {
+			return 0, err
+		}
+		return room.PropertyID, nil
+	})
+}
+
+// ResolveRoomType: Middleware untuk alias lama /api/rooms/:id/..., memetakan kamar ke tipe kamarnya
+// (disimpan di c.Locals("roomTypeID")) dan menandai respons sebagai deprecated
+func (h *RoomHandler) ResolveRoomType(c *fiber.Ctx) error {
+	roomID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID kamar tidak valid")
+	}
+
+	room, err := h.roomService.GetRoomByID(uint(roomID))
+	if err != nil {
+		if errors.Is(err, models.ErrRoomNotFound) || err.Error() == "kamar tidak ditemukan" {
+			return utils.RespondError(c, fiber.StatusNotFound, "Kamar tidak ditemukan")
+		}
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data kamar")
+	}
+
+	successor := strings.Replace(c.Path(), "/rooms/"+c.Params("id"), fmt.Sprintf("/room-types/%d", room.RoomTypeID), 1)
+	markDeprecated(c, successor)
+	c.Locals("roomTypeID", room.RoomTypeID)
+	return c.Next()
+}
+
+// respondRoomError: Memetakan error kamar ke HTTP status
+func respondRoomError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, models.ErrRoomTypeNotFound), errors.Is(err, models.ErrBuildingNotFound),
+		errors.Is(err, models.ErrAmenityNotFound):
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrPropertyMismatch):
+		return utils.RespondError(c, fiber.StatusConflict, err.Error())
+	}
+	return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+}
+
+// normalizeAmenityCodes: Menyeragamkan kode fasilitas filter (huruf besar, tanpa nilai kosong)
+func normalizeAmenityCodes(values []string) []string {
+	var codes []string
+	for _, code := range values {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// parsePriceRange: Parse batas harga opsional dalam mata uang dasar
+func parsePriceRange(minPrice, maxPrice string) (money.Money, money.Money, error) {
+	var min, max money.Money
+	var err error
+	if minPrice != "" {
+		if min, err = money.Parse(minPrice, ""); err != nil {
+			return min, max, models.ErrInvalidPriceRange
+		}
+	}
+	if maxPrice != "" {
+		if max, err = money.Parse(maxPrice, ""); err != nil {
+			return min, max, models.ErrInvalidPriceRange
+		}
+	}
+	return min, max, nil
+}
+
+// parseRoomFilter: Membaca filter pencarian kamar dari query string
+func parseRoomFilter(c *fiber.Ctx) (models.RoomFilter, error) {
+	filter := models.RoomFilter{
+		PropertyFilter: models.PropertyFilter{PropertyID: uint(c.QueryInt("property_id", 0)), City: c.Query("city")},
+		RoomTypeID:     uint(c.QueryInt("room_type_id", 0)),
+		Amenities:      normalizeAmenityCodes(strings.Split(c.Query("amenities"), ",")), // mis. ?amenities=wifi,sea_view
+		Occupancy:      c.QueryInt("occupancy", 0),
+	}
+	var err error
+	filter.MinPrice, filter.MaxPrice, err = parsePriceRange(c.Query("min_price"), c.Query("max_price"))
+	return filter, err
+}
+
+// GetAllRooms: Mengambil kamar fisik beserta tipe & fasilitasnya, dengan filter dan facet (Public)
 func (h *RoomHandler) GetAllRooms(c *fiber.Ctx) error {
 	page := c.QueryInt("page", 1)
 	limit := c.QueryInt("limit", 10)
@@ -30,15 +120,28 @@ func (h *RoomHandler) GetAllRooms(c *fiber.Ctx) error {
 		Offset: (page - 1) * limit,
 	}
 
-	rooms, err := h.roomService.GetAllRooms(pagination)
+	filter, err := parseRoomFilter(c)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	rooms, err := h.roomService.GetAllRooms(filter, pagination)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidPriceRange) || errors.Is(err, models.ErrInvalidGuestCount) {
+			return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+		}
 		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data kamar")
 	}
+	facets, err := h.roomService.GetRoomFacets(filter)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal menghitung filter kamar")
+	}
 
 	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data kamar", fiber.Map{
-		"rooms": rooms,
-		"page":  page,
-		"limit": limit,
+		"rooms":  rooms,
+		"facets": facets,
+		"page":   page,
+		"limit":  limit,
 	})
 }
 
@@ -60,46 +163,42 @@ func (h *RoomHandler) GetRoomByID(c *fiber.Ctx) error {
 	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data kamar", room)
 }
 
-type GetAvailableRoomsInput struct {
-	CheckInDate  string `json:"check_in_date" validate:"required"`
-	CheckOutDate string `json:"check_out_date" validate:"required"`
-}
-
-// GetAvailableRooms: Mengambil kamar yang tersedia (Public)
-func (h *RoomHandler) GetAvailableRooms(c *fiber.Ctx) error {
-	var input GetAvailableRoomsInput
-	if err := c.BodyParser(&input); err != nil {
-		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+// GetRoomCalendar: Kalender ketersediaan & harga kamar per malam, ?from=YYYY-MM-DD&to=YYYY-MM-DD (Public)
+func (h *RoomHandler) GetRoomCalendar(c *fiber.Ctx) error {
+	roomID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID kamar tidak valid")
 	}
 
-	page := c.QueryInt("page", 1)
-	limit := c.QueryInt("limit", 10)
-
-	pagination := &models.Pagination{
-		Page:   page,
-		Limit:  limit,
-		Sort:   c.Query("sort", "created_at desc"),
-		Offset: (page - 1) * limit,
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format tanggal from tidak valid (gunakan format YYYY-MM-DD)")
+	}
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format tanggal to tidak valid (gunakan format YYYY-MM-DD)")
 	}
 
-	rooms, err := h.roomService.GetAvailableRooms(input.CheckInDate, input.CheckOutDate, pagination)
+	calendar, err := h.roomService.GetRoomCalendar(uint(roomID), from, to)
 	if err != nil {
-		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil kamar tersedia")
+		switch {
+		case err.Error() == "kamar tidak ditemukan", errors.Is(err, models.ErrRoomTypeNotFound):
+			return utils.RespondError(c, fiber.StatusNotFound, "Kamar tidak ditemukan")
+		case errors.Is(err, models.ErrInvalidCalendarRange):
+			return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+		}
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil kalender kamar")
 	}
 
-	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil kamar tersedia", fiber.Map{
-		"rooms": rooms,
-		"page":  page,
-		"limit": limit,
-	})
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil kalender kamar", calendar)
 }
 
 type CreateRoomInput struct {
-	RoomNumber   string  `json:"room_number" validate:"required"`
-	Type         string  `json:"type" validate:"required"`
-	Price        float64 `json:"price" validate:"required"`
-	Description  string  `json:"description"`
-	MaxOccupancy int     `json:"max_occupancy" validate:"required"`
+	RoomNumber string `json:"room_number" validate:"required"`
+	RoomTypeID uint   `json:"room_type_id" validate:"required"` // Properti kamar mengikuti tipe kamarnya
+	Status     string `json:"status"`                           // Default available
+	BuildingID *uint  `json:"building_id"`                      // Opsional, gedung di properti yang sama
+	Floor      *int   `json:"floor"`                            // Opsional
 }
 
 // CreateRoom: Membuat kamar baru (Admin Only)
@@ -110,29 +209,31 @@ func (h *RoomHandler) CreateRoom(c *fiber.Ctx) error {
 	}
 
 	room := &models.Room{
-		RoomNumber:   input.RoomNumber,
-		Type:         input.Type,
-		Price:        input.Price,
-		Description:  input.Description,
-		MaxOccupancy: input.MaxOccupancy,
-		Status:       "available",
+		PropertyID: staffPropertyID(c), // Staf properti hanya bisa memakai tipe kamar propertinya
+		RoomNumber: input.RoomNumber,
+		RoomTypeID: input.RoomTypeID,
+		Status:     "available",
+		BuildingID: input.BuildingID,
+		Floor:      input.Floor,
+	}
+	if input.Status != "" {
+		room.Status = input.Status
 	}
 
 	createdRoom, err := h.roomService.CreateRoom(room)
 	if err != nil {
-		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+		return respondRoomError(c, err)
 	}
 
 	return utils.RespondSuccess(c, fiber.StatusCreated, "Kamar berhasil dibuat", createdRoom)
 }
 
 type UpdateRoomInput struct {
-	RoomNumber   string  `json:"room_number"`
-	Type         string  `json:"type"`
-	Price        float64 `json:"price"`
-	Description  string  `json:"description"`
-	Status       string  `json:"status"`
-	MaxOccupancy int     `json:"max_occupancy"`
+	RoomNumber string `json:"room_number"`
+	RoomTypeID uint   `json:"room_type_id"` // Harus tipe kamar di properti yang sama
+	Status     string `json:"status"`
+	BuildingID *uint  `json:"building_id"` // 0 = tanpa gedung
+	Floor      *int   `json:"floor"`
 }
 
 // UpdateRoom: Mengubah data kamar (Admin Only)
@@ -157,30 +258,69 @@ func (h *RoomHandler) UpdateRoom(c *fiber.Ctx) error {
 	if input.RoomNumber != "" {
 		existingRoom.RoomNumber = input.RoomNumber
 	}
-	if input.Type != "" {
-		existingRoom.Type = input.Type
-	}
-	if input.Price > 0 {
-		existingRoom.Price = input.Price
-	}
-	if input.Description != "" {
-		existingRoom.Description = input.Description
+	if input.RoomTypeID > 0 {
+		existingRoom.RoomTypeID = input.RoomTypeID
 	}
 	if input.Status != "" {
 		existingRoom.Status = input.Status
 	}
-	if input.MaxOccupancy > 0 {
-		existingRoom.MaxOccupancy = input.MaxOccupancy
+	if input.BuildingID != nil {
+		existingRoom.BuildingID = input.BuildingID
+		if *input.BuildingID == 0 {
+			existingRoom.BuildingID = nil
+		}
+	}
+	if input.Floor != nil {
+		existingRoom.Floor = input.Floor
 	}
 
 	updatedRoom, err := h.roomService.UpdateRoom(existingRoom)
 	if err != nil {
+		if errors.Is(err, models.ErrRoomTypeNotFound) || errors.Is(err, models.ErrBuildingNotFound) ||
+			errors.Is(err, models.ErrPropertyMismatch) || errors.Is(err, models.ErrInvalidFloor) {
+			return respondRoomError(c, err)
+		}
 		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengubah kamar")
 	}
 
 	return utils.RespondSuccess(c, fiber.StatusOK, "Kamar berhasil diubah", updatedRoom)
 }
 
+type UpdateRoomStatusInput struct {
+	Status string `json:"status" validate:"required"` // available, booked, atau maintenance
+}
+
+// UpdateRoomStatus: Mengubah status kamar saja, mis. housekeeping menandai kamar maintenance (Staff)
+func (h *RoomHandler) UpdateRoomStatus(c *fiber.Ctx) error {
+	roomID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID kamar tidak valid")
+	}
+
+	var input UpdateRoomStatusInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+	switch input.Status {
+	case "available", "booked", "maintenance":
+	default:
+		return utils.RespondError(c, fiber.StatusBadRequest, "Status kamar harus available, booked, atau maintenance")
+	}
+
+	existingRoom, err := h.roomService.GetRoomByID(uint(roomID))
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusNotFound, "Kamar tidak ditemukan")
+	}
+	existingRoom.Status = input.Status
+
+	updatedRoom, err := h.roomService.UpdateRoom(existingRoom)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengubah status kamar")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Status kamar berhasil diubah", updatedRoom)
+}
+
 // DeleteRoom: Menghapus kamar (Admin Only)
 func (h *RoomHandler) DeleteRoom(c *fiber.Ctx) error {
 	roomID, err := strconv.ParseUint(c.Params("id"), 10, 32)
@@ -198,6 +338,30 @@ func (h *RoomHandler) DeleteRoom(c *fiber.Ctx) error {
 	return utils.RespondSuccess(c, fiber.StatusOK, "Kamar berhasil dihapus", nil)
 }
 
+type SetRoomAmenitiesInput struct {
+	AmenityIDs []uint `json:"amenity_ids"` // Daftar lengkap; kosong = hapus semua fasilitas
+}
+
+// SetRoomAmenities: Mengganti fasilitas kamar (Admin Only)
+func (h *RoomHandler) SetRoomAmenities(c *fiber.Ctx) error {
+	roomID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID kamar tidak valid")
+	}
+
+	var input SetRoomAmenitiesInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	room, err := h.roomService.SetRoomAmenities(uint(roomID), input.AmenityIDs)
+	if err != nil {
+		return respondRoomError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Fasilitas kamar berhasil diubah", room)
+}
+
 type AddRoomImageInput struct {
 	ImageURL  string `json:"image_url" validate:"required"`
 	IsPrimary bool   `json:"is_primary"`
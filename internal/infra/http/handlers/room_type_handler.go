@@ -0,0 +1,369 @@
+package handlers
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"backend/pkg/utils"
+	"errors"
+	"strconv"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
+)
+
+type RoomTypeHandler struct {
+	roomTypeService     services.RoomTypeService
+	pricingService      services.PricingService
+	exchangeRateService services.ExchangeRateService
+}
+
+func NewRoomTypeHandler(roomTypeService services.RoomTypeService, pricingService services.PricingService, exchangeRateService services.ExchangeRateService) *RoomTypeHandler {
+	return &RoomTypeHandler{roomTypeService: roomTypeService, pricingService: pricingService, exchangeRateService: exchangeRateService}
+}
+
+// localizeRoomTypes: Mengisi harga tampilan sesuai query ?currency= (opsional)
+func (h *RoomTypeHandler) localizeRoomTypes(c *fiber.Ctx, roomTypes []models.RoomType) error {
+	if err := h.exchangeRateService.LocalizeRoomTypes(roomTypes, c.Query("currency")); err != nil {
+		if errors.Is(err, models.ErrExchangeRateNotFound) {
+			return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+		}
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengonversi harga kamar")
+	}
+	return nil
+}
+
+// markDeprecated: Menandai respons endpoint lama beserta pengganti yang disarankan
+func markDeprecated(c *fiber.Ctx, successor string) {
+	c.Set("Deprecation", "true")
+	c.Set(fiber.HeaderLink, "<"+successor+">; rel=\"successor-version\"")
+}
+
+// respondRoomTypeError: Memetakan error tipe kamar ke HTTP status
+func respondRoomTypeError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, models.ErrRoomTypeNotFound), errors.Is(err, models.ErrCancellationPolicyNotFound),
+		errors.Is(err, models.ErrPropertyNotFound):
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrPropertyAccessDenied):
+		return utils.RespondError(c, fiber.StatusForbidden, err.Error())
+	case errors.Is(err, models.ErrRoomTypeInUse):
+		return utils.RespondError(c, fiber.StatusConflict, err.Error())
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return utils.RespondError(c, fiber.StatusConflict, "Kode tipe kamar sudah digunakan")
+	}
+	return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+}
+
+// RequireRoomTypeAccess: Middleware admin, staf properti hanya boleh mengelola tipe kamar propertinya
+func (h *RoomTypeHandler) RequireRoomTypeAccess(c *fiber.Ctx) error {
+	return requirePropertyAccess(c, func(id uint) (uint, error) {
+		roomType, err := h.roomTypeService.GetRoomTypeByID(id)
+		if err != nil {
+			return 0, err
+		}
+		return roomType.PropertyID, nil
+	})
+}
+
+// GetRoomTypes: Mengambil semua tipe kamar properti aktif, filter ?property_id= & ?city= (Public)
+func (h *RoomTypeHandler) GetRoomTypes(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	pagination := &models.Pagination{
+		Page:   page,
+		Limit:  limit,
+		Sort:   c.Query("sort", "created_at desc"),
+		Offset: (page - 1) * limit,
+	}
+
+	filter := models.PropertyFilter{PropertyID: uint(c.QueryInt("property_id", 0)), City: c.Query("city")}
+	roomTypes, err := h.roomTypeService.GetRoomTypes(filter, pagination)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data tipe kamar")
+	}
+	if err := h.localizeRoomTypes(c, roomTypes); err != nil {
+		return err
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data tipe kamar", fiber.Map{
+		"room_types": roomTypes,
+		"page":       page,
+		"limit":      limit,
+	})
+}
+
+// GetRoomTypeByID: Mengambil detail tipe kamar (Public)
+func (h *RoomTypeHandler) GetRoomTypeByID(c *fiber.Ctx) error {
+	roomTypeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID tipe kamar tidak valid")
+	}
+
+	roomType, err := h.roomTypeService.GetRoomTypeByID(uint(roomTypeID))
+	if err != nil {
+		return respondRoomTypeError(c, err)
+	}
+	roomTypes := []models.RoomType{*roomType}
+	if err := h.localizeRoomTypes(c, roomTypes); err != nil {
+		return err
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data tipe kamar", roomTypes[0])
+}
+
+type GetAvailableRoomTypesInput struct {
+	CheckInDate  string `json:"check_in_date" validate:"required"`
+	CheckOutDate string `json:"check_out_date" validate:"required"`
+	PropertyID   uint   `json:"property_id"` // Opsional: kosong = cari di semua properti
+	City         string `json:"city"`        // Opsional: batasi ke properti di kota tertentu
+	Adults       int    `json:"adults"`      // Opsional: hanya tipe kamar yang muat untuk rombongan
+	ChildAges    []int  `json:"child_ages"`
+
+	// Filter opsional lainnya
+	RoomTypeID uint     `json:"room_type_id"`
+	Amenities  []string `json:"amenities"` // Kode fasilitas, tipe kamar harus memiliki semuanya
+	MinPrice   string   `json:"min_price"` // Harga dasar per malam, mata uang dasar
+	MaxPrice   string   `json:"max_price"`
+}
+
+// GetAvailableRoomTypes: Mengambil tipe kamar yang masih tersisa beserta jumlah sisanya,
+// dari satu properti atau lintas properti (Public)
+func (h *RoomTypeHandler) GetAvailableRoomTypes(c *fiber.Ctx) error {
+	return h.searchAvailableRoomTypes(c, "room_types")
+}
+
+// GetAvailableRooms: Alias lama POST /api/rooms/available (deprecated), hasil tipe kamar yang
+// sama dengan GetAvailableRoomTypes dikirim juga di bawah key "rooms" untuk klien lama (Public)
+func (h *RoomTypeHandler) GetAvailableRooms(c *fiber.Ctx) error {
+	markDeprecated(c, "/api/room-types/available")
+	return h.searchAvailableRoomTypes(c, "room_types", "rooms")
+}
+
+// searchAvailableRoomTypes: Pencarian tipe kamar tersedia, hasilnya dikirim di bawah setiap key
+func (h *RoomTypeHandler) searchAvailableRoomTypes(c *fiber.Ctx, keys ...string) error {
+	var input GetAvailableRoomTypesInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+	if _, _, err := parseStayDates(input.CheckInDate, input.CheckOutDate); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	pagination := &models.Pagination{
+		Page:   page,
+		Limit:  limit,
+		Sort:   c.Query("sort", "created_at desc"),
+		Offset: (page - 1) * limit,
+	}
+
+	minPrice, maxPrice, err := parsePriceRange(input.MinPrice, input.MaxPrice)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+	filter := models.RoomFilter{
+		PropertyFilter: models.PropertyFilter{PropertyID: input.PropertyID, City: input.City},
+		RoomTypeID:     input.RoomTypeID,
+		Amenities:      normalizeAmenityCodes(input.Amenities),
+		MinPrice:       minPrice,
+		MaxPrice:       maxPrice,
+	}
+	party := parseParty(input.Adults, input.ChildAges, 0)
+	if !party.IsZero() {
+		if err := party.Validate(); err != nil {
+			return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+		}
+	}
+	roomTypes, facets, err := h.roomTypeService.GetAvailableRoomTypes(input.CheckInDate, input.CheckOutDate, filter, party, pagination)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidPriceRange) {
+			return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+		}
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil kamar tersedia")
+	}
+	if err := h.localizeRoomTypes(c, roomTypes); err != nil {
+		return err
+	}
+
+	data := fiber.Map{
+		"facets": facets,
+		"page":   page,
+		"limit":  limit,
+	}
+	for _, key := range keys {
+		data[key] = roomTypes
+	}
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil kamar tersedia", data)
+}
+
+type QuoteInput struct {
+	CheckInDate  string `json:"check_in_date" validate:"required"`
+	CheckOutDate string `json:"check_out_date" validate:"required"`
+	Adults       int    `json:"adults"`     // Default 1
+	ChildAges    []int  `json:"child_ages"` // Usia setiap anak, menentukan biaya anak
+	Guests       int    `json:"guests"`     // Deprecated: gunakan adults
+	PromoCode    string `json:"promo_code"`
+	Currency     string `json:"currency"` // Alternatif dari query ?currency=
+}
+
+// GetQuote: Rincian harga + quote token sebelum booking (Public). Pada alias lama
+// /api/rooms/:id/quote tipe kamar sudah dipetakan oleh RoomHandler.ResolveRoomType
+func (h *RoomTypeHandler) GetQuote(c *fiber.Ctx) error {
+	roomTypeID, ok := c.Locals("roomTypeID").(uint)
+	if !ok {
+		id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+		if err != nil {
+			return utils.RespondError(c, fiber.StatusBadRequest, "ID tipe kamar tidak valid")
+		}
+		roomTypeID = uint(id)
+	}
+
+	var input QuoteInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	checkIn, checkOut, err := parseStayDates(input.CheckInDate, input.CheckOutDate)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+	party := parseParty(input.Adults, input.ChildAges, input.Guests)
+	if party.Adults == 0 && party.Children() == 0 {
+		party.Adults = 1
+	}
+
+	currency := c.Query("currency", input.Currency)
+	quote, err := h.pricingService.Quote(roomTypeID, checkIn, checkOut, party, input.PromoCode, currency)
+	if err != nil {
+		if errors.Is(err, models.ErrRoomTypeNotFound) || errors.Is(err, models.ErrPromoNotFound) {
+			return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+		}
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil menghitung harga", quote)
+}
+
+type RoomTypeInput struct {
+	PropertyID   uint        `json:"property_id"` // Hanya saat membuat; staf properti boleh mengosongkan
+	Code         string      `json:"code"`
+	Name         string      `json:"name"`
+	Description  *string     `json:"description"`
+	Price        money.Money `json:"price"` // Angka/string desimal, mis. 500000 atau "500000.00"
+	MaxOccupancy int         `json:"max_occupancy"`
+
+	// Okupansi & biaya per orang; nil = tidak diubah
+	MaxAdults       *int         `json:"max_adults"`         // 0 = dibatasi max_occupancy saja
+	BaseOccupancy   *int         `json:"base_occupancy"`     // Dewasa yang sudah termasuk harga, 0 = semua
+	ExtraAdultPrice *money.Money `json:"extra_adult_price"`  // Per dewasa tambahan per malam
+	ChildPrice      *money.Money `json:"child_price"`        // Per anak berbayar per malam
+	FreeChildMaxAge *int         `json:"free_child_max_age"` // Anak sampai usia ini gratis
+
+	CancellationPolicyID *uint `json:"cancellation_policy_id"` // 0 = tanpa kebijakan pembatalan
+}
+
+// applyRoomTypeInput: Menyalin field yang diberikan dari input ke tipe kamar
+func applyRoomTypeInput(roomType *models.RoomType, input RoomTypeInput) {
+	if input.Code != "" {
+		roomType.Code = input.Code
+	}
+	if input.Name != "" {
+		roomType.Name = input.Name
+	}
+	if input.Description != nil {
+		roomType.Description = *input.Description
+	}
+	if input.Price.IsPositive() {
+		roomType.Price = input.Price
+	}
+	if input.MaxOccupancy > 0 {
+		roomType.MaxOccupancy = input.MaxOccupancy
+	}
+	if input.MaxAdults != nil {
+		roomType.MaxAdults = *input.MaxAdults
+	}
+	if input.BaseOccupancy != nil {
+		roomType.BaseOccupancy = *input.BaseOccupancy
+	}
+	if input.ExtraAdultPrice != nil {
+		roomType.ExtraAdultPrice = *input.ExtraAdultPrice
+	}
+	if input.ChildPrice != nil {
+		roomType.ChildPrice = *input.ChildPrice
+	}
+	if input.FreeChildMaxAge != nil {
+		roomType.FreeChildMaxAge = *input.FreeChildMaxAge
+	}
+	if input.CancellationPolicyID != nil {
+		roomType.CancellationPolicyID = input.CancellationPolicyID
+	}
+}
+
+// CreateRoomType: Membuat tipe kamar baru (Admin Only)
+func (h *RoomTypeHandler) CreateRoomType(c *fiber.Ctx) error {
+	var input RoomTypeInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	propertyID, err := ownedProperty(c, input.PropertyID)
+	if err != nil {
+		return respondRoomTypeError(c, err)
+	}
+
+	roomType := &models.RoomType{PropertyID: propertyID}
+	applyRoomTypeInput(roomType, input)
+
+	createdRoomType, err := h.roomTypeService.CreateRoomType(roomType)
+	if err != nil {
+		return respondRoomTypeError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Tipe kamar berhasil dibuat", createdRoomType)
+}
+
+// UpdateRoomType: Mengubah tipe kamar, properti tidak dapat diubah (Admin Only)
+func (h *RoomTypeHandler) UpdateRoomType(c *fiber.Ctx) error {
+	roomTypeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID tipe kamar tidak valid")
+	}
+
+	var input RoomTypeInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	existingRoomType, err := h.roomTypeService.GetRoomTypeByID(uint(roomTypeID))
+	if err != nil {
+		return respondRoomTypeError(c, err)
+	}
+	applyRoomTypeInput(existingRoomType, input)
+
+	updatedRoomType, err := h.roomTypeService.UpdateRoomType(existingRoomType)
+	if err != nil {
+		return respondRoomTypeError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Tipe kamar berhasil diubah", updatedRoomType)
+}
+
+// DeleteRoomType: Menghapus tipe kamar tanpa kamar fisik (Admin Only)
+func (h *RoomTypeHandler) DeleteRoomType(c *fiber.Ctx) error {
+	roomTypeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID tipe kamar tidak valid")
+	}
+
+	if err := h.roomTypeService.DeleteRoomType(uint(roomTypeID)); err != nil {
+		return respondRoomTypeError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Tipe kamar berhasil dihapus", nil)
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/pkg/utils"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StayRestrictionHandler struct {
+	restrictionService services.StayRestrictionService
+}
+
+func NewStayRestrictionHandler(restrictionService services.StayRestrictionService) *StayRestrictionHandler {
+	return &StayRestrictionHandler{restrictionService: restrictionService}
+}
+
+// RequireStayRestrictionAccess: Middleware admin, staf properti hanya boleh mengelola batasan propertinya
+func (h *StayRestrictionHandler) RequireStayRestrictionAccess(c *fiber.Ctx) error {
+	return requirePropertyAccess(c, func(id uint) (uint, error) {
+		restriction, err := h.restrictionService.GetRestrictionByID(id)
+		if err != nil {
+			return 0, err
+		}
+		return restriction.PropertyID, nil
+	})
+}
+
+// respondStayRestrictionError: Memetakan error batasan masa inap ke HTTP status
+func respondStayRestrictionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, models.ErrStayRestrictionNotFound), errors.Is(err, models.ErrRoomTypeNotFound),
+		errors.Is(err, models.ErrRoomNotFound):
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrPropertyAccessDenied):
+		return utils.RespondError(c, fiber.StatusForbidden, err.Error())
+	}
+	return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+}
+
+type StayRestrictionInput struct {
+	RoomTypeID        *uint   `json:"room_type_id"` // Hanya saat membuat; isi salah satu dari room_type_id atau room_id
+	RoomID            *uint   `json:"room_id"`
+	StartDate         string  `json:"start_date"`
+	EndDate           string  `json:"end_date"`
+	MinStay           *int    `json:"min_stay"`
+	MaxStay           *int    `json:"max_stay"`
+	ClosedToArrival   *bool   `json:"closed_to_arrival"`
+	ClosedToDeparture *bool   `json:"closed_to_departure"`
+	MinAdvanceDays    *int    `json:"min_advance_days"`
+	MaxAdvanceDays    *int    `json:"max_advance_days"`
+	Note              *string `json:"note"`
+}
+
+// applyStayRestrictionInput: Menyalin field yang diberikan dari input ke batasan
+func applyStayRestrictionInput(restriction *models.StayRestriction, input StayRestrictionInput) error {
+	if input.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", input.StartDate)
+		if err != nil {
+			return errors.New("Format tanggal mulai tidak valid (gunakan format YYYY-MM-DD)")
+		}
+		restriction.StartDate = startDate
+	}
+	if input.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", input.EndDate)
+		if err != nil {
+			return errors.New("Format tanggal akhir tidak valid (gunakan format YYYY-MM-DD)")
+		}
+		restriction.EndDate = endDate
+	}
+	if input.MinStay != nil {
+		restriction.MinStay = *input.MinStay
+	}
+	if input.MaxStay != nil {
+		restriction.MaxStay = *input.MaxStay
+	}
+	if input.ClosedToArrival != nil {
+		restriction.ClosedToArrival = *input.ClosedToArrival
+	}
+	if input.ClosedToDeparture != nil {
+		restriction.ClosedToDeparture = *input.ClosedToDeparture
+	}
+	if input.MinAdvanceDays != nil {
+		restriction.MinAdvanceDays = *input.MinAdvanceDays
+	}
+	if input.MaxAdvanceDays != nil {
+		restriction.MaxAdvanceDays = *input.MaxAdvanceDays
+	}
+	if input.Note != nil {
+		restriction.Note = *input.Note
+	}
+	return nil
+}
+
+// GetRestrictions: Mengambil semua batasan, filter ?room_type_id=, ?room_id= & ?property_id= (Admin Only)
+func (h *StayRestrictionHandler) GetRestrictions(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	pagination := &models.Pagination{
+		Page:   page,
+		Limit:  limit,
+		Sort:   c.Query("sort", "start_date asc"),
+		Offset: (page - 1) * limit,
+	}
+
+	propertyID, err := propertyScope(c)
+	if err != nil {
+		return respondStayRestrictionError(c, err)
+	}
+
+	restrictions, err := h.restrictionService.GetRestrictions(uint(c.QueryInt("room_type_id", 0)), uint(c.QueryInt("room_id", 0)), propertyID, pagination)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data batasan masa inap")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data batasan masa inap", fiber.Map{
+		"restrictions": restrictions,
+		"page":         page,
+		"limit":        limit,
+	})
+}
+
+// GetRestrictionByID: Mengambil detail batasan (Admin Only)
+func (h *StayRestrictionHandler) GetRestrictionByID(c *fiber.Ctx) error {
+	restrictionID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID batasan tidak valid")
+	}
+
+	restriction, err := h.restrictionService.GetRestrictionByID(uint(restrictionID))
+	if err != nil {
+		return respondStayRestrictionError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data batasan masa inap", restriction)
+}
+
+// CreateRestriction: Membuat batasan masa inap untuk tipe kamar atau kamar (Admin Only)
+func (h *StayRestrictionHandler) CreateRestriction(c *fiber.Ctx) error {
+	var input StayRestrictionInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	// Staf properti hanya boleh membuat batasan untuk tipe kamar/kamar propertinya (dicek di service)
+	restriction := &models.StayRestriction{
+		PropertyID: staffPropertyID(c),
+		RoomTypeID: input.RoomTypeID,
+		RoomID:     input.RoomID,
+	}
+	if err := applyStayRestrictionInput(restriction, input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	createdRestriction, err := h.restrictionService.CreateRestriction(restriction)
+	if err != nil {
+		return respondStayRestrictionError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Batasan masa inap berhasil dibuat", createdRestriction)
+}
+
+// UpdateRestriction: Mengubah batasan masa inap (Admin Only)
+func (h *StayRestrictionHandler) UpdateRestriction(c *fiber.Ctx) error {
+	restrictionID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID batasan tidak valid")
+	}
+
+	var input StayRestrictionInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	existingRestriction, err := h.restrictionService.GetRestrictionByID(uint(restrictionID))
+	if err != nil {
+		return respondStayRestrictionError(c, err)
+	}
+	if err := applyStayRestrictionInput(existingRestriction, input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	updatedRestriction, err := h.restrictionService.UpdateRestriction(existingRestriction)
+	if err != nil {
+		return respondStayRestrictionError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Batasan masa inap berhasil diubah", updatedRestriction)
+}
+
+// DeleteRestriction: Menghapus batasan masa inap (Admin Only)
+func (h *StayRestrictionHandler) DeleteRestriction(c *fiber.Ctx) error {
+	restrictionID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID batasan tidak valid")
+	}
+
+	if err := h.restrictionService.DeleteRestriction(uint(restrictionID)); err != nil {
+		return respondStayRestrictionError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Batasan masa inap berhasil dihapus", nil)
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/pkg/utils"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TaxFeeHandler struct {
+	taxFeeService services.TaxFeeService
+}
+
+func NewTaxFeeHandler(taxFeeService services.TaxFeeService) *TaxFeeHandler {
+	return &TaxFeeHandler{taxFeeService: taxFeeService}
+}
+
+type TaxFeeInput struct {
+	PropertyID uint         `json:"property_id"` // Hanya saat membuat; staf properti boleh mengosongkan
+	Code       string       `json:"code"`
+	Name       string       `json:"name"`
+	Kind       string       `json:"kind"`      // tax | fee
+	CalcType   string       `json:"calc_type"` // percentage | flat
+	Amount     *json.Number `json:"amount"`    // Persen (percentage) atau nominal (flat), angka/string desimal
+	Basis      string       `json:"basis"`     // per_night | per_stay
+	Inclusive  *bool        `json:"inclusive"`
+	IsActive   *bool        `json:"is_active"`
+	SortOrder  *int         `json:"sort_order"`
+}
+
+// applyTaxFeeInput: Menyalin field yang diberikan dari input ke pajak/biaya
+func applyTaxFeeInput(taxFee *models.TaxFee, input TaxFeeInput) error {
+	if input.Code != "" {
+		taxFee.Code = input.Code
+	}
+	if input.Name != "" {
+		taxFee.Name = input.Name
+	}
+	if input.Kind != "" {
+		taxFee.Kind = input.Kind
+	}
+	if input.CalcType != "" {
+		taxFee.CalcType = input.CalcType
+	}
+	if input.Amount != nil {
+		basisPoints, amount, err := parseRateOrAmount(*input.Amount, taxFee.CalcType == models.CalcPercentage)
+		if err != nil {
+			return err
+		}
+		taxFee.RateBasisPoints, taxFee.Amount = basisPoints, amount
+	}
+	if input.Basis != "" {
+		taxFee.Basis = input.Basis
+	}
+	if input.Inclusive != nil {
+		taxFee.Inclusive = *input.Inclusive
+	}
+	if input.IsActive != nil {
+		taxFee.IsActive = *input.IsActive
+	}
+	if input.SortOrder != nil {
+		taxFee.SortOrder = *input.SortOrder
+	}
+	return nil
+}
+
+// respondTaxFeeError: Memetakan error pajak/biaya ke HTTP status
+func respondTaxFeeError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, models.ErrTaxFeeNotFound), errors.Is(err, models.ErrPropertyNotFound):
+		return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrPropertyAccessDenied):
+		return utils.RespondError(c, fiber.StatusForbidden, err.Error())
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return utils.RespondError(c, fiber.StatusConflict, "Kode pajak/biaya sudah digunakan")
+	}
+	return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+}
+
+// RequireTaxFeeAccess: Middleware admin, staf properti hanya boleh mengelola pajak/biaya propertinya
+func (h *TaxFeeHandler) RequireTaxFeeAccess(c *fiber.Ctx) error {
+	return requirePropertyAccess(c, func(id uint) (uint, error) {
+		taxFee, err := h.taxFeeService.GetTaxFeeByID(id)
+		if err != nil {
+			return 0, err
+		}
+		return taxFee.PropertyID, nil
+	})
+}
+
+// GetTaxFees: Mengambil semua pajak & biaya, filter ?property_id= (Admin Only)
+func (h *TaxFeeHandler) GetTaxFees(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	pagination := &models.Pagination{
+		Page:   page,
+		Limit:  limit,
+		Sort:   c.Query("sort", "sort_order asc"),
+		Offset: (page - 1) * limit,
+	}
+
+	propertyID, err := propertyScope(c)
+	if err != nil {
+		return respondTaxFeeError(c, err)
+	}
+
+	taxFees, err := h.taxFeeService.GetTaxFees(propertyID, pagination)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal mengambil data pajak & biaya")
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data pajak & biaya", fiber.Map{
+		"tax_fees": taxFees,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// GetTaxFeeByID: Mengambil detail pajak/biaya (Admin Only)
+func (h *TaxFeeHandler) GetTaxFeeByID(c *fiber.Ctx) error {
+	taxFeeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pajak/biaya tidak valid")
+	}
+
+	taxFee, err := h.taxFeeService.GetTaxFeeByID(uint(taxFeeID))
+	if err != nil {
+		return respondTaxFeeError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Berhasil mengambil data pajak/biaya", taxFee)
+}
+
+// CreateTaxFee: Membuat pajak/biaya (Admin Only)
+func (h *TaxFeeHandler) CreateTaxFee(c *fiber.Ctx) error {
+	var input TaxFeeInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	propertyID, err := ownedProperty(c, input.PropertyID)
+	if err != nil {
+		return respondTaxFeeError(c, err)
+	}
+
+	taxFee := &models.TaxFee{PropertyID: propertyID, IsActive: true}
+	if err := applyTaxFeeInput(taxFee, input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	createdTaxFee, err := h.taxFeeService.CreateTaxFee(taxFee)
+	if err != nil {
+		return respondTaxFeeError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusCreated, "Pajak/biaya berhasil dibuat", createdTaxFee)
+}
+
+// UpdateTaxFee: Mengubah pajak/biaya (Admin Only)
+func (h *TaxFeeHandler) UpdateTaxFee(c *fiber.Ctx) error {
+	taxFeeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pajak/biaya tidak valid")
+	}
+
+	var input TaxFeeInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "Format request tidak valid")
+	}
+
+	existingTaxFee, err := h.taxFeeService.GetTaxFeeByID(uint(taxFeeID))
+	if err != nil {
+		return respondTaxFeeError(c, err)
+	}
+	if err := applyTaxFeeInput(existingTaxFee, input); err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	updatedTaxFee, err := h.taxFeeService.UpdateTaxFee(existingTaxFee)
+	if err != nil {
+		return respondTaxFeeError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Pajak/biaya berhasil diubah", updatedTaxFee)
+}
+
+// DeleteTaxFee: Menghapus pajak/biaya (Admin Only)
+func (h *TaxFeeHandler) DeleteTaxFee(c *fiber.Ctx) error {
+	taxFeeID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.RespondError(c, fiber.StatusBadRequest, "ID pajak/biaya tidak valid")
+	}
+
+	if err := h.taxFeeService.DeleteTaxFee(uint(taxFeeID)); err != nil {
+		return respondTaxFeeError(c, err)
+	}
+
+	return utils.RespondSuccess(c, fiber.StatusOK, "Pajak/biaya berhasil dihapus", nil)
+}
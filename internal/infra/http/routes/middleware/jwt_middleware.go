@@ -12,12 +12,21 @@ import (
 )
 
 const (
-	CtxUserIDKey = "user_id"
-	CtxRoleKey   = "user_role"
+	CtxUserIDKey     = "user_id"
+	CtxRoleKey       = "user_role"
+	CtxPropertyIDKey = "property_id"
+	CtxTokenIDKey    = "token_id"
+	CtxTokenExpKey   = "token_expires_at"
+	CtxMFAKey        = "mfa"
 )
 
-// JWTMiddleware: Validasi JWT Token
-func JWTMiddleware(cfg *config.Config) fiber.Handler {
+// RevocationList adalah daftar pencabutan access token berdasarkan jti (dipenuhi AuthService)
+type RevocationList interface {
+	IsTokenRevoked(jti string) (bool, error)
+}
+
+// JWTMiddleware: Validasi JWT Token dan daftar pencabutannya
+func JWTMiddleware(cfg *config.Config, revocations RevocationList) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
@@ -43,20 +52,43 @@ func JWTMiddleware(cfg *config.Config) fiber.Handler {
 		}
 
 		claims, ok := token.Claims.(*models.Claims)
-		if !ok {
+		if !ok || claims.ID == "" || claims.ExpiresAt == nil {
 			return utils.RespondError(c, fiber.StatusUnauthorized, "Token tidak dapat diproses")
 		}
 
+		// Token yang sudah logout / sesinya dicabut ditolak meskipun belum kedaluwarsa
+		revoked, err := revocations.IsTokenRevoked(claims.ID)
+		if err != nil {
+			return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal memeriksa token")
+		}
+		if revoked {
+			return utils.RespondError(c, fiber.StatusUnauthorized, models.ErrTokenRevoked.Error())
+		}
+
+		// Token hanya berlaku di tenant tempat user login
+		if tenantID, _ := c.Locals(CtxTenantIDKey).(uint); claims.TenantID != tenantID {
+			return utils.RespondError(c, fiber.StatusUnauthorized, models.ErrTenantMismatch.Error())
+		}
+
 		c.Locals(CtxUserIDKey, claims.UserID)
 		c.Locals(CtxRoleKey, claims.Role)
+		c.Locals(CtxPropertyIDKey, claims.PropertyID)
+		c.Locals(CtxTokenIDKey, claims.ID)
+		c.Locals(CtxTokenExpKey, claims.ExpiresAt.Time)
+		c.Locals(CtxMFAKey, claims.MFA)
 		c.Locals("userID", claims.UserID)
 		c.Locals("role", claims.Role)
+		c.Locals("propertyID", claims.PropertyID) // 0 = staf grup / member
+		c.Locals("tokenID", claims.ID)
+		c.Locals("tokenExpiresAt", claims.ExpiresAt.Time)
 
 		return c.Next()
 	}
 }
 
-// RoleMiddleware: Cek role user
+// RoleMiddleware: Cek role user.
+//
+// Deprecated: gunakan RequirePermission; nama role tidak lagi menentukan hak akses.
 func RoleMiddleware(allowedRoles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		role := c.Locals(CtxRoleKey).(string)
@@ -78,6 +110,6 @@ func RoleMiddleware(allowedRoles ...string) fiber.Handler {
 }
 
 // JWTProtected: Legacy function untuk backward compatibility
-func JWTProtected(cfg *config.Config) fiber.Handler {
-	return JWTMiddleware(cfg)
+func JWTProtected(cfg *config.Config, revocations RevocationList) fiber.Handler {
+	return JWTMiddleware(cfg, revocations)
 }
@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/domain/models"
+	"backend/internal/infra/http/routes/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// noRevocations adalah RevocationList tanpa token yang dicabut
+type noRevocations struct{}
+
+func (noRevocations) IsTokenRevoked(jti string) (bool, error) {
+	return false, nil
+}
+
+func TestJWTMiddlewareRejectsTokenFromOtherTenant(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "test-secret", TenantHeader: "X-Tenant", DefaultTenant: "main"}
+	app := newTenantApp(cfg, middleware.JWTMiddleware(cfg, noRevocations{}))
+
+	// Token diterbitkan saat login di tenant alpha (ID 1)
+	claims := models.Claims{
+		UserID:   7,
+		Role:     models.RoleMember,
+		TenantID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti-alpha",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.JWTSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		header     string
+		host       string
+		wantStatus int
+	}{
+		{name: "tenant yang sama lewat header", header: "alpha", wantStatus: fiber.StatusOK},
+		{name: "tenant yang sama lewat hostname", host: "booking.alpha.test", wantStatus: fiber.StatusOK},
+		{name: "tenant lain lewat header", header: "beta", wantStatus: fiber.StatusUnauthorized},
+		{name: "tenant lain lewat hostname", host: "booking.beta.test", wantStatus: fiber.StatusUnauthorized},
+		{name: "tenant default (tenant lain)", host: "localhost", wantStatus: fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			if tt.header != "" {
+				req.Header.Set("X-Tenant", tt.header)
+			}
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status %d, ingin %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
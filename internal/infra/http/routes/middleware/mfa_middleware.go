@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"backend/internal/config"
+	"backend/internal/domain/models"
+	"backend/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireMFA: Role yang wajib 2FA (MFA_REQUIRED_ROLES) hanya boleh lewat dengan token
+// yang login-nya diselesaikan dengan kode 2FA. Dipasang setelah JWTMiddleware.
+func RequireMFA(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals(CtxRoleKey).(string)
+		if mfa, _ := c.Locals(CtxMFAKey).(bool); !mfa && cfg.RequiresMFA(role) {
+			return utils.RespondError(c, fiber.StatusForbidden, models.ErrMFAEnrollmentRequired.Error())
+		}
+		return c.Next()
+	}
+}
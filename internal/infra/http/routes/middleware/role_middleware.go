@@ -1,11 +1,43 @@
 package middleware
 
 import (
+	"backend/internal/domain/models"
 	"backend/pkg/utils"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// PermissionChecker memeriksa izin sebuah role (dipenuhi RoleService)
+type PermissionChecker interface {
+	HasPermission(role, permission string) (bool, error)
+}
+
+// RequirePermission: Hanya role yang memiliki izin permission yang boleh lewat.
+// Dipasang setelah JWTMiddleware; izin dibaca saat request sehingga perubahan role langsung berlaku.
+func RequirePermission(checker PermissionChecker, permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, ok := c.Locals(CtxRoleKey).(string)
+		if !ok {
+			return utils.RespondError(c, fiber.StatusUnauthorized, "Akses ditolak: user belum terauthentikasi")
+		}
+
+		allowed, err := checker.HasPermission(role, permission)
+		if err != nil {
+			return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal memeriksa izin")
+		}
+		if !allowed {
+			return utils.RespondErrorWithData(c, fiber.StatusForbidden, models.ErrPermissionDenied.Error(), fiber.Map{
+				"permission": permission,
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// AuthorizeRole: Cek role user.
+//
+// Deprecated: gunakan RequirePermission; nama role tidak lagi menentukan hak akses.
 func AuthorizeRole(requiredRole string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		userRole, ok := c.Locals(CtxRoleKey).(string)
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/config"
+	"backend/internal/domain/models"
+	"backend/pkg/utils"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const CtxTenantIDKey = "tenant_id"
+
+// TenantMiddleware: Memetakan request ke tenant dari header tenant atau hostname
+func TenantMiddleware(tenantService services.TenantService, cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tenant, err := tenantService.ResolveTenant(c.Get(cfg.TenantHeader), c.Hostname())
+		if err != nil {
+			if errors.Is(err, models.ErrTenantNotFound) {
+				return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+			}
+			return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal memuat tenant")
+		}
+
+		c.Locals(CtxTenantIDKey, tenant.ID)
+
+		return c.Next()
+	}
+}
+
+// PaymentWebhookTenant: Memetakan webhook pembayaran ke tenant pemilik pembayarannya. Provider
+// mengirim semua webhook ke satu URL, sehingga tenant tidak bisa diambil dari header atau hostname.
+// paymentService harus memakai koneksi tanpa tenant agar pembayaran semua tenant dapat dicari.
+func PaymentWebhookTenant(paymentService services.PaymentService, signatureHeader string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tenantID, err := paymentService.WebhookTenant(c.Body(), c.Get(signatureHeader))
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrInvalidWebhookSignature):
+				return utils.RespondError(c, fiber.StatusUnauthorized, err.Error())
+			case errors.Is(err, models.ErrPaymentNotFound):
+				return utils.RespondError(c, fiber.StatusNotFound, err.Error())
+			}
+			return utils.RespondError(c, fiber.StatusInternalServerError, "Gagal memproses webhook pembayaran")
+		}
+
+		c.Locals(CtxTenantIDKey, tenantID)
+
+		return c.Next()
+	}
+}
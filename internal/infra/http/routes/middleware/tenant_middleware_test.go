@@ -0,0 +1,107 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/app/services"
+	"backend/internal/config"
+	"backend/internal/domain/models"
+	"backend/internal/infra/http/routes/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// stubTenantRepository adalah TenantRepository di memori
+type stubTenantRepository struct {
+	tenants []models.Tenant
+}
+
+func (r *stubTenantRepository) FindByCode(code string) (*models.Tenant, error) {
+	for i := range r.tenants {
+		if r.tenants[i].Code == code {
+			return &r.tenants[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubTenantRepository) FindByDomain(domain string) (*models.Tenant, error) {
+	for i := range r.tenants {
+		if r.tenants[i].Domain != nil && *r.tenants[i].Domain == domain {
+			return &r.tenants[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func newTenantRepository() *stubTenantRepository {
+	alphaDomain, betaDomain := "booking.alpha.test", "booking.beta.test"
+	return &stubTenantRepository{tenants: []models.Tenant{
+		{Model: gorm.Model{ID: 1}, Code: "alpha", Domain: &alphaDomain, IsActive: true},
+		{Model: gorm.Model{ID: 2}, Code: "beta", Domain: &betaDomain, IsActive: true},
+		{Model: gorm.Model{ID: 3}, Code: "main", IsActive: true},
+	}}
+}
+
+// newTenantApp memasang TenantMiddleware di depan handler yang mengembalikan tenant hasil resolusi
+func newTenantApp(cfg *config.Config, handlers ...fiber.Handler) *fiber.App {
+	app := fiber.New()
+	chain := append([]fiber.Handler{middleware.TenantMiddleware(services.NewTenantService(newTenantRepository(), cfg), cfg)}, handlers...)
+	chain = append(chain, func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"tenant_id": c.Locals(middleware.CtxTenantIDKey)})
+	})
+	app.Get("/", chain...)
+	return app
+}
+
+func TestTenantMiddlewareResolvesTenant(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultTenant string
+		header        string
+		host          string
+		wantStatus    int
+		wantTenant    uint
+	}{
+		{name: "dari header", defaultTenant: "main", header: "beta", host: "localhost", wantStatus: fiber.StatusOK, wantTenant: 2},
+		{name: "dari hostname", defaultTenant: "main", host: "booking.alpha.test", wantStatus: fiber.StatusOK, wantTenant: 1},
+		{name: "header diutamakan di atas hostname", defaultTenant: "main", header: "beta", host: "booking.alpha.test", wantStatus: fiber.StatusOK, wantTenant: 2},
+		{name: "hostname tidak dikenal memakai tenant default", defaultTenant: "main", host: "unknown.test", wantStatus: fiber.StatusOK, wantTenant: 3},
+		{name: "header tidak dikenal tidak jatuh ke tenant default", defaultTenant: "main", header: "gamma", host: "booking.alpha.test", wantStatus: fiber.StatusNotFound},
+		{name: "tanpa tenant default ditolak", host: "unknown.test", wantStatus: fiber.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTenantApp(&config.Config{TenantHeader: "X-Tenant", DefaultTenant: tt.defaultTenant})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			req.Host = tt.host
+			if tt.header != "" {
+				req.Header.Set("X-Tenant", tt.header)
+			}
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status %d, ingin %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != fiber.StatusOK {
+				return
+			}
+
+			var body struct {
+				TenantID uint `json:"tenant_id"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.TenantID != tt.wantTenant {
+				t.Fatalf("tenant %d, ingin %d", body.TenantID, tt.wantTenant)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package routes
 
 import (
+	"backend/internal/app/services"
 	"backend/internal/config"
+	"backend/internal/domain/models"
 	"backend/internal/infra/http/handlers"
 	"backend/internal/infra/http/routes/middleware"
 
@@ -12,10 +14,27 @@ func SetupRoutes(
 	app *fiber.App,
 	authHandler *handlers.AuthHandler,
 	roomHandler *handlers.RoomHandler,
+	roomTypeHandler *handlers.RoomTypeHandler,
 	bookingHandler *handlers.BookingHandler,
 	reviewHandler *handlers.ReviewHandler,
+	ratePlanHandler *handlers.RatePlanHandler,
+	promoCodeHandler *handlers.PromoCodeHandler,
+	taxFeeHandler *handlers.TaxFeeHandler,
+	exchangeRateHandler *handlers.ExchangeRateHandler,
+	paymentHandler *handlers.PaymentHandler,
+	cancellationPolicyHandler *handlers.CancellationPolicyHandler,
+	propertyHandler *handlers.PropertyHandler,
+	amenityHandler *handlers.AmenityHandler,
+	stayRestrictionHandler *handlers.StayRestrictionHandler,
+	mfaHandler *handlers.MFAHandler,
+	roleHandler *handlers.RoleHandler,
+	authService services.AuthService,
+	roleService services.RoleService,
 	cfg *config.Config,
 ) {
+	// Access token diverifikasi tanda tangannya lalu dicek ke daftar pencabutan (jti)
+	jwtMiddleware := middleware.JWTMiddleware(cfg, authService)
+
 	// Public Routes (Tanpa autentikasi)
 	public := app.Group("/api")
 
@@ -23,20 +42,58 @@ func SetupRoutes(
 	auth := public.Group("/auth")
 	auth.Post("/register", authHandler.Register)
 	auth.Post("/login", authHandler.Login)
+	auth.Post("/login/mfa", authHandler.VerifyMFALogin)
+	auth.Post("/refresh", authHandler.Refresh)
+	auth.Post("/logout", jwtMiddleware, authHandler.Logout)
+	auth.Post("/forgot-password", authHandler.ForgotPassword)
+	auth.Post("/reset-password", authHandler.ResetPassword)
+	auth.Get("/verify-email", authHandler.VerifyEmail)
+	auth.Post("/verify-email/resend", jwtMiddleware, authHandler.ResendVerification)
+
+	// Two-Factor Authentication Routes (Login Required - bisa diakses admin yang belum mendaftar 2FA)
+	mfa := auth.Group("/mfa", jwtMiddleware)
+	mfa.Get("", mfaHandler.GetStatus)
+	mfa.Post("/enroll", mfaHandler.Enroll)
+	mfa.Post("/confirm", mfaHandler.Confirm)
+	mfa.Post("/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+	mfa.Post("/disable", mfaHandler.Disable)
+
+	// Property Routes (Public - Daftar hotel aktif dalam grup)
+	properties := public.Group("/properties")
+	properties.Get("", propertyHandler.GetProperties)
+	properties.Get("/:id", propertyHandler.GetPropertyByID)
+
+	// Amenity Routes (Public - Pilihan filter fasilitas)
+	public.Get("/amenities", amenityHandler.GetAmenities)
 
 	// Room Routes (Public - Lihat dan Cari)
 	rooms := public.Group("/rooms")
 	rooms.Get("", roomHandler.GetAllRooms)
 	rooms.Get("/:id", roomHandler.GetRoomByID)
-	rooms.Post("/available", roomHandler.GetAvailableRooms)
+	rooms.Get("/:id/calendar", roomHandler.GetRoomCalendar)
+	// Deprecated: alias lama sebelum inventori per tipe kamar, gunakan /room-types
+	rooms.Post("/available", roomTypeHandler.GetAvailableRooms)
+	rooms.Post("/:id/quote", roomHandler.ResolveRoomType, roomTypeHandler.GetQuote)
+
+	// Room Type Routes (Public - Lihat, Cari Ketersediaan, dan Quote)
+	roomTypes := public.Group("/room-types")
+	roomTypes.Get("", roomTypeHandler.GetRoomTypes)
+	roomTypes.Get("/:id", roomTypeHandler.GetRoomTypeByID)
+	roomTypes.Post("/available", roomTypeHandler.GetAvailableRoomTypes)
+	roomTypes.Post("/:id/quote", roomTypeHandler.GetQuote)
 
 	// Review Routes (Public - Lihat)
 	reviews := public.Group("/reviews")
 	reviews.Get("/room/:roomId", reviewHandler.GetRoomReviews)
 	reviews.Get("/:id", reviewHandler.GetReviewByID)
 
+	// Payment Webhook (Public - diverifikasi dengan signature HMAC, bukan JWT). Aplikasi utama
+	// meneruskannya ke tenant pemilik pembayaran (middleware.PaymentWebhookTenant).
+	payments := public.Group("/payments")
+	payments.Post("/webhook", paymentHandler.HandleWebhook)
+
 	// Protected Routes (Memerlukan autentikasi)
-	protected := app.Group("/api", middleware.JWTMiddleware(cfg))
+	protected := app.Group("/api", jwtMiddleware)
 
 	// Member Routes
 	member := protected.Group("/member")
@@ -44,33 +101,158 @@ func SetupRoutes(
 	// Booking Routes (Member)
 	bookings := member.Group("/bookings")
 	bookings.Post("", bookingHandler.CreateBooking)
+	bookings.Post("/hold", bookingHandler.CreateHold)
+	bookings.Post("/:id/finalize", bookingHandler.FinalizeHold)
 	bookings.Get("", bookingHandler.GetMyBookings)
+	bookings.Get("/:id/cancellation", bookingHandler.PreviewCancellation)
+	bookings.Patch("/:id", bookingHandler.ModifyBooking)
+	bookings.Get("/:id/modifications", bookingHandler.GetMyBookingModifications)
 	bookings.Delete("/:id", bookingHandler.CancelBooking)
+	bookings.Post("/:id/payment-intent", paymentHandler.CreatePaymentIntent)
+	bookings.Get("/:id/payments", paymentHandler.GetMyPayments)
+
+	// Simulasi pembayaran lokal (provider mock dan PAYMENT_SIMULATION_ENABLED=true, jangan aktifkan di production)
+	if cfg.PaymentProvider == "mock" && cfg.PaymentSimulationEnabled {
+		member.Post("/payments/mock/:intentId/simulate", paymentHandler.SimulatePayment)
+	}
 
 	// Review Routes (Member)
 	memberReviews := member.Group("/reviews")
 	memberReviews.Post("", reviewHandler.CreateReview)
 
-	// Admin Routes
-	admin := protected.Group("/admin", middleware.RoleMiddleware("admin"))
+	// Admin/Staff Routes (role yang wajib 2FA harus login dengan kode 2FA). Hak akses dicek per
+	// route lewat izin role (lihat models.Permissions), bukan nama role; admin selalu lolos.
+	admin := protected.Group("/admin", middleware.RequireMFA(cfg))
+	can := func(permission string) fiber.Handler {
+		return middleware.RequirePermission(roleService, permission)
+	}
+
+	// Property & Building Management Routes (Admin; staf properti hanya propertinya sendiri)
+	adminProperties := admin.Group("/properties", can(models.PermPropertyManage))
+	adminProperties.Get("", propertyHandler.GetAllProperties)
+	adminProperties.Post("", propertyHandler.CreateProperty)
+	adminProperties.Put("/:id", propertyHandler.UpdateProperty)
+	adminProperties.Delete("/:id", propertyHandler.DeleteProperty)
+	adminProperties.Post("/:id/buildings", propertyHandler.CreateBuilding)
+	adminProperties.Put("/:id/buildings/:buildingId", propertyHandler.UpdateBuilding)
+	adminProperties.Delete("/:id/buildings/:buildingId", propertyHandler.DeleteBuilding)
+
+	// Staff Management Routes (penugasan properti, role, dan reset 2FA)
+	admin.Put("/users/:id/property", can(models.PermStaffManage), propertyHandler.AssignStaff)
+	admin.Put("/users/:id/role", can(models.PermStaffManage), roleHandler.AssignUserRole)
+	admin.Delete("/users/:id/mfa", can(models.PermStaffManage), mfaHandler.ResetUserMFA)
+
+	// Role & Permission Management Routes
+	admin.Get("/permissions", can(models.PermRoleManage), roleHandler.GetPermissions)
+	adminRoles := admin.Group("/roles", can(models.PermRoleManage))
+	adminRoles.Get("", roleHandler.GetRoles)
+	adminRoles.Post("", roleHandler.CreateRole)
+	adminRoles.Get("/:id", roleHandler.GetRoleByID)
+	adminRoles.Put("/:id", roleHandler.UpdateRole)
+	adminRoles.Delete("/:id", roleHandler.DeleteRole)
 
-	// Room Management Routes (Admin)
+	// Room Management Routes (status kamar punya izin sendiri untuk housekeeping)
 	adminRooms := admin.Group("/rooms")
-	adminRooms.Post("", roomHandler.CreateRoom)
-	adminRooms.Put("/:id", roomHandler.UpdateRoom)
-	adminRooms.Delete("/:id", roomHandler.DeleteRoom)
+	adminRooms.Post("", can(models.PermRoomEdit), roomHandler.CreateRoom)
+	adminRooms.Put("/:id", can(models.PermRoomEdit), roomHandler.RequireRoomAccess, roomHandler.UpdateRoom)
+	adminRooms.Put("/:id/status", can(models.PermRoomStatus), roomHandler.RequireRoomAccess, roomHandler.UpdateRoomStatus)
+	adminRooms.Delete("/:id", can(models.PermRoomEdit), roomHandler.RequireRoomAccess, roomHandler.DeleteRoom)
+	adminRooms.Put("/:id/amenities", can(models.PermRoomEdit), roomHandler.RequireRoomAccess, roomHandler.SetRoomAmenities)
 
-	// Room Image Management Routes (Admin)
-	adminRoomImages := admin.Group("/rooms/:id/images")
-	adminRoomImages.Post("", roomHandler.AddRoomImage)
-	adminRoomImages.Delete("/:imageId", roomHandler.DeleteRoomImage)
+	// Room Image Management Routes
+	adminRoomImages := admin.Group("/rooms/:id/images", can(models.PermRoomEdit))
+	adminRoomImages.Post("", roomHandler.RequireRoomAccess, roomHandler.AddRoomImage)
+	adminRoomImages.Delete("/:imageId", roomHandler.RequireRoomAccess, roomHandler.DeleteRoomImage)
 
-	// Booking Management Routes (Admin)
+	// Amenity Catalogue Routes
+	adminAmenities := admin.Group("/amenities", can(models.PermAmenityManage))
+	adminAmenities.Get("", amenityHandler.GetAmenities)
+	adminAmenities.Post("", amenityHandler.CreateAmenity)
+	adminAmenities.Put("/:id", amenityHandler.UpdateAmenity)
+	adminAmenities.Delete("/:id", amenityHandler.DeleteAmenity)
+
+	// Room Type Management Routes
+	adminRoomTypes := admin.Group("/room-types", can(models.PermRoomTypeEdit))
+	adminRoomTypes.Post("", roomTypeHandler.CreateRoomType)
+	adminRoomTypes.Put("/:id", roomTypeHandler.RequireRoomTypeAccess, roomTypeHandler.UpdateRoomType)
+	adminRoomTypes.Delete("/:id", roomTypeHandler.RequireRoomTypeAccess, roomTypeHandler.DeleteRoomType)
+
+	// Room Board (penempatan kamar fisik per tanggal)
+	admin.Get("/room-board", can(models.PermBookingView), bookingHandler.GetRoomBoard)
+
+	// Booking & Payment Routes (izin per route karena front desk, housekeeping, dan accounting berbeda)
 	adminBookings := admin.Group("/bookings")
-	adminBookings.Get("", bookingHandler.GetAllBookings)
-	adminBookings.Put("/:id/payment-status", bookingHandler.UpdatePaymentStatus)
+	adminBookings.Get("", can(models.PermBookingView), bookingHandler.GetAllBookings)
+	adminBookings.Get("/:id/payments", can(models.PermPaymentView), bookingHandler.RequireBookingAccess, paymentHandler.GetPayments)
+	adminBookings.Post("/:id/payments", can(models.PermPaymentRecord), bookingHandler.RequireBookingAccess, paymentHandler.RecordPayment)
+	adminBookings.Post("/:id/payments/capture", can(models.PermPaymentRecord), bookingHandler.RequireBookingAccess, paymentHandler.CapturePayment)
+	adminBookings.Post("/:id/payments/refund", can(models.PermPaymentRefund), bookingHandler.RequireBookingAccess, paymentHandler.RefundPayment)
+
+	// Booking Lifecycle Routes
+	adminBookings.Get("/:id/transitions", can(models.PermBookingView), bookingHandler.RequireBookingAccess, bookingHandler.GetBookingTransitions)
+	adminBookings.Get("/:id/modifications", can(models.PermBookingView), bookingHandler.RequireBookingAccess, bookingHandler.GetBookingModifications)
+	adminBookings.Post("/:id/confirm", can(models.PermBookingManage), bookingHandler.RequireBookingAccess, bookingHandler.ConfirmBooking)
+	adminBookings.Post("/:id/check-in", can(models.PermBookingCheckIn), bookingHandler.RequireBookingAccess, bookingHandler.CheckIn)
+	adminBookings.Put("/:id/room", can(models.PermBookingCheckIn), bookingHandler.RequireBookingAccess, bookingHandler.AssignRoom)
+	adminBookings.Post("/:id/check-out", can(models.PermBookingCheckIn), bookingHandler.RequireBookingAccess, bookingHandler.CheckOut)
+	adminBookings.Post("/:id/complete", can(models.PermBookingManage), bookingHandler.RequireBookingAccess, bookingHandler.CompleteBooking)
+	adminBookings.Post("/:id/no-show", can(models.PermBookingManage), bookingHandler.RequireBookingAccess, bookingHandler.MarkNoShow)
+	adminBookings.Post("/:id/cancel", can(models.PermBookingManage), bookingHandler.RequireBookingAccess, bookingHandler.AdminCancelBooking)
+
+	// Rate Plan Management Routes
+	adminRatePlans := admin.Group("/rate-plans", can(models.PermRateManage))
+	adminRatePlans.Get("", ratePlanHandler.GetRatePlans)
+	adminRatePlans.Post("", ratePlanHandler.CreateRatePlan)
+	adminRatePlans.Get("/:id", ratePlanHandler.RequireRatePlanAccess, ratePlanHandler.GetRatePlanByID)
+	adminRatePlans.Put("/:id", ratePlanHandler.RequireRatePlanAccess, ratePlanHandler.UpdateRatePlan)
+	adminRatePlans.Delete("/:id", ratePlanHandler.RequireRatePlanAccess, ratePlanHandler.DeleteRatePlan)
+	adminRatePlans.Post("/:id/seasons", ratePlanHandler.RequireRatePlanAccess, ratePlanHandler.AddSeason)
+	adminRatePlans.Delete("/:id/seasons/:seasonId", ratePlanHandler.RequireRatePlanAccess, ratePlanHandler.DeleteSeason)
+	adminRatePlans.Put("/:id/date-prices", ratePlanHandler.RequireRatePlanAccess, ratePlanHandler.SetDatePrice)
+	adminRatePlans.Delete("/:id/date-prices/:datePriceId", ratePlanHandler.RequireRatePlanAccess, ratePlanHandler.DeleteDatePrice)
+	adminRatePlans.Post("/:id/blackouts", ratePlanHandler.RequireRatePlanAccess, ratePlanHandler.AddBlackout)
+	adminRatePlans.Delete("/:id/blackouts/:blackoutId", ratePlanHandler.RequireRatePlanAccess, ratePlanHandler.DeleteBlackout)
+
+	// Stay Restriction Management Routes (min/max LOS, CTA/CTD, jendela pemesanan)
+	adminStayRestrictions := admin.Group("/stay-restrictions", can(models.PermRateManage))
+	adminStayRestrictions.Get("", stayRestrictionHandler.GetRestrictions)
+	adminStayRestrictions.Post("", stayRestrictionHandler.CreateRestriction)
+	adminStayRestrictions.Get("/:id", stayRestrictionHandler.RequireStayRestrictionAccess, stayRestrictionHandler.GetRestrictionByID)
+	adminStayRestrictions.Put("/:id", stayRestrictionHandler.RequireStayRestrictionAccess, stayRestrictionHandler.UpdateRestriction)
+	adminStayRestrictions.Delete("/:id", stayRestrictionHandler.RequireStayRestrictionAccess, stayRestrictionHandler.DeleteRestriction)
+
+	// Promo Code Management Routes
+	adminPromoCodes := admin.Group("/promo-codes", can(models.PermPromoManage))
+	adminPromoCodes.Get("", promoCodeHandler.GetPromoCodes)
+	adminPromoCodes.Post("", promoCodeHandler.CreatePromoCode)
+	adminPromoCodes.Get("/:id", promoCodeHandler.GetPromoCodeByID)
+	adminPromoCodes.Put("/:id", promoCodeHandler.UpdatePromoCode)
+	adminPromoCodes.Delete("/:id", promoCodeHandler.DeletePromoCode)
+	adminPromoCodes.Get("/:id/stats", promoCodeHandler.GetPromoCodeStats)
+
+	// Tax & Fee Management Routes
+	adminTaxFees := admin.Group("/tax-fees", can(models.PermTaxFeeManage))
+	adminTaxFees.Get("", taxFeeHandler.GetTaxFees)
+	adminTaxFees.Post("", taxFeeHandler.CreateTaxFee)
+	adminTaxFees.Get("/:id", taxFeeHandler.RequireTaxFeeAccess, taxFeeHandler.GetTaxFeeByID)
+	adminTaxFees.Put("/:id", taxFeeHandler.RequireTaxFeeAccess, taxFeeHandler.UpdateTaxFee)
+	adminTaxFees.Delete("/:id", taxFeeHandler.RequireTaxFeeAccess, taxFeeHandler.DeleteTaxFee)
+
+	// Exchange Rate Management Routes
+	adminExchangeRates := admin.Group("/exchange-rates", can(models.PermExchangeRateManage))
+	adminExchangeRates.Get("", exchangeRateHandler.GetExchangeRates)
+	adminExchangeRates.Put("/:currency", exchangeRateHandler.SetExchangeRate)
+	adminExchangeRates.Delete("/:currency", exchangeRateHandler.DeleteExchangeRate)
+
+	// Cancellation Policy Management Routes
+	adminCancellationPolicies := admin.Group("/cancellation-policies", can(models.PermCancellationPolicyManage))
+	adminCancellationPolicies.Get("", cancellationPolicyHandler.GetCancellationPolicies)
+	adminCancellationPolicies.Post("", cancellationPolicyHandler.CreateCancellationPolicy)
+	adminCancellationPolicies.Get("/:id", cancellationPolicyHandler.GetCancellationPolicyByID)
+	adminCancellationPolicies.Put("/:id", cancellationPolicyHandler.UpdateCancellationPolicy)
+	adminCancellationPolicies.Delete("/:id", cancellationPolicyHandler.DeleteCancellationPolicy)
 
-	// Review Management Routes (Admin)
-	adminReviews := admin.Group("/reviews")
+	// Review Moderation Routes
+	adminReviews := admin.Group("/reviews", can(models.PermReviewModerate))
 	adminReviews.Delete("/:id", reviewHandler.DeleteReview)
 }
@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"backend/internal/domain/models"
+	"backend/internal/infra/http/routes/middleware"
+	"backend/pkg/utils"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// TenantRouter meneruskan request ke aplikasi Fiber milik tenant hasil TenantMiddleware.
+// Aplikasi tiap tenant (repository dengan koneksi ter-scope tenant, service, handler, dan routes)
+// dibangun sekali pada request pertama tenant tersebut lalu dipakai ulang.
+type TenantRouter struct {
+	build func(tenantID uint) *fiber.App
+
+	mu   sync.Mutex
+	apps map[uint]fasthttp.RequestHandler
+}
+
+func NewTenantRouter(build func(tenantID uint) *fiber.App) *TenantRouter {
+	return &TenantRouter{build: build, apps: make(map[uint]fasthttp.RequestHandler)}
+}
+
+// Handle: Handler terakhir aplikasi utama, dipasang setelah TenantMiddleware
+func (r *TenantRouter) Handle(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals(middleware.CtxTenantIDKey).(uint)
+	if !ok {
+		return utils.RespondError(c, fiber.StatusNotFound, models.ErrTenantNotFound.Error())
+	}
+
+	// Locals disimpan di request fasthttp sehingga tetap terbaca oleh aplikasi tenant
+	r.handler(tenantID)(c.Context())
+	return nil
+}
+
+func (r *TenantRouter) handler(tenantID uint) fasthttp.RequestHandler {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	handler, ok := r.apps[tenantID]
+	if !ok {
+		handler = r.build(tenantID).Handler()
+		r.apps[tenantID] = handler
+	}
+	return handler
+}
@@ -0,0 +1,36 @@
+package mail
+
+import (
+	"backend/internal/app/services"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// fileSender menulis setiap email sebagai file .eml di folder outbox, untuk development lokal
+// tanpa server SMTP. File bisa dibuka dengan email client mana pun.
+type fileSender struct {
+	dir  string
+	from string
+}
+
+func NewFileSender(dir, from string) services.MailSender {
+	return &fileSender{dir: dir, from: from}
+}
+
+func (s *fileSender) Send(msg services.MailMessage) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405"), hex.EncodeToString(suffix))
+	return os.WriteFile(filepath.Join(s.dir, name), buildMessage(s.from, msg, now), 0o600)
+}
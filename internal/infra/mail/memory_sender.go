@@ -0,0 +1,31 @@
+package mail
+
+import (
+	"backend/internal/app/services"
+	"sync"
+)
+
+// MemorySender menyimpan email di memori alih-alih mengirimnya, untuk pengujian
+// (token di dalam email bisa dibaca langsung lewat Messages)
+type MemorySender struct {
+	mu       sync.Mutex
+	messages []services.MailMessage
+}
+
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+func (s *MemorySender) Send(msg services.MailMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+// Messages mengembalikan salinan semua email yang sudah "dikirim", urut dari yang terlama
+func (s *MemorySender) Messages() []services.MailMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]services.MailMessage(nil), s.messages...)
+}
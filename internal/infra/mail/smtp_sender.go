@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"backend/internal/app/services"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// smtpSender mengirim email lewat server SMTP (STARTTLS otomatis bila didukung server).
+// Tanpa username, email dikirim tanpa autentikasi (mis. relay internal).
+type smtpSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPSender(host, port, username, password, from string) services.MailSender {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpSender{addr: net.JoinHostPort(host, port), auth: auth, from: from}
+}
+
+func (s *smtpSender) Send(msg services.MailMessage) error {
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{msg.To}, buildMessage(s.from, msg, time.Now())); err != nil {
+		return fmt.Errorf("gagal mengirim email ke %s: %w", msg.To, err)
+	}
+	return nil
+}
+
+// buildMessage menyusun email teks biasa (RFC 5322) beserta header-nya
+func buildMessage(from string, msg services.MailMessage, now time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + headerValue(from) + "\r\n")
+	b.WriteString("To: " + headerValue(msg.To) + "\r\n")
+	b.WriteString("Subject: " + headerValue(msg.Subject) + "\r\n")
+	b.WriteString("Date: " + now.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// headerValue membuang CR/LF agar input pengguna (mis. alamat email) tidak bisa menyisipkan header
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
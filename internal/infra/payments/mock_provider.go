@@ -0,0 +1,132 @@
+package payments
+
+import (
+	"backend/internal/app/services"
+	"backend/internal/domain/models"
+	"backend/internal/domain/money"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// webhookTolerance adalah selisih waktu maksimal signature webhook (proteksi replay)
+const webhookTolerance = 5 * time.Minute
+
+// mockProvider adalah payment provider lokal tanpa gateway sungguhan. Intent, capture, dan refund
+// selalu berhasil; status pembayaran berubah lewat webhook yang ditandatangani HMAC-SHA256
+// dengan format header "t=<unix>,v1=<hex>" (sama seperti gateway pada umumnya).
+type mockProvider struct {
+	secret []byte
+	clock  services.Clock
+}
+
+func NewMockProvider(webhookSecret string, clock services.Clock) services.PaymentProvider {
+	return &mockProvider{secret: []byte(webhookSecret), clock: clock}
+}
+
+type mockWebhookPayload struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		IntentID string      `json:"intent_id"`
+		RefundID string      `json:"refund_id,omitempty"`
+		Amount   money.Money `json:"amount"`
+	} `json:"data"`
+}
+
+func (p *mockProvider) Name() string {
+	return "mock"
+}
+
+func (p *mockProvider) CreateIntent(reference string, amount money.Money) (*services.PaymentIntent, error) {
+	id := "pi_mock_" + randomHex(12)
+	return &services.PaymentIntent{
+		ID:           id,
+		Provider:     p.Name(),
+		Amount:       amount,
+		Status:       services.IntentRequiresPayment,
+		ClientSecret: id + "_secret_" + randomHex(8),
+	}, nil
+}
+
+func (p *mockProvider) Capture(intentID string, amount money.Money) (*services.PaymentIntent, error) {
+	return &services.PaymentIntent{ID: intentID, Provider: p.Name(), Amount: amount, Status: services.IntentSucceeded}, nil
+}
+
+func (p *mockProvider) Refund(intentID string, amount money.Money) (*services.PaymentRefund, error) {
+	return &services.PaymentRefund{ID: "re_mock_" + randomHex(12), IntentID: intentID, Amount: amount, Status: services.RefundSucceeded}, nil
+}
+
+func (p *mockProvider) VerifyWebhook(payload []byte, signature string) (*services.PaymentEvent, error) {
+	var timestamp int64
+	var provided string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp, _ = strconv.ParseInt(value, 10, 64)
+		case "v1":
+			provided = value
+		}
+	}
+	if timestamp == 0 || provided == "" {
+		return nil, models.ErrInvalidWebhookSignature
+	}
+
+	age := p.clock.Now().Sub(time.Unix(timestamp, 0))
+	if age > webhookTolerance || age < -webhookTolerance {
+		return nil, models.ErrInvalidWebhookSignature
+	}
+	expected, err := hex.DecodeString(provided)
+	if err != nil || !hmac.Equal(expected, p.sign(timestamp, payload)) {
+		return nil, models.ErrInvalidWebhookSignature
+	}
+
+	var body mockWebhookPayload
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, fmt.Errorf("payload webhook tidak valid: %w", err)
+	}
+	return &services.PaymentEvent{ID: body.ID, Type: body.Type, IntentID: body.Data.IntentID, RefundID: body.Data.RefundID, Amount: body.Data.Amount}, nil
+}
+
+func (p *mockProvider) SimulateWebhook(eventType, intentID string, amount money.Money) ([]byte, string, error) {
+	var body mockWebhookPayload
+	body.ID = "evt_mock_" + randomHex(12)
+	body.Type = eventType
+	body.Data.IntentID = intentID
+	body.Data.Amount = amount
+	if eventType == models.RefundEventSucceeded {
+		// Seperti refund yang dibuat langsung dari dashboard gateway
+		body.Data.RefundID = "re_mock_" + randomHex(12)
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", err
+	}
+	timestamp := p.clock.Now().Unix()
+	signature := fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(p.sign(timestamp, payload)))
+	return payload, signature, nil
+}
+
+// sign menghitung HMAC-SHA256 atas "<timestamp>.<payload>"
+func (p *mockProvider) sign(timestamp int64, payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -26,3 +26,12 @@ func RespondError(c *fiber.Ctx, status int, message string) error {
 		Data:    nil,
 	})
 }
+
+// RespondErrorWithData mengirim response error beserta detail terstruktur (mis. kode pelanggaran)
+func RespondErrorWithData(c *fiber.Ctx, status int, message string, data interface{}) error {
+	return c.Status(status).JSON(Response{
+		Success: false,
+		Message: message,
+		Data:    data,
+	})
+}